@@ -30,13 +30,63 @@
 type ResponseType int
 
 const (
+	// NextSteps is the response to a hook that is invoked after a command
+	// was executed. Its Template contains the "next steps" that are printed
+	// after the output of the command.
 	NextSteps ResponseType = 0
+
+	// Allow is a response to a [PreCommand] hook, which allows the command
+	// to be executed.
+	Allow ResponseType = 1
+
+	// Deny is a response to a [PreCommand] hook, which prevents the command
+	// from being executed. Its Template contains the reason, which is
+	// printed as part of the error.
+	Deny ResponseType = 2
+
+	// Warn is a response to a hook, which prints the warning in its
+	// Template to stderr. When responding to a [PreCommand] hook, the
+	// command is executed after printing the warning.
+	Warn ResponseType = 3
+
+	// Annotations is a response to a hook that is invoked after a command
+	// was executed. The key/value pairs in its Annotations are added to
+	// the telemetry span of the command.
+	Annotations ResponseType = 4
+
+	// SuggestCommand is a response to a hook that is invoked after a command
+	// was executed. It suggests the command in its Command as a follow-up,
+	// which is executed if the user confirms it. Its Template, if set,
+	// is printed above the confirmation prompt, which always shows the
+	// command.
+	SuggestCommand ResponseType = 5
+)
+
+// Stage is the stage of the command execution at which a hook is invoked.
+type Stage string
+
+const (
+	// PostCommand hooks are invoked after a command was executed, and
+	// respond with [NextSteps], [Warn], [Annotations], or [SuggestCommand].
+	// Post-command hooks are configured with the "hooks" and "error-hooks"
+	// options of the plugin.
+	PostCommand Stage = ""
+
+	// PreCommand hooks are invoked before a command is executed, and
+	// respond with [Allow], [Deny], or [Warn]. Pre-command hooks are
+	// configured with the "pre-hooks" option of the plugin.
+	PreCommand Stage = "pre"
 )
 
 // Request is the type representing the information
 // that plugins declaring support for hooks get passed when
-// being invoked following a CLI command execution.
+// being invoked before or following a CLI command execution.
 type Request struct {
+	// Stage is the stage of the command execution at which the hook
+	// is invoked. It is empty ([PostCommand]) for hooks that are invoked
+	// after the command was executed.
+	Stage Stage `json:"Stage,omitzero"`
+
 	// RootCmd is a string representing the matching hook configuration
 	// which is currently being invoked. If a hook for "docker context"
 	// is configured and the user executes "docker context ls", the plugin
@@ -49,7 +99,9 @@ type Request struct {
 	//
 	// Flag values are not included and are set to an empty string,
 	// except for boolean flags known to the CLI itself, for which
-	// the value is either "true", or "false".
+	// the value is either "true", or "false". Values of other flags
+	// known to the CLI are only included for the flags that are listed
+	// in the "hook-flag-values" option of the plugin.
 	//
 	// Plugins can use this information to adjust their [Response]
 	// based on whether the command triggering the hook was invoked
@@ -57,8 +109,13 @@ type Request struct {
 	Flags map[string]string `json:"Flags,omitzero"`
 
 	// CommandError is a string containing the error output (if any)
-	// of the command for which the hook was invoked.
+	// of the command for which the hook was invoked. It is always empty
+	// for [PreCommand] hooks.
 	CommandError string `json:"CommandError,omitzero"`
+
+	// Context is the name of the Docker context the command for which the
+	// hook was invoked is executed with.
+	Context string `json:"Context,omitzero"`
 }
 
 // Response represents a plugin hook response. Plugins
@@ -68,6 +125,16 @@ type Request struct {
 type Response struct {
 	Type     ResponseType `json:"Type"`
 	Template string       `json:"Template,omitzero"`
+
+	// Annotations contains the key/value pairs of an [Annotations]
+	// response. Keys are prefixed with "hook.<plugin name>." when
+	// added to the telemetry span.
+	Annotations map[string]string `json:"Annotations,omitzero"`
+
+	// Command contains the command and its arguments, without the
+	// leading "docker", of a [SuggestCommand] response; for example,
+	// ["image", "prune", "--all"].
+	Command []string `json:"Command,omitzero"`
 }
 
 // HookType is the type of response from the plugin.
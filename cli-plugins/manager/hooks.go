@@ -8,15 +8,24 @@
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/docker/cli/cli-plugins/hooks"
 	"github.com/docker/cli/cli/config"
 	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/internal/prompt"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // HookPluginData is the type representing the information
@@ -31,29 +40,144 @@
 // RunCLICommandHooks is the entrypoint into the hooks execution flow after
 // a main CLI command was executed. It calls the hook subcommand for all
 // present CLI plugins that declare support for hooks in their metadata and
-// parses/prints their responses.
-func RunCLICommandHooks(ctx context.Context, dockerCLI config.Provider, rootCmd, subCommand *cobra.Command, cmdErrorMessage string) {
+// parses/prints their responses. Annotations that plugins respond with are
+// added to the span in ctx, if any.
+func RunCLICommandHooks(ctx context.Context, dockerCLI config.Provider, rootCmd, subCommand *cobra.Command, cmdErrorMessage string, opts ...HookOption) {
 	commandName := strings.TrimPrefix(subCommand.CommandPath(), rootCmd.Name()+" ")
 	flags := getCommandFlags(subCommand)
 
-	runHooks(ctx, dockerCLI.ConfigFile(), rootCmd, subCommand, commandName, flags, cmdErrorMessage)
+	runHooks(ctx, dockerCLI.ConfigFile(), rootCmd, subCommand, commandName, flags, cmdErrorMessage, newHookOptions(subCommand, opts))
 }
 
 // RunPluginHooks is the entrypoint for the hooks execution flow
 // after a plugin command was just executed by the CLI.
-func RunPluginHooks(ctx context.Context, dockerCLI config.Provider, rootCmd, subCommand *cobra.Command, args []string, cmdErrorMessage string) {
+func RunPluginHooks(ctx context.Context, dockerCLI config.Provider, rootCmd, subCommand *cobra.Command, args []string, cmdErrorMessage string, opts ...HookOption) {
 	commandName := strings.Join(args, " ")
 	flags := getNaiveFlags(args)
 
-	runHooks(ctx, dockerCLI.ConfigFile(), rootCmd, subCommand, commandName, flags, cmdErrorMessage)
+	runHooks(ctx, dockerCLI.ConfigFile(), rootCmd, subCommand, commandName, flags, cmdErrorMessage, newHookOptions(subCommand, opts))
 }
 
-func runHooks(ctx context.Context, cfg *configfile.ConfigFile, rootCmd, subCommand *cobra.Command, invokedCommand string, flags map[string]string, cmdErrorMessage string) {
-	nextSteps := invokeAndCollectHooks(ctx, cfg, rootCmd, subCommand, invokedCommand, flags, cmdErrorMessage)
-	hooks.PrintNextSteps(subCommand.ErrOrStderr(), nextSteps)
+// RunPreCommandHooks is the entrypoint into the hooks execution flow before
+// a main CLI command is executed. It calls the hook subcommand for all CLI
+// plugins that declare a pre-command hook for the command, and prints the
+// warnings they respond with. An error is returned if a plugin denies the
+// command from being executed.
+//
+// The flags of the command are parsed from args, as the command is not
+// executed yet.
+func RunPreCommandHooks(ctx context.Context, dockerCLI config.Provider, rootCmd, subCommand *cobra.Command, args []string, opts ...HookOption) error {
+	commandName := strings.TrimPrefix(subCommand.CommandPath(), rootCmd.Name()+" ")
+	flags := parseCommandFlags(subCommand, args)
+
+	return runPreHooks(ctx, dockerCLI.ConfigFile(), rootCmd, subCommand, commandName, flags, newHookOptions(subCommand, opts))
+}
+
+// RunPrePluginHooks is the entrypoint for the hooks execution flow
+// before a plugin command is executed by the CLI.
+func RunPrePluginHooks(ctx context.Context, dockerCLI config.Provider, rootCmd, subCommand *cobra.Command, args []string, opts ...HookOption) error {
+	commandName := strings.Join(args, " ")
+	flags := getNaiveFlags(args)
+
+	return runPreHooks(ctx, dockerCLI.ConfigFile(), rootCmd, subCommand, commandName, flags, newHookOptions(subCommand, opts))
+}
+
+// HookOption configures how plugin hooks are run.
+type HookOption func(*hookOptions)
+
+type hookOptions struct {
+	out            io.Writer
+	nonInteractive bool
+	contextName    string
+}
+
+// WithHookOutput writes the output of plugin hooks, such as warnings and
+// next steps, to out instead of to the standard error of the command.
+func WithHookOutput(out io.Writer) HookOption {
+	return func(o *hookOptions) {
+		o.out = out
+	}
+}
+
+// WithNonInteractive runs plugin hooks without prompting the user. Commands
+// that are suggested by plugins are not executed.
+func WithNonInteractive() HookOption {
+	return func(o *hookOptions) {
+		o.nonInteractive = true
+	}
+}
+
+// WithContextName sets the name of the Docker context the command is executed
+// with, which is passed to plugins in the Context field of [hooks.Request].
+func WithContextName(name string) HookOption {
+	return func(o *hookOptions) {
+		o.contextName = name
+	}
+}
+
+func newHookOptions(subCommand *cobra.Command, opts []HookOption) hookOptions {
+	o := hookOptions{out: subCommand.ErrOrStderr()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// defaultHookTimeout is the time after which a plugin hook is cancelled,
+// unless the plugin configures a different timeout with "hook-timeout".
+const defaultHookTimeout = 10 * time.Second
+
+// hooksDeadline is the time after which the hooks that are invoked before, or
+// after, a command are cancelled, regardless of the timeouts of the plugins,
+// to bound the delay that plugins add to a command. It is a variable so that
+// it can be changed in tests.
+var hooksDeadline = 20 * time.Second
+
+// hookTimeout returns the timeout for invoking the hook of a plugin, as
+// configured with the "hook-timeout" option of the plugin.
+func hookTimeout(pluginCfg map[string]string) time.Duration {
+	if v, ok := pluginCfg["hook-timeout"]; ok {
+		timeout, err := time.ParseDuration(v)
+		if err == nil && timeout > 0 {
+			return timeout
+		}
+		logrus.WithField("hook-timeout", v).Debug("Invalid plugin hook timeout; using the default timeout")
+	}
+	return defaultHookTimeout
+}
+
+func runPreHooks(ctx context.Context, cfg *configfile.ConfigFile, rootCmd, subCommand *cobra.Command, invokedCommand string, flags map[string]string, opts hookOptions) error {
+	responses := invokePreCommandHooks(ctx, cfg, rootCmd, subCommand, invokedCommand, flags, opts.contextName)
+
+	var errs []error
+	for _, r := range responses {
+		switch r.response {
+		case hooks.Warn:
+			_, _ = fmt.Fprintln(opts.out, "WARNING: "+r.message)
+		case hooks.Deny:
+			msg := fmt.Sprintf("docker %s: denied by the %s plugin", invokedCommand, r.pluginName)
+			if r.message != "" {
+				msg += ": " + r.message
+			}
+			errs = append(errs, errors.New(msg))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// preCommandResponse is the processed response of a pre-command hook.
+type preCommandResponse struct {
+	pluginName string
+	response   hooks.ResponseType
+	message    string
 }
 
-func invokeAndCollectHooks(ctx context.Context, cfg *configfile.ConfigFile, rootCmd, subCmd *cobra.Command, subCmdStr string, flags map[string]string, cmdErrorMessage string) []string {
+// invokePreCommandHooks invokes the pre-command hooks of the plugins that
+// declare a pre-command hook for the command. Plugins are invoked in order of
+// their name, and plugins that fail or respond with an invalid response are
+// skipped, unless the plugin sets the "pre-hooks-fail" option to "deny", in
+// which case the failure denies the command.
+func invokePreCommandHooks(ctx context.Context, cfg *configfile.ConfigFile, rootCmd, subCmd *cobra.Command, subCmdStr string, flags map[string]string, contextName string) []preCommandResponse {
 	if ctx.Err() != nil {
 		return nil
 	}
@@ -63,70 +187,259 @@ func invokeAndCollectHooks(ctx context.Context, cfg *configfile.ConfigFile, root
 		return nil
 	}
 
+	ctx, cancel := context.WithTimeout(ctx, hooksDeadline)
+	defer cancel()
+
 	pluginDirs := getPluginDirs(cfg)
-	nextSteps := make([]string, 0, len(pluginsCfg))
+	responses := make([]preCommandResponse, 0, len(pluginsCfg))
 
-	tryInvokeHook := func(pluginName string, pluginCfg map[string]string) (messages []string, ok bool, err error) {
-		match, matched := pluginMatch(pluginCfg, subCmdStr, cmdErrorMessage)
+	tryInvokeHook := func(pluginName string, pluginCfg map[string]string) (preCommandResponse, bool, error) {
+		match, matched := matchHookConfig(pluginCfg["pre-hooks"], subCmdStr)
 		if !matched {
-			return nil, false, nil
+			return preCommandResponse{}, false, nil
 		}
 
 		p, err := getPlugin(pluginName, pluginDirs, rootCmd)
 		if err != nil {
-			return nil, false, err
+			return preCommandResponse{}, false, err
 		}
 
-		resp, err := p.RunHook(ctx, hooks.Request{
-			RootCmd:      match,
-			Flags:        flags,
-			CommandError: cmdErrorMessage,
+		hookCtx, cancel := context.WithTimeout(ctx, hookTimeout(pluginCfg))
+		defer cancel()
+		resp, err := p.RunHook(hookCtx, hooks.Request{
+			Stage:   hooks.PreCommand,
+			RootCmd: match,
+			Flags:   flagsForPlugin(subCmd, flags, pluginCfg),
+			Context: contextName,
 		})
 		if err != nil {
-			return nil, false, err
+			return preCommandResponse{}, false, err
 		}
 
 		var message hooks.Response
 		if err := json.Unmarshal(resp, &message); err != nil {
-			return nil, false, fmt.Errorf("failed to unmarshal hook response (%q): %w", string(resp), err)
+			return preCommandResponse{}, false, fmt.Errorf("failed to unmarshal hook response (%q): %w", string(resp), err)
 		}
 
-		// currently the only hook type
-		if message.Type != hooks.NextSteps {
-			return nil, false, errors.New("unexpected hook response type: " + strconv.Itoa(int(message.Type)))
+		switch message.Type {
+		case hooks.Allow:
+			return preCommandResponse{pluginName: pluginName, response: message.Type}, true, nil
+		case hooks.Deny, hooks.Warn:
+		default:
+			return preCommandResponse{}, false, errors.New("unexpected pre-command hook response type: " + strconv.Itoa(int(message.Type)))
 		}
 
-		messages, err = hooks.ParseTemplate(message.Template, subCmd)
+		messages, err := hooks.ParseTemplate(message.Template, subCmd)
 		if err != nil {
-			return nil, false, err
+			return preCommandResponse{}, false, err
 		}
 
-		return messages, true, nil
+		return preCommandResponse{
+			pluginName: pluginName,
+			response:   message.Type,
+			message:    strings.TrimSpace(strings.Join(messages, "\n")),
+		}, true, nil
 	}
 
-	for pluginName, pluginCfg := range pluginsCfg {
-		messages, ok, err := tryInvokeHook(pluginName, pluginCfg)
+	for _, pluginName := range slices.Sorted(maps.Keys(pluginsCfg)) {
+		r, ok, err := tryInvokeHook(pluginName, pluginsCfg[pluginName])
 		if err != nil {
+			if pluginsCfg[pluginName]["pre-hooks-fail"] == "deny" {
+				responses = append(responses, preCommandResponse{
+					pluginName: pluginName,
+					response:   hooks.Deny,
+					message:    "pre-command hook failed: " + err.Error(),
+				})
+				continue
+			}
 			// skip misbehaving plugins, but don't halt execution
 			logrus.WithFields(logrus.Fields{
 				"error":  err,
 				"plugin": pluginName,
-			}).Debug("Plugin hook invocation failed")
+			}).Debug("Plugin pre-command hook invocation failed")
 			continue
 		}
-		if !ok {
-			continue
+		if ok {
+			responses = append(responses, r)
 		}
+	}
+	return responses
+}
+
+func runHooks(ctx context.Context, cfg *configfile.ConfigFile, rootCmd, subCommand *cobra.Command, invokedCommand string, flags map[string]string, cmdErrorMessage string, opts hookOptions) {
+	responses := invokeAndCollectHooks(ctx, cfg, rootCmd, subCommand, invokedCommand, flags, cmdErrorMessage, opts.contextName)
+
+	var (
+		nextSteps   []string
+		annotations []attribute.KeyValue
+		suggestion  *postCommandResponse
+	)
+	for _, r := range responses {
+		switch r.response {
+		case hooks.NextSteps:
+			var appended bool
+			nextSteps, appended = appendNextSteps(nextSteps, r.messages)
+			if !appended {
+				logrus.WithFields(logrus.Fields{
+					"plugin": r.pluginName,
+				}).Debug("Plugin responded with an empty hook message; ignoring")
+			}
+		case hooks.Warn:
+			_, _ = fmt.Fprintln(opts.out, "WARNING: "+strings.Join(r.messages, "\n"))
+		case hooks.Annotations:
+			for _, k := range slices.Sorted(maps.Keys(r.annotations)) {
+				annotations = append(annotations, attribute.String("hook."+r.pluginName+"."+k, r.annotations[k]))
+			}
+		case hooks.SuggestCommand:
+			if suggestion != nil {
+				logrus.WithFields(logrus.Fields{
+					"plugin": r.pluginName,
+				}).Debug("Only one command can be suggested; ignoring suggested command")
+				continue
+			}
+			suggestion = &r
+		}
+	}
 
-		var appended bool
-		nextSteps, appended = appendNextSteps(nextSteps, messages)
-		if !appended {
+	if len(annotations) > 0 {
+		trace.SpanFromContext(ctx).SetAttributes(annotations...)
+	}
+	hooks.PrintNextSteps(opts.out, nextSteps)
+	if suggestion != nil && opts.nonInteractive {
+		logrus.WithFields(logrus.Fields{
+			"plugin": suggestion.pluginName,
+		}).Debug("Not running the suggested command in a non-interactive session")
+	} else if suggestion != nil {
+		if err := runSuggestedCommand(ctx, subCommand, suggestion); err != nil {
 			logrus.WithFields(logrus.Fields{
+				"error":  err,
+				"plugin": suggestion.pluginName,
+			}).Debug("Failed to run the suggested command")
+		}
+	}
+}
+
+// runSuggestedCommand asks the user to confirm the command that is suggested
+// by a plugin, and executes it with the docker binary that is currently
+// running if the user confirms. The prompt always shows the command, so that
+// a plugin can not hide the command that is executed; the message of the
+// plugin, if any, is printed above it.
+func runSuggestedCommand(ctx context.Context, subCommand *cobra.Command, suggestion *postCommandResponse) error {
+	message := fmt.Sprintf("Run %q?", "docker "+strings.Join(suggestion.command, " "))
+	if text := strings.TrimSpace(strings.Join(suggestion.messages, "\n")); text != "" {
+		message = text + "\n" + message
+	}
+	ok, err := prompt.Confirm(ctx, subCommand.InOrStdin(), subCommand.ErrOrStderr(), message)
+	if err != nil || !ok {
+		return err
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	cmd := exec.CommandContext(ctx, exe, suggestion.command...)
+	cmd.Stdin = subCommand.InOrStdin()
+	cmd.Stdout = subCommand.OutOrStdout()
+	cmd.Stderr = subCommand.ErrOrStderr()
+	return cmd.Run()
+}
+
+// postCommandResponse is the processed response of a post-command hook.
+type postCommandResponse struct {
+	pluginName  string
+	response    hooks.ResponseType
+	messages    []string
+	annotations map[string]string
+	command     []string
+}
+
+// invokeAndCollectHooks invokes the post-command hooks of the plugins that
+// declare a hook for the command. Plugins are invoked in order of their name,
+// and plugins that fail or respond with an invalid response are skipped.
+func invokeAndCollectHooks(ctx context.Context, cfg *configfile.ConfigFile, rootCmd, subCmd *cobra.Command, subCmdStr string, flags map[string]string, cmdErrorMessage string, contextName string) []postCommandResponse {
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	pluginsCfg := cfg.Plugins
+	if pluginsCfg == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, hooksDeadline)
+	defer cancel()
+
+	pluginDirs := getPluginDirs(cfg)
+	responses := make([]postCommandResponse, 0, len(pluginsCfg))
+
+	tryInvokeHook := func(pluginName string, pluginCfg map[string]string) (postCommandResponse, bool, error) {
+		match, matched := pluginMatch(pluginCfg, subCmdStr, cmdErrorMessage)
+		if !matched {
+			return postCommandResponse{}, false, nil
+		}
+
+		p, err := getPlugin(pluginName, pluginDirs, rootCmd)
+		if err != nil {
+			return postCommandResponse{}, false, err
+		}
+
+		hookCtx, cancel := context.WithTimeout(ctx, hookTimeout(pluginCfg))
+		defer cancel()
+		resp, err := p.RunHook(hookCtx, hooks.Request{
+			RootCmd:      match,
+			Flags:        flagsForPlugin(subCmd, flags, pluginCfg),
+			CommandError: cmdErrorMessage,
+			Context:      contextName,
+		})
+		if err != nil {
+			return postCommandResponse{}, false, err
+		}
+
+		var message hooks.Response
+		if err := json.Unmarshal(resp, &message); err != nil {
+			return postCommandResponse{}, false, fmt.Errorf("failed to unmarshal hook response (%q): %w", string(resp), err)
+		}
+
+		switch message.Type {
+		case hooks.NextSteps, hooks.Warn:
+		case hooks.Annotations:
+			return postCommandResponse{pluginName: pluginName, response: message.Type, annotations: message.Annotations}, true, nil
+		case hooks.SuggestCommand:
+			if len(message.Command) == 0 || strings.HasPrefix(message.Command[0], "-") {
+				return postCommandResponse{}, false, fmt.Errorf("invalid suggested command: %q", message.Command)
+			}
+		default:
+			return postCommandResponse{}, false, errors.New("unexpected hook response type: " + strconv.Itoa(int(message.Type)))
+		}
+
+		messages, err := hooks.ParseTemplate(message.Template, subCmd)
+		if err != nil {
+			return postCommandResponse{}, false, err
+		}
+
+		return postCommandResponse{
+			pluginName: pluginName,
+			response:   message.Type,
+			messages:   messages,
+			command:    message.Command,
+		}, true, nil
+	}
+
+	for _, pluginName := range slices.Sorted(maps.Keys(pluginsCfg)) {
+		r, ok, err := tryInvokeHook(pluginName, pluginsCfg[pluginName])
+		if err != nil {
+			// skip misbehaving plugins, but don't halt execution
+			logrus.WithFields(logrus.Fields{
+				"error":  err,
 				"plugin": pluginName,
-			}).Debug("Plugin responded with an empty hook message; ignoring")
+			}).Debug("Plugin hook invocation failed")
+			continue
+		}
+		if ok {
+			responses = append(responses, r)
 		}
 	}
-	return nextSteps
+	return responses
 }
 
 // appendNextSteps appends the processed hook output to the nextSteps slice.
@@ -153,9 +466,13 @@ func appendNextSteps(nextSteps []string, processed []string) ([]string, bool) {
 // and, if the configuration includes a hook for the invoked command, returns
 // the configured hook string.
 //
-// Plugins can declare two types of hooks in their configuration:
+// Plugins can declare two types of hooks in their configuration that fire
+// after a command was executed:
 //   - "hooks": fires on every command invocation (success or failure)
 //   - "error-hooks": fires only when a command fails (cmdErrorMessage is non-empty)
+//
+// Hooks that fire before a command is executed are declared with "pre-hooks",
+// and are matched by [invokePreCommandHooks].
 func pluginMatch(pluginCfg map[string]string, subCmd string, cmdErrorMessage string) (string, bool) {
 	// Check "hooks" first — these always fire regardless of command outcome.
 	if match, ok := matchHookConfig(pluginCfg["hooks"], subCmd); ok {
@@ -205,18 +522,72 @@ func hookMatch(hookCmd, subCmd string) bool {
 	return true
 }
 
+// getCommandFlags returns the flags that were set on the command, and their
+// values. Flag values are redacted by [flagsForPlugin] before they are passed
+// to a plugin.
 func getCommandFlags(cmd *cobra.Command) map[string]string {
 	flags := make(map[string]string)
 	cmd.Flags().Visit(func(f *pflag.Flag) {
-		var fValue string
-		if f.Value.Type() == "bool" {
-			fValue = f.Value.String()
-		}
-		flags[f.Name] = fValue
+		flags[f.Name] = f.Value.String()
+	})
+	return flags
+}
+
+// parseCommandFlags returns the flags that are set in args, and their values,
+// for a command that is not executed yet. The args are parsed into a copy of
+// the flags of the command, so that the flags are not set twice when the
+// command is executed. Flags are parsed after the first positional argument
+// as well, as the copy can't tell if the command allows interspersed flags.
+func parseCommandFlags(cmd *cobra.Command, args []string) map[string]string {
+	flags := getCommandFlags(cmd)
+
+	fs := pflag.NewFlagSet(cmd.Name(), pflag.ContinueOnError)
+	fs.ParseErrorsAllowlist.UnknownFlags = true
+	fs.SetOutput(io.Discard)
+	for _, set := range []*pflag.FlagSet{cmd.LocalFlags(), cmd.InheritedFlags()} {
+		set.VisitAll(func(f *pflag.Flag) {
+			fs.AddFlag(&pflag.Flag{
+				Name:        f.Name,
+				Shorthand:   f.Shorthand,
+				NoOptDefVal: f.NoOptDefVal,
+				Value:       &rawFlagValue{typ: f.Value.Type()},
+			})
+		})
+	}
+	_ = fs.Parse(args)
+	fs.Visit(func(f *pflag.Flag) {
+		flags[f.Name] = f.Value.String()
 	})
 	return flags
 }
 
+// rawFlagValue stores the value of a flag as it was passed.
+type rawFlagValue struct {
+	typ   string
+	value string
+}
+
+func (v *rawFlagValue) String() string     { return v.value }
+func (v *rawFlagValue) Set(s string) error { v.value = s; return nil }
+func (v *rawFlagValue) Type() string       { return v.typ }
+
+// flagsForPlugin returns the flags that are passed to the hook of a plugin.
+// Flag values are set to an empty string, except for boolean flags, and for
+// the flags that are listed in the comma-separated "hook-flag-values" option
+// of the plugin.
+func flagsForPlugin(cmd *cobra.Command, flags map[string]string, pluginCfg map[string]string) map[string]string {
+	allowed := strings.Split(pluginCfg["hook-flag-values"], ",")
+	pluginFlags := make(map[string]string, len(flags))
+	for name, value := range flags {
+		f := cmd.Flags().Lookup(name)
+		if f == nil || (f.Value.Type() != "bool" && !slices.Contains(allowed, name)) {
+			value = ""
+		}
+		pluginFlags[name] = value
+	}
+	return pluginFlags
+}
+
 // getNaiveFlags string-matches argv and parses them into a map.
 // This is used when calling hooks after a plugin command, since
 // in this case we can't rely on the cobra command tree to parse
@@ -1,13 +1,23 @@
 package manager
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"os"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/docker/cli/cli-plugins/hooks"
 	"github.com/docker/cli/cli/config/configfile"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"gotest.tools/v3/assert"
 	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
 )
 
 type fakeConfigProvider struct {
@@ -49,6 +59,32 @@ func TestGetNaiveFlags(t *testing.T) {
 	}
 }
 
+func TestParseCommandFlags(t *testing.T) {
+	root := &cobra.Command{Use: "docker"}
+	root.PersistentFlags().String("context", "", "")
+	sub := &cobra.Command{Use: "run"}
+	sub.Flags().BoolP("interactive", "i", false, "")
+	sub.Flags().BoolP("tty", "t", false, "")
+	sub.Flags().StringSliceP("env", "e", nil, "")
+	sub.Flags().String("name", "", "")
+	root.AddCommand(sub)
+
+	flags := parseCommandFlags(sub, []string{"-it", "--name=web", "-e", "A=1", "--unknown", "alpine", "sh"})
+	assert.Check(t, is.DeepEqual(flags, map[string]string{
+		"interactive": "true",
+		"tty":         "true",
+		"name":        "web",
+		"env":         "A=1",
+	}))
+
+	// The flags of the command are not set, as they are parsed again when
+	// the command is executed.
+	assert.Check(t, !sub.Flags().Changed("name"))
+	env, err := sub.Flags().GetStringSlice("env")
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(env, 0))
+}
+
 func TestPluginMatch(t *testing.T) {
 	testCases := []struct {
 		doc             string
@@ -337,7 +373,7 @@ func TestInvokeAndCollectHooksErrorHooksSkippedOnSuccess(t *testing.T) {
 	// binary is never looked up and no results are returned.
 	result := invokeAndCollectHooks(
 		context.Background(), cfg, root, sub,
-		"build", map[string]string{}, "",
+		"build", map[string]string{}, "", "",
 	)
 	assert.Check(t, is.Len(result, 0))
 }
@@ -350,7 +386,7 @@ func TestInvokeAndCollectHooksNoPlugins(t *testing.T) {
 
 	result := invokeAndCollectHooks(
 		context.Background(), cfg, root, sub,
-		"build", map[string]string{}, "some error",
+		"build", map[string]string{}, "some error", "",
 	)
 	assert.Check(t, is.Len(result, 0))
 }
@@ -369,7 +405,240 @@ func TestInvokeAndCollectHooksCancelledContext(t *testing.T) {
 
 	result := invokeAndCollectHooks(
 		ctx, cfg, root, sub,
-		"build", map[string]string{}, "exit status 1",
+		"build", map[string]string{}, "exit status 1", "",
 	)
 	assert.Check(t, is.Nil(result))
 }
+
+func TestRunPreCommandHooks(t *testing.T) {
+	const pluginTmpl = `#!/bin/sh
+if [ "$2" = "docker-cli-plugin-hooks" ]; then
+	printf '%%s' "$3" > "$(dirname "$0")/request-$1.json"
+	echo '%s'
+else
+	echo '{"SchemaVersion":"0.1.0"}'
+fi
+`
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithFile("docker-allow", fmt.Sprintf(pluginTmpl, `{"Type":1}`), fs.WithMode(0o777)),
+		fs.WithFile("docker-deny", fmt.Sprintf(pluginTmpl, `{"Type":2,"Template":"not allowed on the {{flagValue \"context\"}} context"}`), fs.WithMode(0o777)),
+		fs.WithFile("docker-warn", fmt.Sprintf(pluginTmpl, `{"Type":3,"Template":"privileged containers are insecure"}`), fs.WithMode(0o777)),
+		fs.WithFile("docker-nextsteps", fmt.Sprintf(pluginTmpl, `{"Type":0,"Template":"next steps"}`), fs.WithMode(0o777)),
+		fs.WithFile("docker-invalid", fmt.Sprintf(pluginTmpl, `not json`), fs.WithMode(0o777)),
+	)
+
+	newCommand := func() (*cobra.Command, *cobra.Command, *bytes.Buffer) {
+		root := &cobra.Command{Use: "docker"}
+		root.PersistentFlags().String("context", "", "")
+		sub := &cobra.Command{Use: "run"}
+		sub.Flags().Bool("privileged", false, "")
+		root.AddCommand(sub)
+		assert.NilError(t, root.ParseFlags([]string{"--context", "prod"}))
+		var errBuf bytes.Buffer
+		sub.SetErr(&errBuf)
+		return root, sub, &errBuf
+	}
+
+	t.Run("allow and warn", func(t *testing.T) {
+		cfg := configfile.New("")
+		cfg.CLIPluginsExtraDirs = []string{dir.Path()}
+		cfg.Plugins = map[string]map[string]string{
+			"allow":     {"pre-hooks": "run"},
+			"warn":      {"pre-hooks": "image,run"},
+			"deny":      {"pre-hooks": "image"},
+			"nextsteps": {"hooks": "run"},
+		}
+		root, sub, errBuf := newCommand()
+		err := RunPreCommandHooks(context.Background(), &fakeConfigProvider{cfg: cfg}, root, sub, []string{"--privileged"}, WithContextName("prod"))
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(errBuf.String(), "WARNING: privileged containers are insecure\n"))
+
+		req, err := os.ReadFile(dir.Join("request-allow.json"))
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(string(req), `{"Stage":"pre","RootCmd":"run","Flags":{"privileged":"true"},"Context":"prod"}`))
+	})
+
+	t.Run("failing hook", func(t *testing.T) {
+		cfg := configfile.New("")
+		cfg.CLIPluginsExtraDirs = []string{dir.Path()}
+		cfg.Plugins = map[string]map[string]string{
+			"invalid": {"pre-hooks": "run"},
+		}
+		root, sub, _ := newCommand()
+		err := RunPreCommandHooks(context.Background(), &fakeConfigProvider{cfg: cfg}, root, sub, []string{"--privileged"})
+		assert.NilError(t, err)
+
+		cfg.Plugins["invalid"]["pre-hooks-fail"] = "deny"
+		err = RunPreCommandHooks(context.Background(), &fakeConfigProvider{cfg: cfg}, root, sub, []string{"--privileged"})
+		assert.Check(t, is.ErrorContains(err, "docker run: denied by the invalid plugin: pre-command hook failed: failed to unmarshal hook response"))
+
+		delete(cfg.Plugins, "invalid")
+		cfg.Plugins["missing"] = map[string]string{"pre-hooks": "run", "pre-hooks-fail": "deny"}
+		err = RunPreCommandHooks(context.Background(), &fakeConfigProvider{cfg: cfg}, root, sub, []string{"--privileged"})
+		assert.Check(t, is.ErrorContains(err, "docker run: denied by the missing plugin: pre-command hook failed: "))
+	})
+
+	t.Run("deny", func(t *testing.T) {
+		cfg := configfile.New("")
+		cfg.CLIPluginsExtraDirs = []string{dir.Path()}
+		cfg.Plugins = map[string]map[string]string{
+			"deny":      {"pre-hooks": "run"},
+			"warn":      {"pre-hooks": "run"},
+			"nextsteps": {"pre-hooks": "run"},
+		}
+		root, sub, errBuf := newCommand()
+		err := RunPreCommandHooks(context.Background(), &fakeConfigProvider{cfg: cfg}, root, sub, []string{"--privileged"})
+		assert.Check(t, is.Error(err, "docker run: denied by the deny plugin: not allowed on the prod context"))
+		assert.Check(t, is.Equal(errBuf.String(), "WARNING: privileged containers are insecure\n"))
+	})
+}
+
+func TestRunCLICommandHooks(t *testing.T) {
+	const pluginTmpl = `#!/bin/sh
+if [ "$2" = "docker-cli-plugin-hooks" ]; then
+	printf '%%s' "$3" > "$(dirname "$0")/request-$1.json"
+	echo '%s'
+else
+	echo '{"SchemaVersion":"0.1.0"}'
+fi
+`
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithFile("docker-nextsteps", fmt.Sprintf(pluginTmpl, `{"Type":0,"Template":"next steps"}`), fs.WithMode(0o777)),
+		fs.WithFile("docker-warn", fmt.Sprintf(pluginTmpl, `{"Type":3,"Template":"image {{flagValue \"tag\"}} is deprecated"}`), fs.WithMode(0o777)),
+		fs.WithFile("docker-annotate", fmt.Sprintf(pluginTmpl, `{"Type":4,"Annotations":{"image.size":"large"}}`), fs.WithMode(0o777)),
+		fs.WithFile("docker-suggest", fmt.Sprintf(pluginTmpl, `{"Type":5,"Command":["image","prune","--all"]}`), fs.WithMode(0o777)),
+		fs.WithFile("docker-suggest2", fmt.Sprintf(pluginTmpl, `{"Type":5,"Command":["system","prune"]}`), fs.WithMode(0o777)),
+	)
+
+	cfg := configfile.New("")
+	cfg.CLIPluginsExtraDirs = []string{dir.Path()}
+	cfg.Plugins = map[string]map[string]string{
+		"nextsteps": {"hooks": "build"},
+		"warn":      {"hooks": "build", "hook-flag-values": "tag"},
+		"annotate":  {"hooks": "build"},
+		"suggest":   {"hooks": "build"},
+		"suggest2":  {"hooks": "build"},
+	}
+
+	root := &cobra.Command{Use: "docker"}
+	sub := &cobra.Command{Use: "build"}
+	sub.Flags().String("tag", "", "")
+	sub.Flags().String("secret", "", "")
+	sub.Flags().Bool("pull", false, "")
+	root.AddCommand(sub)
+	assert.NilError(t, sub.ParseFlags([]string{"--tag", "myimage", "--secret", "id=token", "--pull"}))
+	var errBuf bytes.Buffer
+	sub.SetErr(&errBuf)
+	sub.SetIn(strings.NewReader("n\n"))
+
+	ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "command")
+	RunCLICommandHooks(ctx, &fakeConfigProvider{cfg: cfg}, root, sub, "")
+	span.End()
+
+	assert.Check(t, is.Equal(errBuf.String(), "WARNING: image myimage is deprecated\n"+
+		"\n\033[1mWhat's next:\033[0m\n    next steps\n"+
+		`Run "docker image prune --all"? [y/N] `))
+	assert.Check(t, is.DeepEqual(span.(sdktrace.ReadOnlySpan).Attributes(), []attribute.KeyValue{
+		attribute.String("hook.annotate.image.size", "large"),
+	}, cmpopts.EquateComparable(attribute.Value{})))
+
+	req, err := os.ReadFile(dir.Join("request-warn.json"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(req), `{"RootCmd":"build","Flags":{"pull":"true","secret":"","tag":"myimage"}}`))
+	req, err = os.ReadFile(dir.Join("request-nextsteps.json"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(req), `{"RootCmd":"build","Flags":{"pull":"true","secret":"","tag":""}}`))
+}
+
+func TestRunCLICommandHooksOptions(t *testing.T) {
+	const pluginTmpl = `#!/bin/sh
+if [ "$2" = "docker-cli-plugin-hooks" ]; then
+	%s
+else
+	echo '{"SchemaVersion":"0.1.0"}'
+fi
+`
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithFile("docker-nextsteps", fmt.Sprintf(pluginTmpl, `echo '{"Type":0,"Template":"next steps"}'`), fs.WithMode(0o777)),
+		fs.WithFile("docker-slow", fmt.Sprintf(pluginTmpl, `sleep 10; echo '{"Type":3,"Template":"too late"}'`), fs.WithMode(0o777)),
+		fs.WithFile("docker-suggest", fmt.Sprintf(pluginTmpl, `echo '{"Type":5,"Command":["image","prune"]}'`), fs.WithMode(0o777)),
+	)
+
+	cfg := configfile.New("")
+	cfg.CLIPluginsExtraDirs = []string{dir.Path()}
+	cfg.Plugins = map[string]map[string]string{
+		"nextsteps": {"hooks": "build"},
+		"slow":      {"hooks": "build", "hook-timeout": "100ms"},
+		"suggest":   {"hooks": "build"},
+	}
+
+	root := &cobra.Command{Use: "docker"}
+	sub := &cobra.Command{Use: "build"}
+	root.AddCommand(sub)
+	var errBuf, hookOut bytes.Buffer
+	sub.SetErr(&errBuf)
+
+	start := time.Now()
+	RunCLICommandHooks(context.Background(), &fakeConfigProvider{cfg: cfg}, root, sub, "", WithHookOutput(&hookOut), WithNonInteractive())
+	assert.Check(t, time.Since(start) < 5*time.Second, "slow hook was not cancelled")
+	assert.Check(t, is.Equal(errBuf.String(), ""))
+	assert.Check(t, is.Equal(hookOut.String(), "\n\033[1mWhat's next:\033[0m\n    next steps\n"))
+}
+
+func TestRunSuggestedCommandPrompt(t *testing.T) {
+	sub := &cobra.Command{Use: "build"}
+	var errBuf bytes.Buffer
+	sub.SetErr(&errBuf)
+	sub.SetIn(strings.NewReader("n\n"))
+
+	err := runSuggestedCommand(context.Background(), sub, &postCommandResponse{
+		pluginName: "suggest",
+		response:   hooks.SuggestCommand,
+		messages:   []string{"Clean up unused images?"},
+		command:    []string{"image", "prune", "--all"},
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(errBuf.String(), "Clean up unused images?\n"+`Run "docker image prune --all"? [y/N] `))
+}
+
+func TestHooksDeadline(t *testing.T) {
+	const pluginTmpl = `#!/bin/sh
+if [ "$2" = "docker-cli-plugin-hooks" ]; then
+	sleep 10; echo '{"Type":3,"Template":"too late"}'
+else
+	echo '{"SchemaVersion":"0.1.0"}'
+fi
+`
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithFile("docker-slow1", pluginTmpl, fs.WithMode(0o777)),
+		fs.WithFile("docker-slow2", pluginTmpl, fs.WithMode(0o777)),
+	)
+
+	cfg := configfile.New("")
+	cfg.CLIPluginsExtraDirs = []string{dir.Path()}
+	cfg.Plugins = map[string]map[string]string{
+		"slow1": {"hooks": "build", "hook-timeout": "1m"},
+		"slow2": {"hooks": "build", "hook-timeout": "1m"},
+	}
+
+	defer func(d time.Duration) { hooksDeadline = d }(hooksDeadline)
+	hooksDeadline = 200 * time.Millisecond
+
+	root := &cobra.Command{Use: "docker"}
+	sub := &cobra.Command{Use: "build"}
+	root.AddCommand(sub)
+	var errBuf bytes.Buffer
+	sub.SetErr(&errBuf)
+
+	start := time.Now()
+	RunCLICommandHooks(context.Background(), &fakeConfigProvider{cfg: cfg}, root, sub, "")
+	assert.Check(t, time.Since(start) < 5*time.Second, "hooks were not cancelled at the deadline")
+	assert.Check(t, is.Equal(errBuf.String(), ""))
+}
+
+func TestHookTimeout(t *testing.T) {
+	assert.Check(t, is.Equal(hookTimeout(map[string]string{}), defaultHookTimeout))
+	assert.Check(t, is.Equal(hookTimeout(map[string]string{"hook-timeout": "2s"}), 2*time.Second))
+	assert.Check(t, is.Equal(hookTimeout(map[string]string{"hook-timeout": "-1s"}), defaultHookTimeout))
+	assert.Check(t, is.Equal(hookTimeout(map[string]string{"hook-timeout": "invalid"}), defaultHookTimeout))
+}
@@ -45,12 +45,18 @@ func getPluginDirs(cfg *configfile.ConfigFile) []string {
 	if cfg != nil {
 		pluginDirs = append(pluginDirs, cfg.CLIPluginsExtraDirs...)
 	}
-	pluginDir := filepath.Join(config.Dir(), "cli-plugins")
-	pluginDirs = append(pluginDirs, pluginDir)
+	pluginDirs = append(pluginDirs, UserPluginDir())
 	pluginDirs = append(pluginDirs, defaultSystemPluginDirs...)
 	return pluginDirs
 }
 
+// UserPluginDir returns the directory for the plugins of the user, which is
+// the "cli-plugins" directory inside the CLIs [config.Path] (usually
+// "~/.docker/cli-plugins").
+func UserPluginDir() string {
+	return filepath.Join(config.Dir(), "cli-plugins")
+}
+
 func addPluginCandidatesFromDir(res map[string][]string, d string) {
 	dentries, err := os.ReadDir(d)
 	// Silently ignore any directories which we cannot list (e.g. due to
@@ -96,6 +102,14 @@ func listPluginCandidates(dirs []string) map[string][]string {
 	return result
 }
 
+// IsPluginCandidate returns whether a plugin with the given name is
+// installed on the system. Unlike [GetPlugin], the plugin is not executed to
+// validate it.
+func IsPluginCandidate(name string, dockerCLI config.Provider) bool {
+	candidates := listPluginCandidates(getPluginDirs(dockerCLI.ConfigFile()))
+	return len(candidates[name]) > 0
+}
+
 // GetPlugin returns a plugin on the system by its name
 func GetPlugin(name string, dockerCLI config.Provider, rootcmd *cobra.Command) (*Plugin, error) {
 	pluginDirs := getPluginDirs(dockerCLI.ConfigFile())
@@ -11,6 +11,7 @@
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/docker/cli/cli-plugins/hooks"
 	"github.com/docker/cli/cli-plugins/metadata"
@@ -164,6 +165,9 @@ func (p *Plugin) RunHook(ctx context.Context, hookData hooks.Request) ([]byte, e
 	pCmd := exec.CommandContext(ctx, p.Path, p.Name, metadata.HookSubcommandName, string(hDataBytes)) // #nosec G204 -- ignore "Subprocess launched with a potential tainted input or cmd arguments"
 	pCmd.Env = os.Environ()
 	pCmd.Env = append(pCmd.Env, metadata.ReexecEnvvar+"="+os.Args[0])
+	// Don't wait indefinitely for processes that were started by the hook,
+	// and which keep its output open, after the hook exited or was cancelled.
+	pCmd.WaitDelay = time.Second
 
 	out, err := pCmd.Output()
 	if err != nil {
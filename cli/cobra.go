@@ -16,6 +16,11 @@
 	"github.com/spf13/pflag"
 )
 
+// CommandAnnotationAlias is added to the commands that are added for the
+// aliases that are defined in the CLI configuration file. Its value is the
+// command that the alias expands to.
+const CommandAnnotationAlias = "com.docker.cli.alias"
+
 // setupCommonRootCommand contains the setup common to
 // SetupRootCommand and SetupPluginRootCommand.
 func setupCommonRootCommand(rootCmd *cobra.Command) (*cliflags.ClientOptions, *cobra.Command) {
@@ -29,12 +34,14 @@ func setupCommonRootCommand(rootCmd *cobra.Command) (*cliflags.ClientOptions, *c
 	cobra.AddTemplateFunc("hasManagementSubCommands", hasManagementSubCommands)
 	cobra.AddTemplateFunc("hasSwarmSubCommands", hasSwarmSubCommands)
 	cobra.AddTemplateFunc("hasInvalidPlugins", hasInvalidPlugins)
+	cobra.AddTemplateFunc("hasAliasCommands", hasAliasCommands)
 	cobra.AddTemplateFunc("topCommands", topCommands)
 	cobra.AddTemplateFunc("commandAliases", commandAliases)
 	cobra.AddTemplateFunc("operationSubCommands", operationSubCommands)
 	cobra.AddTemplateFunc("managementSubCommands", managementSubCommands)
 	cobra.AddTemplateFunc("orchestratorSubCommands", orchestratorSubCommands)
 	cobra.AddTemplateFunc("invalidPlugins", invalidPlugins)
+	cobra.AddTemplateFunc("aliasCommands", aliasCommands)
 	cobra.AddTemplateFunc("wrappedFlagUsages", wrappedFlagUsages)
 	cobra.AddTemplateFunc("vendorAndVersion", vendorAndVersion)
 	cobra.AddTemplateFunc("invalidPluginReason", invalidPluginReason)
@@ -215,6 +222,11 @@ func isPlugin(cmd *cobra.Command) bool {
 	return cmd.Annotations[metadata.CommandAnnotationPlugin] == "true"
 }
 
+func isAlias(cmd *cobra.Command) bool {
+	_, ok := cmd.Annotations[CommandAnnotationAlias]
+	return ok
+}
+
 func hasAliases(cmd *cobra.Command) bool {
 	return len(cmd.Aliases) > 0 || cmd.Annotations["aliases"] != ""
 }
@@ -235,6 +247,10 @@ func hasInvalidPlugins(cmd *cobra.Command) bool {
 	return len(invalidPlugins(cmd)) > 0
 }
 
+func hasAliasCommands(cmd *cobra.Command) bool {
+	return len(aliasCommands(cmd)) > 0
+}
+
 func hasTopCommands(cmd *cobra.Command) bool {
 	return len(topCommands(cmd)) > 0
 }
@@ -282,7 +298,7 @@ func topCommands(cmd *cobra.Command) []*cobra.Command {
 func operationSubCommands(cmd *cobra.Command) []*cobra.Command {
 	cmds := []*cobra.Command{}
 	for _, sub := range cmd.Commands() {
-		if isPlugin(sub) {
+		if isPlugin(sub) || isAlias(sub) {
 			continue
 		}
 		if _, ok := sub.Annotations["category-top"]; ok {
@@ -374,6 +390,16 @@ func invalidPlugins(cmd *cobra.Command) []*cobra.Command {
 	return cmds
 }
 
+func aliasCommands(cmd *cobra.Command) []*cobra.Command {
+	cmds := []*cobra.Command{}
+	for _, sub := range cmd.Commands() {
+		if isAlias(sub) && sub.IsAvailableCommand() {
+			cmds = append(cmds, sub)
+		}
+	}
+	return cmds
+}
+
 func invalidPluginReason(cmd *cobra.Command) string {
 	return cmd.Annotations[metadata.CommandAnnotationPluginInvalid]
 }
@@ -448,6 +474,15 @@ func invalidPluginReason(cmd *cobra.Command) string {
 {{- end}}
 {{- end}}
 
+{{- if hasAliasCommands . }}
+
+Aliases:
+
+{{- range aliasCommands . }}
+  {{rpad .Name .NamePadding }} {{.Short}}
+{{- end}}
+{{- end}}
+
 {{- if hasInvalidPlugins . }}
 
 Invalid Plugins:
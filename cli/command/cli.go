@@ -11,6 +11,7 @@
 	"os"
 	"runtime"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -206,6 +207,41 @@ func (cli *DockerCli) HooksEnabled() bool {
 	return false
 }
 
+// HooksNonInteractive returns whether plugin hooks are run when the CLI is
+// used non-interactively; for example, in a CI pipeline, where the output of
+// the CLI is not a terminal. Hooks must also be enabled (see [DockerCli.HooksEnabled]).
+func (cli *DockerCli) HooksNonInteractive() bool {
+	if v := os.Getenv("DOCKER_CLI_HOOKS_NON_INTERACTIVE"); v != "" {
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			return false
+		}
+		return enabled
+	}
+	if v, ok := cli.ConfigFile().Features["hooks-non-interactive"]; ok {
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			return false
+		}
+		return enabled
+	}
+	return false
+}
+
+// HooksOutput returns the path of the file to which the output of plugin
+// hooks is appended. It returns an empty string if the output of hooks is
+// written to stderr.
+func (cli *DockerCli) HooksOutput() string {
+	out, ok := os.LookupEnv("DOCKER_CLI_HOOKS_OUTPUT")
+	if !ok {
+		out = cli.ConfigFile().Features["hooks-output"]
+	}
+	if out == "stderr" {
+		return ""
+	}
+	return out
+}
+
 // Initialize the dockerCli runs initialization that must happen after command
 // line flags are parsed.
 func (cli *DockerCli) Initialize(opts *cliflags.ClientOptions, ops ...CLIOption) error {
@@ -253,7 +289,7 @@ func (cli *DockerCli) Initialize(opts *cliflags.ClientOptions, ops ...CLIOption)
 	cli.contextStore = &ContextStoreWithDefault{
 		Store: store.New(config.ContextStoreDir(), *cli.contextStoreConfig),
 		Resolver: func() (*DefaultContext, error) {
-			return resolveDefaultContext(cli.options, *cli.contextStoreConfig)
+			return resolveDefaultContext(cli.options, cli.configFile, *cli.contextStoreConfig)
 		},
 	}
 
@@ -266,20 +302,37 @@ func (cli *DockerCli) Initialize(opts *cliflags.ClientOptions, ops ...CLIOption)
 	}
 	filterResourceAttributesEnvvar()
 
-	// early return if GODEBUG is already set or the docker context is
-	// the default context, i.e. is a virtual context where we won't override
-	// any GODEBUG values.
-	if v := os.Getenv("GODEBUG"); cli.currentContext == DefaultContextName || v != "" {
+	// early return if the docker context is the default context, i.e. is a
+	// virtual context that has no metadata to apply.
+	if cli.currentContext == DefaultContextName {
 		return nil
 	}
 	meta, err := cli.contextStore.GetMetadata(cli.currentContext)
-	if err == nil {
-		setGoDebug(meta)
+	if err != nil {
+		return nil
+	}
+	if err := applyContextConfig(cli.configFile, meta); err != nil {
+		_, _ = fmt.Fprintf(cli.err, "WARNING: ignoring the CLI configuration of context %q: %v\n", cli.currentContext, err)
 	}
+	setGoDebug(meta)
 
 	return nil
 }
 
+// applyContextConfig layers the settings of the CLI configuration file that
+// are overridden in the context metadata over the configuration file.
+func applyContextConfig(configFile *configfile.ConfigFile, meta store.Metadata) error {
+	dockerContext, err := GetDockerContext(meta)
+	if err != nil {
+		return err
+	}
+	overrides, err := dockerContext.CLIConfig()
+	if err != nil {
+		return err
+	}
+	return configFile.SetOverrides(overrides)
+}
+
 // NewAPIClientFromFlags creates a new APIClient from command line flags
 func NewAPIClientFromFlags(opts *cliflags.ClientOptions, configFile *configfile.ConfigFile) (client.APIClient, error) {
 	if opts.Context != "" && len(opts.Hosts) > 0 {
@@ -290,7 +343,7 @@ func NewAPIClientFromFlags(opts *cliflags.ClientOptions, configFile *configfile.
 	contextStore := &ContextStoreWithDefault{
 		Store: store.New(config.ContextStoreDir(), storeConfig),
 		Resolver: func() (*DefaultContext, error) {
-			return resolveDefaultContext(opts, storeConfig)
+			return resolveDefaultContext(opts, configFile, storeConfig)
 		},
 	}
 	endpoint, err := resolveDockerEndpoint(contextStore, resolveContextName(opts, configFile))
@@ -300,8 +353,22 @@ func NewAPIClientFromFlags(opts *cliflags.ClientOptions, configFile *configfile.
 	return newAPIClientFromEndpoint(endpoint, configFile, client.WithUserAgent(UserAgent()))
 }
 
+// NewAPIClientForContext creates a new APIClient for the Docker endpoint of
+// the given context, and returns it with the endpoint it connects to.
+func NewAPIClientForContext(s store.Reader, contextName string, configFile *configfile.ConfigFile) (client.APIClient, docker.Endpoint, error) {
+	endpoint, err := resolveDockerEndpoint(s, contextName)
+	if err != nil {
+		return nil, docker.Endpoint{}, fmt.Errorf("unable to resolve docker endpoint: %w", err)
+	}
+	apiClient, err := newAPIClientFromEndpoint(endpoint, configFile, client.WithUserAgent(UserAgent()))
+	if err != nil {
+		return nil, endpoint, err
+	}
+	return apiClient, endpoint, nil
+}
+
 func newAPIClientFromEndpoint(ep docker.Endpoint, configFile *configfile.ConfigFile, extraOpts ...client.Opt) (client.APIClient, error) {
-	opts, err := ep.ClientOpts()
+	opts, err := ep.ClientOptsWithConfig(configFile)
 	if err != nil {
 		return nil, err
 	}
@@ -335,11 +402,11 @@ func resolveDockerEndpoint(s store.Reader, contextName string) (docker.Endpoint,
 }
 
 // Resolve the Docker endpoint for the default context (based on config, env vars and CLI flags)
-func resolveDefaultDockerEndpoint(opts *cliflags.ClientOptions) (docker.Endpoint, error) {
+func resolveDefaultDockerEndpoint(opts *cliflags.ClientOptions, configFile *configfile.ConfigFile) (docker.Endpoint, error) {
 	// defaultToTLS determines whether we should use a TLS host as default
 	// if nothing was configured by the user.
 	defaultToTLS := opts.TLSOptions != nil
-	host, err := getServerHost(opts.Hosts, defaultToTLS)
+	host, err := getServerHost(opts.Hosts, defaultToTLS, configFile)
 	if err != nil {
 		return docker.Endpoint{}, err
 	}
@@ -469,7 +536,7 @@ func (cli *DockerCli) DockerEndpoint() docker.Endpoint {
 func (cli *DockerCli) getDockerEndPoint() (ep docker.Endpoint, err error) {
 	cn := cli.CurrentContext()
 	if cn == DefaultContextName {
-		return resolveDefaultDockerEndpoint(cli.options)
+		return resolveDefaultDockerEndpoint(cli.options, cli.configFile)
 	}
 	return resolveDockerEndpoint(cli.contextStore, cn)
 }
@@ -583,15 +650,36 @@ func NewDockerCli(ops ...CLIOption) (*DockerCli, error) {
 	return cli, nil
 }
 
-func getServerHost(hosts []string, defaultToTLS bool) (string, error) {
+func getServerHost(hosts []string, defaultToTLS bool, configFile *configfile.ConfigFile) (string, error) {
+	var host string
 	switch len(hosts) {
 	case 0:
-		return dopts.ParseHost(defaultToTLS, os.Getenv(client.EnvOverrideHost))
+		host = os.Getenv(client.EnvOverrideHost)
 	case 1:
-		return dopts.ParseHost(defaultToTLS, hosts[0])
+		host = hosts[0]
 	default:
 		return "", errors.New("specify only one -H")
 	}
+	if hasConnectionHelper(host, configFile) {
+		// Hosts with a custom URL scheme are passed as-is to the connection
+		// helper that is configured for the scheme.
+		return strings.TrimSpace(host), nil
+	}
+	return dopts.ParseHost(defaultToTLS, host)
+}
+
+// hasConnectionHelper returns whether a connection helper is configured in
+// the config-file for the URL scheme of the host.
+func hasConnectionHelper(host string, configFile *configfile.ConfigFile) bool {
+	if configFile == nil {
+		return false
+	}
+	scheme, _, ok := strings.Cut(strings.TrimSpace(host), "://")
+	if !ok {
+		return false
+	}
+	_, ok = configFile.ConnectionHelpers[scheme]
+	return ok
 }
 
 // UserAgent returns the default user agent string used for making API requests.
@@ -22,6 +22,7 @@
 	"github.com/docker/cli/cli/flags"
 	"github.com/moby/moby/client"
 	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
 )
 
 func TestNewAPIClientFromFlags(t *testing.T) {
@@ -49,6 +50,31 @@ func TestNewAPIClientFromFlagsForDefaultSchema(t *testing.T) {
 	assert.Equal(t, apiClient.ClientVersion(), client.MaxAPIVersion)
 }
 
+func TestNewAPIClientFromFlagsWithConnectionHelper(t *testing.T) {
+	opts := &flags.ClientOptions{Hosts: []string{"myscheme://example.com"}}
+	_, err := NewAPIClientFromFlags(opts, &configfile.ConfigFile{})
+	assert.Check(t, is.ErrorContains(err, "invalid bind address format: myscheme://example.com"))
+
+	apiClient, err := NewAPIClientFromFlags(opts, &configfile.ConfigFile{
+		ConnectionHelpers: map[string]configfile.ConnectionHelper{
+			"myscheme": {Command: "my-helper"},
+		},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, apiClient.DaemonHost(), "http://docker.example.com")
+}
+
+func TestNewAPIClientFromFlagsWithConnectionHelperFromEnv(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "myscheme://example.com")
+	apiClient, err := NewAPIClientFromFlags(&flags.ClientOptions{}, &configfile.ConfigFile{
+		ConnectionHelpers: map[string]configfile.ConnectionHelper{
+			"myscheme": {Command: "my-helper"},
+		},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, apiClient.DaemonHost(), "http://docker.example.com")
+}
+
 func TestNewAPIClientFromFlagsWithCustomHeaders(t *testing.T) {
 	var received map[string]string
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -368,6 +394,48 @@ func TestHooksEnabled(t *testing.T) {
 	})
 }
 
+func TestHooksNonInteractive(t *testing.T) {
+	t.Run("disabled by default", func(t *testing.T) {
+		config.SetDir(t.TempDir())
+		cli, err := NewDockerCli()
+		assert.NilError(t, err)
+		assert.Check(t, !cli.HooksNonInteractive())
+		assert.Check(t, is.Equal(cli.HooksOutput(), ""))
+	})
+
+	t.Run("enabled in configFile", func(t *testing.T) {
+		configFile := `{
+    "features": {
+      "hooks-non-interactive": "true",
+      "hooks-output": "/var/log/docker-hooks.log"
+    }}`
+		config.SetDir(t.TempDir())
+		err := os.WriteFile(filepath.Join(config.Dir(), "config.json"), []byte(configFile), 0o600)
+		assert.NilError(t, err)
+		cli, err := NewDockerCli()
+		assert.NilError(t, err)
+		assert.Check(t, cli.HooksNonInteractive())
+		assert.Check(t, is.Equal(cli.HooksOutput(), "/var/log/docker-hooks.log"))
+	})
+
+	t.Run("env var overrides configFile", func(t *testing.T) {
+		configFile := `{
+    "features": {
+      "hooks-non-interactive": "true",
+      "hooks-output": "/var/log/docker-hooks.log"
+    }}`
+		t.Setenv("DOCKER_CLI_HOOKS_NON_INTERACTIVE", "false")
+		t.Setenv("DOCKER_CLI_HOOKS_OUTPUT", "stderr")
+		config.SetDir(t.TempDir())
+		err := os.WriteFile(filepath.Join(config.Dir(), "config.json"), []byte(configFile), 0o600)
+		assert.NilError(t, err)
+		cli, err := NewDockerCli()
+		assert.NilError(t, err)
+		assert.Check(t, !cli.HooksNonInteractive())
+		assert.Check(t, is.Equal(cli.HooksOutput(), ""))
+	})
+}
+
 func TestSetGoDebug(t *testing.T) {
 	t.Run("GODEBUG already set", func(t *testing.T) {
 		t.Setenv("GODEBUG", "val1,val2")
@@ -389,6 +457,55 @@ func TestSetGoDebug(t *testing.T) {
 	})
 }
 
+func TestInitializeContextConfig(t *testing.T) {
+	config.SetDir(t.TempDir())
+	err := os.WriteFile(filepath.Join(config.Dir(), "config.json"), []byte(`{"psFormat": "table {{.ID}}", "detachKeys": "ctrl-x"}`), 0o600)
+	assert.NilError(t, err)
+
+	s := store.New(config.ContextStoreDir(), DefaultContextStoreConfig())
+	err = s.CreateOrUpdate(store.Metadata{
+		Name: "production",
+		Metadata: DockerContext{
+			AdditionalFields: map[string]any{
+				CLIConfigField: map[string]any{"psFormat": "table {{.Names}}"},
+			},
+		},
+	})
+	assert.NilError(t, err)
+	err = s.CreateOrUpdate(store.Metadata{
+		Name: "invalid",
+		Metadata: DockerContext{
+			AdditionalFields: map[string]any{
+				CLIConfigField: map[string]any{"credsStore": "example"},
+			},
+		},
+	})
+	assert.NilError(t, err)
+
+	t.Run("overrides", func(t *testing.T) {
+		errBuf := new(bytes.Buffer)
+		cli, err := NewDockerCli(WithErrorStream(errBuf))
+		assert.NilError(t, err)
+		opts := flags.NewClientOptions()
+		opts.Context = "production"
+		assert.NilError(t, cli.Initialize(opts))
+		assert.Check(t, is.Equal(cli.ConfigFile().PsFormat, "table {{.Names}}"))
+		assert.Check(t, is.Equal(cli.ConfigFile().DetachKeys, "ctrl-x"))
+		assert.Check(t, is.Equal(errBuf.String(), ""))
+	})
+
+	t.Run("invalid overrides", func(t *testing.T) {
+		errBuf := new(bytes.Buffer)
+		cli, err := NewDockerCli(WithErrorStream(errBuf))
+		assert.NilError(t, err)
+		opts := flags.NewClientOptions()
+		opts.Context = "invalid"
+		assert.NilError(t, cli.Initialize(opts))
+		assert.Check(t, is.Equal(cli.ConfigFile().PsFormat, "table {{.ID}}"))
+		assert.Check(t, is.Equal(errBuf.String(), `WARNING: ignoring the CLI configuration of context "invalid": setting "credsStore" cannot be overridden`+"\n"))
+	})
+}
+
 func TestNewDockerCliWithCustomUserAgent(t *testing.T) {
 	var received string
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -7,11 +7,13 @@
 	_ "github.com/docker/cli/cli/command/config"
 	_ "github.com/docker/cli/cli/command/container"
 	_ "github.com/docker/cli/cli/command/context"
+	_ "github.com/docker/cli/cli/command/credentials"
 	_ "github.com/docker/cli/cli/command/image"
 	_ "github.com/docker/cli/cli/command/manifest"
 	_ "github.com/docker/cli/cli/command/network"
 	_ "github.com/docker/cli/cli/command/node"
 	_ "github.com/docker/cli/cli/command/plugin"
+	_ "github.com/docker/cli/cli/command/plugincli"
 	_ "github.com/docker/cli/cli/command/registry"
 	_ "github.com/docker/cli/cli/command/secret"
 	_ "github.com/docker/cli/cli/command/service"
@@ -4,6 +4,7 @@
 package container
 
 import (
+	"slices"
 	"strings"
 	"sync"
 
@@ -329,6 +330,66 @@ func containerNames(dockerCLI completion.APIClientProvider, cmd *cobra.Command,
 	return names
 }
 
+// logsFilterKeys are the filters that can be used with "docker logs --filter".
+var logsFilterKeys = []string{
+	"ancestor", "before", "expose", "exited", "health", "id", "is-task", "isolation",
+	"label", "name", "network", "project", "publish", "since", "status", "volume",
+}
+
+// completeLogsFilters provides completion for the filters that can be used
+// with "docker logs --filter".
+func completeLogsFilters(dockerCLI completion.APIClientProvider) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		key, _, ok := strings.Cut(toComplete, "=")
+		if !ok {
+			return postfixWith("=", logsFilterKeys), cobra.ShellCompDirectiveNoSpace
+		}
+		var values []string
+		switch key {
+		case "ancestor":
+			values, _ = completion.ImageNames(dockerCLI, -1)(cmd, nil, "")
+		case "before", "id", "name", "since":
+			values = containerNames(dockerCLI, cmd, nil, "")
+		case "health":
+			values = []string{"starting", "healthy", "unhealthy", "none"}
+		case "is-task":
+			values = []string{"true", "false"}
+		case "network":
+			values, _ = completion.NetworkNames(dockerCLI)(cmd, nil, "")
+		case "project":
+			values = composeProjects(dockerCLI, cmd)
+		case "status":
+			values = []string{"created", "restarting", "running", "removing", "paused", "exited", "dead"}
+		case "volume":
+			values, _ = completion.VolumeNames(dockerCLI)(cmd, nil, "")
+		case "expose", "exited", "isolation", "label", "publish":
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		default:
+			return postfixWith("=", logsFilterKeys), cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+		}
+		return prefixWith(key+"=", values), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// composeProjects contacts the API to get the names of the Compose projects
+// of containers. In case of an error, an empty list is returned.
+func composeProjects(dockerCLI completion.APIClientProvider, cmd *cobra.Command) []string {
+	res, err := dockerCLI.Client().ContainerList(cmd.Context(), client.ContainerListOptions{
+		All:     true,
+		Filters: make(client.Filters).Add("label", composeProjectLabel),
+	})
+	if err != nil {
+		return []string{}
+	}
+	projects := []string{}
+	for _, ctr := range res.Items {
+		if p := ctr.Labels[composeProjectLabel]; p != "" && !slices.Contains(projects, p) {
+			projects = append(projects, p)
+		}
+	}
+	return projects
+}
+
 // prefixWith prefixes every element in the slice with the given prefix.
 func prefixWith(prefix string, values []string) []string {
 	result := make([]string, len(values))
@@ -176,3 +176,39 @@ func TestCompleteLinks(t *testing.T) {
 		})
 	}
 }
+
+func TestCompleteLogsFilters(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		containerListFunc: func(options client.ContainerListOptions) (client.ContainerListResult, error) {
+			if options.Filters["label"]["com.docker.compose.project"] {
+				return client.ContainerListResult{
+					Items: []container.Summary{
+						{Labels: map[string]string{"com.docker.compose.project": "myapp"}},
+						{Labels: map[string]string{"com.docker.compose.project": "myapp"}},
+						{Labels: map[string]string{"com.docker.compose.project": "other"}},
+					},
+				}, nil
+			}
+			return client.ContainerListResult{
+				Items: []container.Summary{*builders.Container("c1")},
+			}, nil
+		},
+	})
+	cmd := newLogsCommand(cli)
+
+	completions, directive := completeLogsFilters(cli)(cmd, nil, "")
+	assert.Check(t, is.DeepEqual(completions, postfixWith("=", logsFilterKeys)))
+	assert.Check(t, is.Equal(directive, cobra.ShellCompDirectiveNoSpace))
+
+	completions, directive = completeLogsFilters(cli)(cmd, nil, "project=")
+	assert.Check(t, is.DeepEqual(completions, []string{"project=myapp", "project=other"}))
+	assert.Check(t, is.Equal(directive, cobra.ShellCompDirectiveNoFileComp))
+
+	completions, directive = completeLogsFilters(cli)(cmd, nil, "name=")
+	assert.Check(t, is.DeepEqual(completions, []string{"name=c1"}))
+	assert.Check(t, is.Equal(directive, cobra.ShellCompDirectiveNoFileComp))
+
+	completions, directive = completeLogsFilters(cli)(cmd, nil, "status=ru")
+	assert.Check(t, is.Contains(completions, "status=running"))
+	assert.Check(t, is.Equal(directive, cobra.ShellCompDirectiveNoFileComp))
+}
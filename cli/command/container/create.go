@@ -58,6 +58,7 @@ func newCreateCommand(dockerCLI command.Cli) *cobra.Command {
 			if len(args) > 1 {
 				copts.Args = args[1:]
 			}
+			options.platform = command.DefaultPlatform(dockerCLI, options.platform)
 			return runCreate(cmd.Context(), dockerCLI, cmd.Flags(), &options, copts)
 		},
 		Annotations: map[string]string{
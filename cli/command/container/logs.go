@@ -1,14 +1,27 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
 package container
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/internal/tui"
+	"github.com/docker/cli/opts"
 	"github.com/moby/moby/api/pkg/stdcopy"
 	"github.com/moby/moby/client"
+	"github.com/morikuni/aec"
 	"github.com/spf13/cobra"
 )
 
@@ -19,21 +32,27 @@ type logsOptions struct {
 	timestamps bool
 	details    bool
 	tail       string
+	filter     opts.FilterOpt
 
-	container string
+	containers []string
 }
 
 // newLogsCommand creates a new cobra.Command for "docker container logs"
 func newLogsCommand(dockerCLI command.Cli) *cobra.Command {
-	var opts logsOptions
+	options := logsOptions{filter: opts.NewFilterOpt()}
 
 	cmd := &cobra.Command{
-		Use:   "logs [OPTIONS] CONTAINER",
-		Short: "Fetch the logs of a container",
-		Args:  cli.ExactArgs(1),
+		Use:   "logs [OPTIONS] CONTAINER [CONTAINER...]",
+		Short: "Fetch the logs of one or more containers",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(options.filter.Value()) > 0 {
+				return nil
+			}
+			return cli.RequiresMinArgs(1)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			opts.container = args[0]
-			return runLogs(cmd.Context(), dockerCLI, &opts)
+			options.containers = args
+			return runLogs(cmd.Context(), dockerCLI, &options)
 		},
 		Annotations: map[string]string{
 			"aliases": "docker container logs, docker logs",
@@ -43,18 +62,25 @@ func newLogsCommand(dockerCLI command.Cli) *cobra.Command {
 	}
 
 	flags := cmd.Flags()
-	flags.BoolVarP(&opts.follow, "follow", "f", false, "Follow log output")
-	flags.StringVar(&opts.since, "since", "", `Show logs since timestamp (e.g. "2013-01-02T13:23:37Z") or relative (e.g. "42m" for 42 minutes)`)
-	flags.StringVar(&opts.until, "until", "", `Show logs before a timestamp (e.g. "2013-01-02T13:23:37Z") or relative (e.g. "42m" for 42 minutes)`)
+	flags.BoolVarP(&options.follow, "follow", "f", false, "Follow log output")
+	flags.StringVar(&options.since, "since", "", `Show logs since timestamp (e.g. "2013-01-02T13:23:37Z") or relative (e.g. "42m" for 42 minutes)`)
+	flags.StringVar(&options.until, "until", "", `Show logs before a timestamp (e.g. "2013-01-02T13:23:37Z") or relative (e.g. "42m" for 42 minutes)`)
 	flags.SetAnnotation("until", "version", []string{"1.35"})
-	flags.BoolVarP(&opts.timestamps, "timestamps", "t", false, "Show timestamps")
-	flags.BoolVar(&opts.details, "details", false, "Show extra details provided to logs")
-	flags.StringVarP(&opts.tail, "tail", "n", "all", "Number of lines to show from the end of the logs")
+	flags.BoolVarP(&options.timestamps, "timestamps", "t", false, "Show timestamps")
+	flags.BoolVar(&options.details, "details", false, "Show extra details provided to logs")
+	flags.StringVarP(&options.tail, "tail", "n", "all", "Number of lines to show from the end of the logs")
+	flags.Var(&options.filter, "filter", `Fetch the logs of the containers matching the filter (e.g. "label=foo", or "project=myapp" for a Compose project)`)
+
+	_ = cmd.RegisterFlagCompletionFunc("filter", completeLogsFilters(dockerCLI))
 	return cmd
 }
 
 func runLogs(ctx context.Context, dockerCli command.Cli, opts *logsOptions) error {
-	c, err := dockerCli.Client().ContainerInspect(ctx, opts.container, client.ContainerInspectOptions{})
+	if len(opts.containers) != 1 || len(opts.filter.Value()) > 0 {
+		return runMultiLogs(ctx, dockerCli, opts)
+	}
+
+	c, err := dockerCli.Client().ContainerInspect(ctx, opts.containers[0], client.ContainerInspectOptions{})
 	if err != nil {
 		return err
 	}
@@ -81,3 +107,324 @@ func runLogs(ctx context.Context, dockerCli command.Cli, opts *logsOptions) erro
 	}
 	return err
 }
+
+// logColors are the colors used for the container names when showing the
+// logs of multiple containers.
+var logColors = []aec.ANSI{
+	aec.CyanF,
+	aec.YellowF,
+	aec.GreenF,
+	aec.MagentaF,
+	aec.BlueF,
+	aec.LightCyanF,
+	aec.LightYellowF,
+	aec.LightGreenF,
+	aec.LightMagentaF,
+	aec.LightBlueF,
+}
+
+// logSource is a container to fetch logs from.
+type logSource struct {
+	id     string
+	name   string
+	tty    bool
+	prefix string
+}
+
+// logLine is a single line of a container's logs.
+type logLine struct {
+	source    *logSource
+	stderr    bool
+	timestamp time.Time
+	// text is the line, including the timestamp if timestamps are shown.
+	text []byte
+}
+
+// runMultiLogs shows the logs of multiple containers. Lines are prefixed with
+// the name of the container, and interleaved by their timestamp. When
+// following the logs, lines are ordered within the followWindow.
+func runMultiLogs(ctx context.Context, dockerCLI command.Cli, opts *logsOptions) error {
+	sources, err := getLogSources(ctx, dockerCLI.Client(), opts)
+	if err != nil {
+		return err
+	}
+
+	out := tui.NewOutput(dockerCLI.Out())
+	var width int
+	for _, s := range sources {
+		width = max(width, len(s.name))
+	}
+	for i, s := range sources {
+		s.prefix = out.Color(logColors[i%len(logColors)]).Apply(fmt.Sprintf("%-*s |", width, s.name)) + " "
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		errs     []error
+		channels = make([]chan logLine, len(sources))
+	)
+	for i, s := range sources {
+		channels[i] = make(chan logLine, 100)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(channels[i])
+			if err := streamLogLines(ctx, dockerCLI.Client(), s, opts, channels[i]); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("%s: %w", s.name, err))
+				mu.Unlock()
+			}
+		}()
+	}
+
+	if opts.follow {
+		printFollowedLogs(dockerCLI, channels)
+	} else {
+		printMergedLogs(dockerCLI, channels)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// getLogSources returns the containers given as arguments, and those matching
+// the filter, if set.
+func getLogSources(ctx context.Context, apiClient client.APIClient, opts *logsOptions) ([]*logSource, error) {
+	refs := slices.Clone(opts.containers)
+	if len(opts.filter.Value()) > 0 {
+		res, err := apiClient.ContainerList(ctx, client.ContainerListOptions{
+			All:     true,
+			Filters: logsFilters(opts.filter.Value()),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(res.Items) == 0 && len(refs) == 0 {
+			return nil, errors.New("no containers found matching the filter")
+		}
+		var ids []string
+		for _, c := range res.Items {
+			ids = append(ids, c.ID)
+		}
+		refs = append(refs, ids...)
+	}
+
+	var sources []*logSource
+	for _, ref := range refs {
+		res, err := apiClient.ContainerInspect(ctx, ref, client.ContainerInspectOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if slices.ContainsFunc(sources, func(s *logSource) bool { return s.id == res.Container.ID }) {
+			continue
+		}
+		sources = append(sources, &logSource{
+			id:   res.Container.ID,
+			name: strings.TrimPrefix(res.Container.Name, "/"),
+			tty:  res.Container.Config != nil && res.Container.Config.Tty,
+		})
+	}
+	return sources, nil
+}
+
+// composeProjectLabel is the label Compose sets on containers to the name of
+// their project.
+const composeProjectLabel = "com.docker.compose.project"
+
+// logsFilters returns the filters to list containers with. In addition to
+// the filters supported by the API, the "project" filter selects containers
+// that are part of the given Compose project.
+func logsFilters(filters client.Filters) client.Filters {
+	result := make(client.Filters)
+	for key, values := range filters {
+		for value := range values {
+			if key == "project" {
+				result.Add("label", composeProjectLabel+"="+value)
+			} else {
+				result.Add(key, value)
+			}
+		}
+	}
+	return result
+}
+
+// streamLogLines fetches the logs of a container, and sends them to lines
+// one line at a time.
+func streamLogLines(ctx context.Context, apiClient client.APIClient, source *logSource, opts *logsOptions, lines chan<- logLine) error {
+	resp, err := apiClient.ContainerLogs(ctx, source.id, client.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Since:      opts.since,
+		Until:      opts.until,
+		// Timestamps are always requested to interleave the lines of the
+		// containers, and removed before printing if not requested.
+		Timestamps: true,
+		Follow:     opts.follow,
+		Tail:       opts.tail,
+		Details:    opts.details,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Close() }()
+
+	stdout := &logLineWriter{source: source, timestamps: opts.timestamps, lines: lines}
+	stderr := &logLineWriter{source: source, timestamps: opts.timestamps, lines: lines, stderr: true}
+	if source.tty {
+		_, err = io.Copy(stdout, resp)
+	} else {
+		_, err = stdcopy.StdCopy(stdout, stderr, resp)
+	}
+	stdout.flush()
+	stderr.flush()
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// logLineWriter splits the logs written to it into lines.
+type logLineWriter struct {
+	source     *logSource
+	stderr     bool
+	timestamps bool
+	lines      chan<- logLine
+	buf        []byte
+}
+
+func (w *logLineWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.send(w.buf[:i+1])
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush sends the remaining output, if it did not end with a newline.
+func (w *logLineWriter) flush() {
+	if len(w.buf) > 0 {
+		w.send(append(w.buf, '\n'))
+		w.buf = nil
+	}
+}
+
+func (w *logLineWriter) send(line []byte) {
+	l := logLine{source: w.source, stderr: w.stderr, text: slices.Clone(line)}
+	if ts, rest, ok := bytes.Cut(line, []byte{' '}); ok {
+		if t, err := time.Parse(time.RFC3339Nano, string(ts)); err == nil {
+			l.timestamp = t
+			if !w.timestamps {
+				l.text = slices.Clone(rest)
+			}
+		}
+	}
+	w.lines <- l
+}
+
+func printLogLine(dockerCLI command.Cli, l logLine) {
+	var out io.Writer = dockerCLI.Out()
+	if l.stderr {
+		out = dockerCLI.Err()
+	}
+	_, _ = io.WriteString(out, l.source.prefix)
+	_, _ = out.Write(l.text)
+}
+
+// followWindow is the time lines are held back when following the logs of
+// multiple containers, to print the lines received in that window ordered
+// by their timestamp.
+var followWindow = 100 * time.Millisecond
+
+// receivedLine is a line that is held back until the followWindow expires.
+type receivedLine struct {
+	logLine
+	received time.Time
+}
+
+// printFollowedLogs prints lines as they are received. Lines are held back
+// for the followWindow, so that lines of different containers that are
+// received within the window are printed ordered by their timestamp. Lines
+// that are received later than that may be printed out of order.
+func printFollowedLogs(dockerCLI command.Cli, channels []chan logLine) {
+	merged := make(chan logLine)
+	var wg sync.WaitGroup
+	for _, ch := range channels {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for l := range ch {
+				merged <- l
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(merged)
+	}()
+
+	ticker := time.NewTicker(followWindow / 2)
+	defer ticker.Stop()
+
+	var pending []receivedLine
+	flush := func(cutoff time.Time) {
+		slices.SortStableFunc(pending, func(a, b receivedLine) int {
+			return a.timestamp.Compare(b.timestamp)
+		})
+		var n int
+		for _, l := range pending {
+			if l.received.After(cutoff) {
+				break
+			}
+			printLogLine(dockerCLI, l.logLine)
+			n++
+		}
+		pending = pending[n:]
+	}
+	for {
+		select {
+		case l, ok := <-merged:
+			if !ok {
+				flush(time.Now())
+				return
+			}
+			pending = append(pending, receivedLine{logLine: l, received: time.Now()})
+		case now := <-ticker.C:
+			flush(now.Add(-followWindow))
+		}
+	}
+}
+
+// printMergedLogs prints lines ordered by their timestamp. The lines of each
+// container are already ordered, so the next line to print is the oldest of
+// the next line of each container.
+func printMergedLogs(dockerCLI command.Cli, channels []chan logLine) {
+	heads := make([]*logLine, len(channels))
+	for {
+		next := -1
+		for i, ch := range channels {
+			if heads[i] == nil && ch != nil {
+				if l, ok := <-ch; ok {
+					heads[i] = &l
+				} else {
+					channels[i] = nil
+				}
+			}
+			if heads[i] != nil && (next < 0 || heads[i].timestamp.Before(heads[next].timestamp)) {
+				next = i
+			}
+		}
+		if next < 0 {
+			return
+		}
+		printLogLine(dockerCLI, *heads[next])
+		heads[next] = nil
+	}
+}
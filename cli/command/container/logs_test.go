@@ -1,10 +1,15 @@
 package container
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
+	"io"
+	"strings"
 	"testing"
 
 	"github.com/docker/cli/internal/test"
+	"github.com/moby/moby/api/pkg/stdcopy"
 	"github.com/moby/moby/api/types/container"
 	"github.com/moby/moby/client"
 	"gotest.tools/v3/assert"
@@ -32,7 +37,7 @@ func TestRunLogs(t *testing.T) {
 		{
 			doc:         "successful logs",
 			expectedOut: "foo",
-			options:     &logsOptions{},
+			options:     &logsOptions{containers: []string{"foo"}},
 			client: &fakeClient{
 				logFunc: func(container string, opts client.ContainerLogsOptions) (client.ContainerLogsResult, error) {
 					// FIXME(thaJeztah): how to mock this?
@@ -58,3 +63,121 @@ func TestRunLogs(t *testing.T) {
 		})
 	}
 }
+
+func TestRunLogsMultipleContainers(t *testing.T) {
+	logs := map[string]string{
+		"id-web": "2024-01-01T00:00:01.000000000Z web 1\n2024-01-01T00:00:03.000000000Z web 2\n",
+		"id-db":  "2024-01-01T00:00:02.000000000Z db 1\n2024-01-01T00:00:04.000000000Z db 2",
+	}
+	var listOptions client.ContainerListOptions
+	cli := test.NewFakeCli(&fakeClient{
+		containerListFunc: func(options client.ContainerListOptions) (client.ContainerListResult, error) {
+			listOptions = options
+			return client.ContainerListResult{Items: []container.Summary{{ID: "id-db"}, {ID: "id-web"}}}, nil
+		},
+		inspectFunc: func(ref string) (client.ContainerInspectResult, error) {
+			name := strings.TrimPrefix(ref, "id-")
+			return client.ContainerInspectResult{
+				Container: container.InspectResponse{
+					ID:     "id-" + name,
+					Name:   "/myapp-" + name + "-1",
+					Config: &container.Config{Tty: true},
+				},
+			}, nil
+		},
+		logFunc: func(containerID string, options client.ContainerLogsOptions) (client.ContainerLogsResult, error) {
+			assert.Check(t, options.Timestamps)
+			return mockContainerLogsResult(logs[containerID]), nil
+		},
+	})
+
+	cmd := newLogsCommand(cli)
+	cmd.SetArgs([]string{"--filter", "project=myapp", "web"})
+	assert.NilError(t, cmd.Execute())
+
+	assert.Check(t, is.DeepEqual(listOptions.Filters, client.Filters{"label": {"com.docker.compose.project=myapp": true}}))
+	assert.Check(t, listOptions.All)
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), `myapp-web-1 | web 1
+myapp-db-1  | db 1
+myapp-web-1 | web 2
+myapp-db-1  | db 2
+`))
+}
+
+func TestRunLogsMultipleContainersFollow(t *testing.T) {
+	logs := map[string]string{
+		"id-web": "2024-01-01T00:00:01.000000000Z web 1\n2024-01-01T00:00:03.000000000Z web 2\n",
+		"id-db":  "2024-01-01T00:00:02.000000000Z db 1\n2024-01-01T00:00:04.000000000Z db 2\n",
+	}
+	cli := test.NewFakeCli(&fakeClient{
+		inspectFunc: func(ref string) (client.ContainerInspectResult, error) {
+			return client.ContainerInspectResult{
+				Container: container.InspectResponse{
+					ID:     "id-" + ref,
+					Name:   "/" + ref,
+					Config: &container.Config{Tty: true},
+				},
+			}, nil
+		},
+		logFunc: func(containerID string, options client.ContainerLogsOptions) (client.ContainerLogsResult, error) {
+			assert.Check(t, options.Follow)
+			return mockContainerLogsResult(logs[containerID]), nil
+		},
+	})
+
+	cmd := newLogsCommand(cli)
+	cmd.SetArgs([]string{"--follow", "--timestamps", "web", "db"})
+	assert.NilError(t, cmd.Execute())
+
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), `web | 2024-01-01T00:00:01.000000000Z web 1
+db  | 2024-01-01T00:00:02.000000000Z db 1
+web | 2024-01-01T00:00:03.000000000Z web 2
+db  | 2024-01-01T00:00:04.000000000Z db 2
+`))
+}
+
+func TestRunLogsMultipleContainersStderr(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		inspectFunc: func(ref string) (client.ContainerInspectResult, error) {
+			return client.ContainerInspectResult{
+				Container: container.InspectResponse{
+					ID:     ref,
+					Name:   "/" + ref,
+					Config: &container.Config{},
+				},
+			}, nil
+		},
+		logFunc: func(containerID string, _ client.ContainerLogsOptions) (client.ContainerLogsResult, error) {
+			var buf bytes.Buffer
+			writeStdFrame(&buf, stdcopy.Stdout, "2024-01-01T00:00:01.000000000Z "+containerID+" out\n")
+			writeStdFrame(&buf, stdcopy.Stderr, "2024-01-01T00:00:02.000000000Z "+containerID+" err\n")
+			return io.NopCloser(&buf), nil
+		},
+	})
+
+	err := runLogs(context.TODO(), cli, &logsOptions{containers: []string{"one", "two"}, timestamps: true})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), `one | 2024-01-01T00:00:01.000000000Z one out
+two | 2024-01-01T00:00:01.000000000Z two out
+`))
+	assert.Check(t, is.Equal(cli.ErrBuffer().String(), `one | 2024-01-01T00:00:02.000000000Z one err
+two | 2024-01-01T00:00:02.000000000Z two err
+`))
+}
+
+// writeStdFrame writes data as a multiplexed stream, as returned by the API
+// for containers without a TTY.
+func writeStdFrame(buf *bytes.Buffer, stream stdcopy.StdType, data string) {
+	header := []byte{byte(stream), 0, 0, 0, 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(header[4:], uint32(len(data)))
+	buf.Write(header)
+	buf.WriteString(data)
+}
+
+func TestLogsRequiresContainerOrFilter(t *testing.T) {
+	cmd := newLogsCommand(test.NewFakeCli(&fakeClient{}))
+	cmd.SetArgs([]string{})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Check(t, is.ErrorContains(cmd.Execute(), "requires at least 1 argument"))
+}
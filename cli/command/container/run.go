@@ -43,6 +43,7 @@ func newRunCommand(dockerCLI command.Cli) *cobra.Command {
 			if len(args) > 1 {
 				copts.Args = args[1:]
 			}
+			options.platform = command.DefaultPlatform(dockerCLI, options.platform)
 			return runRun(cmd.Context(), dockerCLI, cmd.Flags(), &options, copts)
 		},
 		ValidArgsFunction: completion.ImageNames(dockerCLI, 1),
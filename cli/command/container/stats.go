@@ -62,6 +62,30 @@ type StatsOptions struct {
 	// above), but may require daemon-side validation as the list of accepted
 	// filters can differ between daemon- and API versions.
 	Filters client.Filters
+
+	// Record is the path of a file to record every sample of the stats to,
+	// instead of presenting a live stream of the stats. It cannot be combined
+	// with NoStream.
+	Record string
+
+	// RecordFormat is the format in which samples are recorded; "json" (the
+	// default) writes a JSON object per line, and "csv" writes CSV with a
+	// header row.
+	RecordFormat string
+
+	// Duration is the duration for which to sample the stats when recording,
+	// or showing a summary. If zero, stats are sampled until interrupted.
+	Duration time.Duration
+
+	// Interval is the interval at which stats are sampled when recording, or
+	// showing a summary. It defaults to one second.
+	Interval time.Duration
+
+	// Summary enables printing the minimum, average, maximum, and 95th
+	// percentile of the stats of each container over the sampling window,
+	// instead of presenting a live stream of the stats. It cannot be combined
+	// with NoStream.
+	Summary bool
 }
 
 // newStatsCommand creates a new [cobra.Command] for "docker container stats".
@@ -88,6 +112,11 @@ func newStatsCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.BoolVar(&options.NoStream, "no-stream", false, "Disable streaming stats and only pull the first result")
 	flags.BoolVar(&options.NoTrunc, "no-trunc", false, "Do not truncate output")
 	flags.StringVar(&options.Format, "format", "", flagsHelper.FormatHelp)
+	flags.StringVar(&options.Record, "record", "", "Record every sample to a file instead of showing a live stream")
+	flags.StringVar(&options.RecordFormat, "record-format", recordFormatJSON, `Format of the recorded samples ("json", "csv")`)
+	flags.DurationVar(&options.Duration, "duration", 0, "Duration to sample stats for with --record or --summary (default until interrupted)")
+	flags.DurationVar(&options.Interval, "interval", defaultStatsInterval, "Interval between samples with --record or --summary")
+	flags.BoolVar(&options.Summary, "summary", false, "Show the min, avg, max, and p95 of the stats of each container over the sampling window")
 	return cmd
 }
 
@@ -107,6 +136,22 @@ func newStatsCommand(dockerCLI command.Cli) *cobra.Command {
 //
 //nolint:gocyclo
 func RunStats(ctx context.Context, dockerCLI command.Cli, options *StatsOptions) error {
+	sampling := options.Record != "" || options.Summary
+	if sampling && options.NoStream {
+		return errors.New("--record and --summary cannot be used with --no-stream")
+	}
+	if !sampling && options.Duration != 0 {
+		return errors.New("--duration can only be used with --record or --summary")
+	}
+	if options.Duration < 0 || options.Interval < 0 {
+		return errors.New("--duration and --interval must not be negative")
+	}
+	if options.Record != "" {
+		if err := validateRecordFormat(options.RecordFormat); err != nil {
+			return err
+		}
+	}
+
 	apiClient := dockerCLI.Client()
 
 	// Get the daemonOSType to handle platform-specific stats fields.
@@ -276,6 +321,10 @@ func RunStats(ctx context.Context, dockerCLI command.Cli, options *StatsOptions)
 		}
 	}
 
+	if sampling {
+		return sampleStats(ctx, dockerCLI.Out(), options, &cStats, closeChan, showAll)
+	}
+
 	format := options.Format
 	if format == "" {
 		if len(dockerCLI.ConfigFile().StatsFormat) > 0 {
@@ -0,0 +1,303 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package container
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/docker/go-units"
+)
+
+// Formats supported for recording stats.
+const (
+	recordFormatJSON = "json"
+	recordFormatCSV  = "csv"
+)
+
+const defaultStatsInterval = time.Second
+
+// statsSample is a single sample of a container's stats, as recorded with
+// the "--record" option.
+type statsSample struct {
+	Time             time.Time `json:"time"`
+	ID               string    `json:"id"`
+	Name             string    `json:"name"`
+	CPUPercentage    float64   `json:"cpu_percent"`
+	Memory           float64   `json:"memory_usage"`
+	MemoryLimit      float64   `json:"memory_limit"`
+	MemoryPercentage float64   `json:"memory_percent"`
+	NetworkRx        float64   `json:"network_rx"`
+	NetworkTx        float64   `json:"network_tx"`
+	BlockRead        float64   `json:"block_read"`
+	BlockWrite       float64   `json:"block_write"`
+	PidsCurrent      uint64    `json:"pids"`
+}
+
+var statsSampleCSVHeader = []string{
+	"time", "id", "name", "cpu_percent", "memory_usage", "memory_limit", "memory_percent",
+	"network_rx", "network_tx", "block_read", "block_write", "pids",
+}
+
+func newStatsSample(t time.Time, s StatsEntry) statsSample {
+	return statsSample{
+		Time:             t,
+		ID:               s.ID,
+		Name:             strings.TrimPrefix(s.Name, "/"),
+		CPUPercentage:    s.CPUPercentage,
+		Memory:           s.Memory,
+		MemoryLimit:      s.MemoryLimit,
+		MemoryPercentage: s.MemoryPercentage,
+		NetworkRx:        s.NetworkRx,
+		NetworkTx:        s.NetworkTx,
+		BlockRead:        s.BlockRead,
+		BlockWrite:       s.BlockWrite,
+		PidsCurrent:      s.PidsCurrent,
+	}
+}
+
+func (s statsSample) csvRecord() []string {
+	formatFloat := func(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) }
+	return []string{
+		s.Time.Format(time.RFC3339Nano),
+		s.ID,
+		s.Name,
+		formatFloat(s.CPUPercentage),
+		formatFloat(s.Memory),
+		formatFloat(s.MemoryLimit),
+		formatFloat(s.MemoryPercentage),
+		formatFloat(s.NetworkRx),
+		formatFloat(s.NetworkTx),
+		formatFloat(s.BlockRead),
+		formatFloat(s.BlockWrite),
+		strconv.FormatUint(s.PidsCurrent, 10),
+	}
+}
+
+// statsRecorder writes samples as JSON lines or CSV.
+type statsRecorder struct {
+	enc *json.Encoder
+	csv *csv.Writer
+}
+
+func validateRecordFormat(format string) error {
+	switch format {
+	case "", recordFormatJSON, recordFormatCSV:
+		return nil
+	default:
+		return fmt.Errorf("invalid record format %q: only %s and %s are supported", format, recordFormatJSON, recordFormatCSV)
+	}
+}
+
+func newStatsRecorder(w io.Writer, format string) (*statsRecorder, error) {
+	if err := validateRecordFormat(format); err != nil {
+		return nil, err
+	}
+	if format == recordFormatCSV {
+		r := &statsRecorder{csv: csv.NewWriter(w)}
+		return r, r.csv.Write(statsSampleCSVHeader)
+	}
+	return &statsRecorder{enc: json.NewEncoder(w)}, nil
+}
+
+func (r *statsRecorder) write(s statsSample) error {
+	if r.csv != nil {
+		return r.csv.Write(s.csvRecord())
+	}
+	return r.enc.Encode(s)
+}
+
+func (r *statsRecorder) flush() error {
+	if r.csv != nil {
+		r.csv.Flush()
+		return r.csv.Error()
+	}
+	return nil
+}
+
+// statsSummary collects the samples of each container to report their
+// minimum, average, maximum, and 95th percentile. Network and block IO are
+// reported as the rate between consecutive samples, as they are counters.
+type statsSummary struct {
+	containers []*containerSummary
+}
+
+type containerSummary struct {
+	id, name   string
+	last       statsSample
+	cpu, mem   []float64
+	netRx      []float64
+	netTx      []float64
+	blockRead  []float64
+	blockWrite []float64
+}
+
+func (s *statsSummary) add(sample statsSample) {
+	idx := slices.IndexFunc(s.containers, func(c *containerSummary) bool { return c.id == sample.ID })
+	if idx < 0 {
+		s.containers = append(s.containers, &containerSummary{id: sample.ID, name: sample.Name})
+		idx = len(s.containers) - 1
+	}
+	c := s.containers[idx]
+	c.cpu = append(c.cpu, sample.CPUPercentage)
+	c.mem = append(c.mem, sample.Memory)
+	if !c.last.Time.IsZero() {
+		if elapsed := sample.Time.Sub(c.last.Time).Seconds(); elapsed > 0 {
+			rate := func(cur, prev float64) float64 { return math.Max(cur-prev, 0) / elapsed }
+			c.netRx = append(c.netRx, rate(sample.NetworkRx, c.last.NetworkRx))
+			c.netTx = append(c.netTx, rate(sample.NetworkTx, c.last.NetworkTx))
+			c.blockRead = append(c.blockRead, rate(sample.BlockRead, c.last.BlockRead))
+			c.blockWrite = append(c.blockWrite, rate(sample.BlockWrite, c.last.BlockWrite))
+		}
+	}
+	c.last = sample
+}
+
+// write writes the summary as a table.
+func (s *statsSummary) write(out io.Writer, trunc bool) error {
+	formatPercent := formatPercentage
+	formatBytes := func(v float64) string { return units.BytesSize(v) }
+	formatRate := func(v float64) string { return units.HumanSizeWithPrecision(v, 3) + "/s" }
+
+	tw := tabwriter.NewWriter(out, 10, 1, 3, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "CONTAINER ID\tNAME\tMETRIC\tMIN\tAVG\tMAX\tP95")
+	for _, c := range s.containers {
+		id := c.id
+		if trunc && len(id) > 12 {
+			id = id[:12]
+		}
+		metrics := []struct {
+			name   string
+			values []float64
+			format func(float64) string
+		}{
+			{name: "CPU %", values: c.cpu, format: formatPercent},
+			{name: "MEM USAGE", values: c.mem, format: formatBytes},
+			{name: "NET RX", values: c.netRx, format: formatRate},
+			{name: "NET TX", values: c.netTx, format: formatRate},
+			{name: "BLOCK READ", values: c.blockRead, format: formatRate},
+			{name: "BLOCK WRITE", values: c.blockWrite, format: formatRate},
+		}
+		for _, m := range metrics {
+			if len(m.values) == 0 {
+				_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", id, c.name, m.name, noValue, noValue, noValue, noValue)
+				continue
+			}
+			minV, avgV, maxV, p95 := summarize(m.values)
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", id, c.name, m.name, m.format(minV), m.format(avgV), m.format(maxV), m.format(p95))
+		}
+	}
+	return tw.Flush()
+}
+
+// summarize returns the minimum, average, maximum, and 95th percentile
+// (using the nearest-rank method) of a non-empty list of values.
+func summarize(values []float64) (minV, avgV, maxV, p95 float64) {
+	sorted := slices.Sorted(slices.Values(values))
+	var sum float64
+	for _, v := range sorted {
+		sum += v
+	}
+	rank := int(math.Ceil(0.95*float64(len(sorted)))) - 1
+	return sorted[0], sum / float64(len(sorted)), sorted[len(sorted)-1], sorted[max(rank, 0)]
+}
+
+// sampleStats samples the stats of the containers at the configured interval,
+// recording them to a file and/or collecting them for a summary, until the
+// duration expires or the context is canceled (the command is interrupted).
+func sampleStats(ctx context.Context, out io.Writer, options *StatsOptions, cStats *stats, closeChan <-chan error, showAll bool) (retErr error) {
+	var recorder *statsRecorder
+	if options.Record != "" {
+		f, err := os.Create(options.Record)
+		if err != nil {
+			return fmt.Errorf("failed to create record file: %w", err)
+		}
+		defer func() {
+			if err := f.Close(); err != nil && retErr == nil {
+				retErr = err
+			}
+		}()
+		recorder, err = newStatsRecorder(f, options.RecordFormat)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := recorder.flush(); err != nil && retErr == nil {
+				retErr = err
+			}
+		}()
+	}
+
+	var summary statsSummary
+	defer func() {
+		if options.Summary && retErr == nil {
+			retErr = summary.write(out, !options.NoTrunc)
+		}
+	}()
+
+	interval := options.Interval
+	if interval <= 0 {
+		interval = defaultStatsInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var done <-chan time.Time
+	if options.Duration > 0 {
+		timer := time.NewTimer(options.Duration)
+		defer timer.Stop()
+		done = timer.C
+	}
+
+	for {
+		select {
+		case now := <-ticker.C:
+			statsList := cStats.snapshot()
+			if len(statsList) == 0 && !showAll {
+				return nil
+			}
+			for _, c := range statsList {
+				if c.GetError() != nil {
+					continue
+				}
+				entry := c.GetStatistics()
+				if entry.IsInvalid {
+					continue
+				}
+				sample := newStatsSample(now, entry)
+				if recorder != nil {
+					if err := recorder.write(sample); err != nil {
+						return err
+					}
+				}
+				summary.add(sample)
+			}
+		case <-done:
+			return nil
+		case err, ok := <-closeChan:
+			if !ok || err == nil || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return nil
+			}
+			return err
+		case <-ctx.Done():
+			// Interrupting ends the sampling window early, also if a duration
+			// is set; the samples taken so far are recorded and summarized.
+			if errors.Is(ctx.Err(), context.Canceled) {
+				return nil
+			}
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package container
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
+)
+
+func TestStatsRecorder(t *testing.T) {
+	ts := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	entry := StatsEntry{
+		Name:             "/web",
+		ID:               "abcdef",
+		CPUPercentage:    12.5,
+		Memory:           1024,
+		MemoryLimit:      4096,
+		MemoryPercentage: 25,
+		NetworkRx:        10,
+		NetworkTx:        20,
+		BlockRead:        30,
+		BlockWrite:       40,
+		PidsCurrent:      3,
+	}
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		r, err := newStatsRecorder(&buf, recordFormatJSON)
+		assert.NilError(t, err)
+		assert.NilError(t, r.write(newStatsSample(ts, entry)))
+		assert.NilError(t, r.flush())
+		assert.Check(t, is.Equal(buf.String(), `{"time":"2026-01-02T03:04:05Z","id":"abcdef","name":"web","cpu_percent":12.5,"memory_usage":1024,"memory_limit":4096,"memory_percent":25,"network_rx":10,"network_tx":20,"block_read":30,"block_write":40,"pids":3}`+"\n"))
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		r, err := newStatsRecorder(&buf, recordFormatCSV)
+		assert.NilError(t, err)
+		assert.NilError(t, r.write(newStatsSample(ts, entry)))
+		assert.NilError(t, r.flush())
+		expected := "time,id,name,cpu_percent,memory_usage,memory_limit,memory_percent,network_rx,network_tx,block_read,block_write,pids\n" +
+			"2026-01-02T03:04:05Z,abcdef,web,12.5,1024,4096,25,10,20,30,40,3\n"
+		assert.Check(t, is.Equal(buf.String(), expected))
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		_, err := newStatsRecorder(&bytes.Buffer{}, "yaml")
+		assert.Check(t, is.Error(err, `invalid record format "yaml": only json and csv are supported`))
+	})
+}
+
+func TestSummarize(t *testing.T) {
+	values := make([]float64, 0, 20)
+	for i := 20; i > 0; i-- {
+		values = append(values, float64(i))
+	}
+	minV, avgV, maxV, p95 := summarize(values)
+	assert.Check(t, is.Equal(minV, 1.0))
+	assert.Check(t, is.Equal(avgV, 10.5))
+	assert.Check(t, is.Equal(maxV, 20.0))
+	assert.Check(t, is.Equal(p95, 19.0))
+
+	minV, avgV, maxV, p95 = summarize([]float64{5})
+	assert.Check(t, is.Equal(minV, 5.0))
+	assert.Check(t, is.Equal(avgV, 5.0))
+	assert.Check(t, is.Equal(maxV, 5.0))
+	assert.Check(t, is.Equal(p95, 5.0))
+}
+
+func TestStatsSummary(t *testing.T) {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	var s statsSummary
+	for i, cpu := range []float64{10, 30, 20} {
+		s.add(newStatsSample(start.Add(time.Duration(i)*time.Second), StatsEntry{
+			Name:          "/web",
+			ID:            "0123456789abcdef",
+			CPUPercentage: cpu,
+			Memory:        float64(1024 * (i + 1)),
+			NetworkRx:     float64(1000 * i),
+			NetworkTx:     float64(2000 * i),
+		}))
+	}
+	s.add(newStatsSample(start, StatsEntry{Name: "/db", ID: "fedcba9876543210", CPUPercentage: 1}))
+
+	var buf bytes.Buffer
+	assert.NilError(t, s.write(&buf, true))
+	expected := `CONTAINER ID   NAME      METRIC        MIN       AVG       MAX       P95
+0123456789ab   web       CPU %         10.00%    20.00%    30.00%    30.00%
+0123456789ab   web       MEM USAGE     1KiB      2KiB      3KiB      3KiB
+0123456789ab   web       NET RX        1kB/s     1kB/s     1kB/s     1kB/s
+0123456789ab   web       NET TX        2kB/s     2kB/s     2kB/s     2kB/s
+0123456789ab   web       BLOCK READ    0B/s      0B/s      0B/s      0B/s
+0123456789ab   web       BLOCK WRITE   0B/s      0B/s      0B/s      0B/s
+fedcba987654   db        CPU %         1.00%     1.00%     1.00%     1.00%
+fedcba987654   db        MEM USAGE     0B        0B        0B        0B
+fedcba987654   db        NET RX        --        --        --        --
+fedcba987654   db        NET TX        --        --        --        --
+fedcba987654   db        BLOCK READ    --        --        --        --
+fedcba987654   db        BLOCK WRITE   --        --        --        --
+`
+	assert.Check(t, is.Equal(buf.String(), expected))
+}
+
+func TestSampleStatsInterrupted(t *testing.T) {
+	s := NewStats("abcdef0123456789")
+	s.SetStatistics(StatsEntry{
+		Container:     "abcdef0123456789",
+		ID:            "abcdef0123456789",
+		Name:          "/web",
+		CPUPercentage: 10,
+		Memory:        1024,
+	})
+	cStats := stats{cs: []*Stats{s}}
+
+	dir := fs.NewDir(t, t.Name())
+	options := &StatsOptions{
+		Record:       dir.Join("stats.csv"),
+		RecordFormat: recordFormatCSV,
+		Summary:      true,
+		Duration:     time.Hour,
+		Interval:     time.Millisecond,
+	}
+
+	// Interrupt the command before the duration expires.
+	ctx, interrupt := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		interrupt()
+	}()
+
+	var out bytes.Buffer
+	assert.NilError(t, sampleStats(ctx, &out, options, &cStats, nil, false))
+	assert.Check(t, is.Contains(out.String(), "abcdef012345   web       CPU %"))
+
+	recorded, err := os.ReadFile(options.Record)
+	assert.NilError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(recorded)), "\n")
+	assert.Assert(t, len(lines) > 1, "expected recorded samples, got: %s", recorded)
+	assert.Check(t, is.Equal(lines[0], strings.Join(statsSampleCSVHeader, ",")))
+	assert.Check(t, strings.Contains(lines[1], ",abcdef0123456789,web,10,1024,"), lines[1])
+}
+
+func TestRunStatsSamplingOptions(t *testing.T) {
+	tests := []struct {
+		doc         string
+		options     StatsOptions
+		expectedErr string
+	}{
+		{
+			doc:         "record with no-stream",
+			options:     StatsOptions{Record: "stats.json", NoStream: true},
+			expectedErr: "--record and --summary cannot be used with --no-stream",
+		},
+		{
+			doc:         "summary with no-stream",
+			options:     StatsOptions{Summary: true, NoStream: true},
+			expectedErr: "--record and --summary cannot be used with --no-stream",
+		},
+		{
+			doc:         "duration without record",
+			options:     StatsOptions{Duration: time.Minute},
+			expectedErr: "--duration can only be used with --record or --summary",
+		},
+		{
+			doc:         "invalid record format",
+			options:     StatsOptions{Record: "stats.yaml", RecordFormat: "yaml"},
+			expectedErr: `invalid record format "yaml": only json and csv are supported`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			cli := test.NewFakeCli(&fakeClient{})
+			err := RunStats(context.Background(), cli, &tc.options)
+			assert.Check(t, is.Error(err, tc.expectedErr))
+		})
+	}
+}
@@ -6,11 +6,22 @@
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"maps"
 
 	"github.com/docker/cli/cli/context/store"
 )
 
+// CLIConfigField is the name of the field in the context metadata that holds
+// the settings of the CLI configuration file (config.json) to override when
+// the context is used.
+//
+//	{
+//	  "Name": "my-context",
+//	  "Metadata": { "CLIConfig": { "psFormat": "table {{.Names}}\t{{.Status}}" } }
+//	}
+const CLIConfigField = "CLIConfig"
+
 // DockerContext is a typed representation of what we put in Context metadata
 type DockerContext struct {
 	Description      string
@@ -49,6 +60,25 @@ func (dc *DockerContext) UnmarshalJSON(payload []byte) error {
 	return nil
 }
 
+// CLIConfig returns the settings of the CLI configuration file that are
+// overridden by the context, or nil if the context does not override any
+// settings.
+func (dc DockerContext) CLIConfig() (map[string]json.RawMessage, error) {
+	v, ok := dc.AdditionalFields[CLIConfigField]
+	if !ok || v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var cfg map[string]json.RawMessage
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid %s in context metadata: %w", CLIConfigField, err)
+	}
+	return cfg, nil
+}
+
 // GetDockerContext extracts metadata from stored context metadata
 func GetDockerContext(storeMetadata store.Metadata) (DockerContext, error) {
 	if storeMetadata.Metadata == nil {
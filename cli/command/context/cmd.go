@@ -31,6 +31,7 @@ func newContextCommand(dockerCLI command.Cli) *cobra.Command {
 		newUpdateCommand(dockerCLI),
 		newInspectCommand(dockerCLI),
 		newShowCommand(dockerCLI),
+		newPingCommand(dockerCLI),
 	)
 	return cmd
 }
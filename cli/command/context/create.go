@@ -5,6 +5,7 @@
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -12,6 +13,7 @@
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/formatter/tabwriter"
+	"github.com/docker/cli/cli/config/configfile"
 	"github.com/docker/cli/cli/context/docker"
 	"github.com/docker/cli/cli/context/store"
 	"github.com/spf13/cobra"
@@ -22,6 +24,7 @@ type createOptions struct {
 	description string
 	endpoint    map[string]string
 	from        string
+	cliConfig   []string
 
 	// Additional Metadata to store in the context. This option is not
 	// currently exposed to the user.
@@ -58,6 +61,7 @@ func newCreateCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.StringVar(&opts.description, "description", "", "Description of the context")
 	flags.StringToStringVar(&opts.endpoint, "docker", nil, "set the docker endpoint")
 	flags.StringVar(&opts.from, "from", "", "create context from a named context")
+	flags.StringArrayVar(&opts.cliConfig, "cli-config", nil, "Override a setting of the CLI configuration file when using the context (KEY=VALUE)")
 	return cmd
 }
 
@@ -68,13 +72,17 @@ func runCreate(dockerCLI command.Cli, name string, opts createOptions) error {
 	if err != nil {
 		return err
 	}
+	cliConfig, err := parseCLIConfig(opts.cliConfig)
+	if err != nil {
+		return err
+	}
 	switch {
 	case opts.from == "" && opts.endpoint == nil:
-		err = createFromExistingContext(s, name, dockerCLI.CurrentContext(), opts)
+		err = createFromExistingContext(s, name, dockerCLI.CurrentContext(), opts, cliConfig)
 	case opts.from != "":
-		err = createFromExistingContext(s, name, opts.from, opts)
+		err = createFromExistingContext(s, name, opts.from, opts, cliConfig)
 	default:
-		err = createNewContext(s, name, opts)
+		err = createNewContext(s, dockerCLI.ConfigFile(), name, opts, cliConfig)
 	}
 	if err == nil {
 		_, _ = fmt.Fprintln(dockerCLI.Out(), name)
@@ -83,23 +91,27 @@ func runCreate(dockerCLI command.Cli, name string, opts createOptions) error {
 	return err
 }
 
-func createNewContext(contextStore store.ReaderWriter, name string, opts createOptions) error {
+func createNewContext(contextStore store.ReaderWriter, configFile *configfile.ConfigFile, name string, opts createOptions, cliConfig map[string]json.RawMessage) error {
 	if opts.endpoint == nil {
 		return errors.New("docker endpoint configuration is required")
 	}
-	dockerEP, dockerTLS, err := getDockerEndpointMetadataAndTLS(contextStore, opts.endpoint)
+	dockerEP, dockerTLS, err := getDockerEndpointMetadataAndTLS(contextStore, opts.endpoint, configFile)
 	if err != nil {
 		return fmt.Errorf("unable to create docker endpoint config: %w", err)
 	}
+	dockerContext := command.DockerContext{
+		Description:      opts.description,
+		AdditionalFields: opts.metaData,
+	}
+	if err := setCLIConfig(&dockerContext, cliConfig); err != nil {
+		return err
+	}
 	contextMetadata := store.Metadata{
 		Endpoints: map[string]any{
 			docker.DockerEndpoint: dockerEP,
 		},
-		Metadata: command.DockerContext{
-			Description:      opts.description,
-			AdditionalFields: opts.metaData,
-		},
-		Name: name,
+		Metadata: dockerContext,
+		Name:     name,
 	}
 	contextTLSData := store.ContextTLSData{}
 	if dockerTLS != nil {
@@ -129,13 +141,14 @@ func checkContextNameForCreation(s store.Reader, name string) error {
 	return nil
 }
 
-func createFromExistingContext(s store.ReaderWriter, name string, fromContextName string, opts createOptions) error {
+func createFromExistingContext(s store.ReaderWriter, name string, fromContextName string, opts createOptions, cliConfig map[string]json.RawMessage) error {
 	if len(opts.endpoint) != 0 {
 		return errors.New("cannot use --docker flag when --from is set")
 	}
 	reader := store.Export(fromContextName, &descriptionDecorator{
 		Reader:      s,
 		description: opts.description,
+		cliConfig:   cliConfig,
 	})
 	defer reader.Close()
 	return store.Import(name, s, reader)
@@ -144,6 +157,7 @@ func createFromExistingContext(s store.ReaderWriter, name string, fromContextNam
 type descriptionDecorator struct {
 	store.Reader
 	description string
+	cliConfig   map[string]json.RawMessage
 }
 
 func (d *descriptionDecorator) GetMetadata(name string) (store.Metadata, error) {
@@ -158,6 +172,9 @@ func (d *descriptionDecorator) GetMetadata(name string) (store.Metadata, error)
 	if d.description != "" {
 		typedContext.Description = d.description
 	}
+	if err := setCLIConfig(&typedContext, d.cliConfig); err != nil {
+		return c, err
+	}
 	c.Metadata = typedContext
 	return c, nil
 }
@@ -145,6 +145,34 @@ func TestCreate(t *testing.T) {
 	}
 }
 
+func TestCreateConnectionHelper(t *testing.T) {
+	options := createOptions{
+		endpoint: map[string]string{
+			"host": "myscheme://example.com",
+		},
+	}
+
+	cli := makeFakeCli(t, withCliConfig(&configfile.ConfigFile{
+		ConnectionHelpers: map[string]configfile.ConnectionHelper{
+			"myscheme": {},
+		},
+	}))
+	err := runCreate(cli, "invalid-helper", options)
+	assert.ErrorContains(t, err, "invalid connection helper for myscheme://: no command specified")
+
+	cli = makeFakeCli(t, withCliConfig(&configfile.ConfigFile{
+		ConnectionHelpers: map[string]configfile.ConnectionHelper{
+			"myscheme": {Command: "my-helper"},
+		},
+	}))
+	assert.NilError(t, runCreate(cli, "with-helper", options))
+	assertContextCreateLogging(t, cli, "with-helper")
+
+	ctx, err := cli.ContextStore().GetMetadata("with-helper")
+	assert.NilError(t, err)
+	assert.Equal(t, ctx.Endpoints[docker.DockerEndpoint].(docker.EndpointMeta).Host, "myscheme://example.com")
+}
+
 func assertContextCreateLogging(t *testing.T, cli *test.FakeCli, n string) {
 	t.Helper()
 	assert.Equal(t, n+"\n", cli.OutBuffer().String())
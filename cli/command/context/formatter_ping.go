@@ -0,0 +1,112 @@
+package context
+
+import (
+	"time"
+
+	"github.com/docker/cli/cli/command/formatter"
+)
+
+const (
+	defaultPingTableFormat = "table {{.Name}}\t{{.Status}}\t{{.Latency}}\t{{.APIVersion}}\t{{.EngineVersion}}\t{{.TLSExpiry}}\t{{.Error}}"
+
+	dockerEndpointHeader = "DOCKER ENDPOINT"
+	statusHeader         = "STATUS"
+	latencyHeader        = "LATENCY"
+	apiVersionHeader     = "API VERSION"
+	engineVersionHeader  = "ENGINE VERSION"
+	tlsExpiryHeader      = "TLS EXPIRY"
+
+	maxErrLength = 45
+
+	statusReachable   = "reachable"
+	statusUnreachable = "unreachable"
+)
+
+// newPingFormat returns a Format for rendering using a pingContext.
+func newPingFormat(source string) formatter.Format {
+	switch source {
+	case "", formatter.TableFormatKey:
+		return defaultPingTableFormat
+	}
+	return formatter.Format(source)
+}
+
+// pingFormatWrite writes the results of checking the contexts using the context.
+func pingFormatWrite(fmtCtx formatter.Context, statuses []contextStatus) error {
+	pingCtx := &pingContext{
+		HeaderContext: formatter.HeaderContext{
+			Header: formatter.SubHeaderContext{
+				"Name":           formatter.NameHeader,
+				"DockerEndpoint": dockerEndpointHeader,
+				"Status":         statusHeader,
+				"Latency":        latencyHeader,
+				"APIVersion":     apiVersionHeader,
+				"EngineVersion":  engineVersionHeader,
+				"TLSExpiry":      tlsExpiryHeader,
+				"Error":          formatter.ErrorHeader,
+			},
+		},
+	}
+	return fmtCtx.Write(pingCtx, func(format func(subContext formatter.SubContext) error) error {
+		for _, s := range statuses {
+			if err := format(&pingContext{s: s}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+type pingContext struct {
+	formatter.HeaderContext
+	s contextStatus
+}
+
+func (c *pingContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *pingContext) Name() string {
+	return c.s.Name
+}
+
+func (c *pingContext) DockerEndpoint() string {
+	return c.s.Endpoint
+}
+
+func (c *pingContext) Status() string {
+	if c.s.Reachable {
+		return statusReachable
+	}
+	return statusUnreachable
+}
+
+func (c *pingContext) Latency() string {
+	if !c.s.Reachable {
+		return ""
+	}
+	return c.s.Latency.Round(time.Microsecond).String()
+}
+
+func (c *pingContext) APIVersion() string {
+	return c.s.APIVersion
+}
+
+func (c *pingContext) EngineVersion() string {
+	return c.s.EngineVersion
+}
+
+// TLSExpiry returns the expiry date of the client certificate, in RFC 3339
+// format, or an empty string if the context has no client certificate.
+func (c *pingContext) TLSExpiry() string {
+	if c.s.TLSExpiry.IsZero() {
+		return ""
+	}
+	return c.s.TLSExpiry.UTC().Format(time.RFC3339)
+}
+
+// Error returns the truncated error (if any) that occurred when connecting
+// to the context.
+func (c *pingContext) Error() string {
+	return formatter.Ellipsis(c.s.Error, maxErrLength)
+}
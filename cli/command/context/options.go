@@ -1,10 +1,17 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
 package context
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/config/configfile"
 	"github.com/docker/cli/cli/context"
 	"github.com/docker/cli/cli/context/docker"
 	"github.com/docker/cli/cli/context/store"
@@ -88,7 +95,10 @@ func validateConfig(config map[string]string, allowedKeys map[string]struct{}) e
 	return errors.Join(errs...)
 }
 
-func getDockerEndpoint(contextStore store.Reader, config map[string]string) (docker.Endpoint, error) {
+// getDockerEndpoint returns the docker endpoint for the given configuration.
+// The connection helpers configured in configFile are used to validate hosts
+// with a custom URL scheme.
+func getDockerEndpoint(contextStore store.Reader, config map[string]string, configFile *configfile.ConfigFile) (docker.Endpoint, error) {
 	if err := validateConfig(config, allowedDockerConfigKeys); err != nil {
 		return docker.Endpoint{}, err
 	}
@@ -118,7 +128,7 @@ func getDockerEndpoint(contextStore store.Reader, config map[string]string) (doc
 		TLSData: tlsData,
 	}
 	// try to resolve a docker client, validating the configuration
-	opts, err := ep.ClientOpts()
+	opts, err := ep.ClientOptsWithConfig(configFile)
 	if err != nil {
 		return docker.Endpoint{}, fmt.Errorf("invalid docker endpoint options: %w", err)
 	}
@@ -129,10 +139,73 @@ func getDockerEndpoint(contextStore store.Reader, config map[string]string) (doc
 	return ep, nil
 }
 
-func getDockerEndpointMetadataAndTLS(contextStore store.Reader, config map[string]string) (docker.EndpointMeta, *store.EndpointTLSData, error) {
-	ep, err := getDockerEndpoint(contextStore, config)
+func getDockerEndpointMetadataAndTLS(contextStore store.Reader, config map[string]string, configFile *configfile.ConfigFile) (docker.EndpointMeta, *store.EndpointTLSData, error) {
+	ep, err := getDockerEndpoint(contextStore, config, configFile)
 	if err != nil {
 		return docker.EndpointMeta{}, nil, err
 	}
 	return ep.EndpointMeta, ep.TLSData.ToStoreTLSData(), nil
 }
+
+// parseCLIConfig parses the KEY=VALUE settings of the CLI configuration file
+// to override when using a context. Values that are a JSON object, array, or
+// string are used as-is, and other values are used as a string. A nil value
+// is returned for settings with an empty value.
+func parseCLIConfig(settings []string) (map[string]json.RawMessage, error) {
+	if len(settings) == 0 {
+		return nil, nil
+	}
+	cliConfig := make(map[string]json.RawMessage, len(settings))
+	overrides := make(map[string]json.RawMessage, len(settings))
+	for _, setting := range settings {
+		k, v, ok := strings.Cut(setting, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid CLI configuration setting %q: must be in KEY=VALUE format", setting)
+		}
+		switch {
+		case v == "":
+			cliConfig[k] = nil
+			continue
+		case strings.ContainsAny(v[:1], `{["`) && json.Valid([]byte(v)):
+			cliConfig[k] = json.RawMessage(v)
+		default:
+			cliConfig[k], _ = json.Marshal(v)
+		}
+		overrides[k] = cliConfig[k]
+	}
+	if err := configfile.New("").SetOverrides(overrides); err != nil {
+		return nil, err
+	}
+	return cliConfig, nil
+}
+
+// setCLIConfig updates the settings of the CLI configuration file that are
+// overridden by the context. Settings with a nil value are removed.
+func setCLIConfig(dockerContext *command.DockerContext, settings map[string]json.RawMessage) error {
+	if len(settings) == 0 {
+		return nil
+	}
+	cliConfig, err := dockerContext.CLIConfig()
+	if err != nil {
+		return err
+	}
+	if cliConfig == nil {
+		cliConfig = make(map[string]json.RawMessage, len(settings))
+	}
+	for k, v := range settings {
+		if v == nil {
+			delete(cliConfig, k)
+		} else {
+			cliConfig[k] = v
+		}
+	}
+	if len(cliConfig) == 0 {
+		delete(dockerContext.AdditionalFields, command.CLIConfigField)
+		return nil
+	}
+	if dockerContext.AdditionalFields == nil {
+		dockerContext.AdditionalFields = make(map[string]any)
+	}
+	dockerContext.AdditionalFields[command.CLIConfigField] = cliConfig
+	return nil
+}
@@ -0,0 +1,169 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package context
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/context/docker"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/fvbommel/sortorder"
+	"github.com/moby/moby/client"
+	"github.com/spf13/cobra"
+)
+
+type pingOptions struct {
+	format  string
+	timeout time.Duration
+}
+
+func newPingCommand(dockerCLI command.Cli) *cobra.Command {
+	opts := &pingOptions{}
+	cmd := &cobra.Command{
+		Use:   "ping [OPTIONS] [CONTEXT...]",
+		Short: "Check the connection to the Docker endpoint of contexts",
+		Long:  "Check the connection to the Docker endpoint of contexts.\nIf no context is specified, all contexts are checked.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPing(cmd.Context(), dockerCLI, opts, args)
+		},
+		ValidArgsFunction:     completeContextNames(dockerCLI, -1, false),
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.format, "format", "", flagsHelper.FormatHelp)
+	flags.DurationVar(&opts.timeout, "timeout", 10*time.Second, "Maximum time to wait for each context to respond")
+	return cmd
+}
+
+// contextStatus is the result of checking the connection to the Docker
+// endpoint of a context.
+type contextStatus struct {
+	Name          string
+	Endpoint      string
+	Reachable     bool
+	APIVersion    string
+	EngineVersion string
+	// Latency is the round-trip time of a ping on an established connection.
+	Latency time.Duration
+	// TLSExpiry is the earliest expiry date of the client certificate and
+	// CA certificates of the context.
+	TLSExpiry time.Time
+	Error     string
+}
+
+func runPing(ctx context.Context, dockerCLI command.Cli, opts *pingOptions, names []string) error {
+	if len(names) == 0 {
+		contexts, err := dockerCLI.ContextStore().List()
+		if err != nil {
+			return err
+		}
+		for _, c := range contexts {
+			names = append(names, c.Name)
+		}
+	}
+
+	statuses := checkContexts(ctx, dockerCLI, names, opts.timeout)
+	sort.Slice(statuses, func(i, j int) bool {
+		return sortorder.NaturalLess(statuses[i].Name, statuses[j].Name)
+	})
+
+	pingCtx := formatter.Context{
+		Output: dockerCLI.Out(),
+		Format: newPingFormat(opts.format),
+	}
+	return pingFormatWrite(pingCtx, statuses)
+}
+
+// checkContexts checks the connection to the contexts in parallel. Each
+// context must respond within the timeout.
+func checkContexts(ctx context.Context, dockerCLI command.Cli, names []string, timeout time.Duration) []contextStatus {
+	statuses := make([]contextStatus, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			statuses[i] = checkContext(ctx, dockerCLI, name)
+		}()
+	}
+	wg.Wait()
+	return statuses
+}
+
+// checkContext connects to the Docker endpoint of the context, and returns
+// its status.
+func checkContext(ctx context.Context, dockerCLI command.Cli, name string) contextStatus {
+	status := contextStatus{Name: name}
+	apiClient, ep, err := command.NewAPIClientForContext(dockerCLI.ContextStore(), name, dockerCLI.ConfigFile())
+	status.Endpoint = ep.Host
+	status.TLSExpiry = certificateExpiry(ep)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	defer apiClient.Close()
+
+	// The first ping sets up the connection, which may include starting an
+	// SSH connection, and is not included in the latency.
+	if _, err := apiClient.Ping(ctx, client.PingOptions{}); err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	start := time.Now()
+	if _, err := apiClient.Ping(ctx, client.PingOptions{}); err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	status.Latency = time.Since(start)
+	status.Reachable = true
+
+	v, err := apiClient.ServerVersion(ctx, client.ServerVersionOptions{})
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	status.APIVersion = v.APIVersion
+	status.EngineVersion = v.Version
+	return status
+}
+
+// certificateExpiry returns the earliest expiry date of the client certificate
+// and CA certificates of the endpoint, or a zero time if the endpoint has no
+// certificates.
+func certificateExpiry(ep docker.Endpoint) time.Time {
+	if ep.TLSData == nil {
+		return time.Time{}
+	}
+	var expiry time.Time
+	for _, data := range [][]byte{ep.TLSData.Cert, ep.TLSData.CA} {
+		for {
+			var block *pem.Block
+			block, data = pem.Decode(data)
+			if block == nil {
+				break
+			}
+			if block.Type != "CERTIFICATE" {
+				continue
+			}
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				continue
+			}
+			if expiry.IsZero() || cert.NotAfter.Before(expiry) {
+				expiry = cert.NotAfter
+			}
+		}
+	}
+	return expiry
+}
@@ -0,0 +1,159 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package context
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/command/formatter"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/golden"
+)
+
+// newTestDaemon returns the address of a daemon that responds to ping and
+// version requests.
+func newTestDaemon(t *testing.T) string {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Api-Version", "1.52")
+		switch {
+		case r.URL.Path == "/_ping":
+			_, _ = w.Write([]byte("OK"))
+		case strings.HasSuffix(r.URL.Path, "/version"):
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"Version":"29.0.0","ApiVersion":"1.52"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return "tcp://" + srv.Listener.Addr().String()
+}
+
+// writeTestCertificate writes a client certificate and key that expire at
+// the given time, and returns their paths.
+func writeTestCertificate(t *testing.T, notAfter time.Time) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	cert, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    notAfter.Add(-24 * time.Hour),
+		NotAfter:     notAfter,
+	}, &x509.Certificate{SerialNumber: big.NewInt(1)}, &key.PublicKey, key)
+	assert.NilError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NilError(t, err)
+
+	dir := t.TempDir()
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	assert.NilError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}), 0o600))
+	assert.NilError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
+
+func TestCheckContexts(t *testing.T) {
+	cli := makeFakeCli(t)
+	assert.NilError(t, runCreate(cli, "reachable", createOptions{
+		endpoint: map[string]string{keyHost: newTestDaemon(t)},
+	}))
+
+	expiry := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	certFile, keyFile := writeTestCertificate(t, expiry)
+	assert.NilError(t, runCreate(cli, "unreachable", createOptions{
+		endpoint: map[string]string{
+			keyHost:          "tcp://127.0.0.1:1",
+			keyCert:          certFile,
+			keyKey:           keyFile,
+			keySkipTLSVerify: "true",
+		},
+	}))
+
+	caExpiry := time.Date(2029, 1, 2, 3, 4, 5, 0, time.UTC)
+	caFile, _ := writeTestCertificate(t, caExpiry)
+	assert.NilError(t, runCreate(cli, "ca", createOptions{
+		endpoint: map[string]string{
+			keyHost: "tcp://127.0.0.1:1",
+			keyCA:   caFile,
+			keyCert: certFile,
+			keyKey:  keyFile,
+		},
+	}))
+
+	statuses := checkContexts(context.Background(), cli, []string{"reachable", "unreachable", "missing", "ca"}, 10*time.Second)
+	assert.Assert(t, is.Len(statuses, 4))
+
+	reachable := statuses[0]
+	assert.Check(t, reachable.Reachable)
+	assert.Check(t, is.Equal(reachable.Error, ""))
+	assert.Check(t, is.Equal(reachable.APIVersion, "1.52"))
+	assert.Check(t, is.Equal(reachable.EngineVersion, "29.0.0"))
+	assert.Check(t, reachable.Latency > 0)
+	assert.Check(t, reachable.TLSExpiry.IsZero())
+
+	unreachable := statuses[1]
+	assert.Check(t, !unreachable.Reachable)
+	assert.Check(t, is.Equal(unreachable.Endpoint, "tcp://127.0.0.1:1"))
+	assert.Check(t, unreachable.Error != "")
+	assert.Check(t, is.Equal(unreachable.TLSExpiry, expiry))
+
+	missing := statuses[2]
+	assert.Check(t, !missing.Reachable)
+	assert.Check(t, is.Contains(missing.Error, `context "missing": context not found`))
+
+	// The CA certificate expires before the client certificate.
+	ca := statuses[3]
+	assert.Check(t, is.Equal(ca.TLSExpiry, caExpiry))
+}
+
+func TestPingFormat(t *testing.T) {
+	statuses := []contextStatus{
+		{
+			Name:          "reachable",
+			Endpoint:      "ssh://user@example.com",
+			Reachable:     true,
+			APIVersion:    "1.52",
+			EngineVersion: "29.0.0",
+			Latency:       12345678 * time.Nanosecond,
+			TLSExpiry:     time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		{
+			Name:     "unreachable",
+			Endpoint: "tcp://127.0.0.1:1",
+			Error:    "connection refused",
+		},
+	}
+
+	testCases := []struct {
+		name   string
+		format string
+	}{
+		{name: "table", format: "table"},
+		{name: "format", format: "{{.Name}} {{.DockerEndpoint}} {{.Status}}"},
+		{name: "json", format: "json"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out strings.Builder
+			err := pingFormatWrite(formatter.Context{Output: &out, Format: newPingFormat(tc.format)}, statuses)
+			assert.NilError(t, err)
+			golden.Assert(t, out.String(), "ping-"+tc.name+".golden")
+		})
+	}
+}
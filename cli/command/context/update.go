@@ -16,6 +16,7 @@
 type updateOptions struct {
 	description string
 	endpoint    map[string]string
+	cliConfig   []string
 }
 
 func longUpdateDescription() string {
@@ -47,6 +48,7 @@ func newUpdateCommand(dockerCLI command.Cli) *cobra.Command {
 	flags := cmd.Flags()
 	flags.StringVar(&opts.description, "description", "", "Description of the context")
 	flags.StringToStringVar(&opts.endpoint, "docker", nil, "set the docker endpoint")
+	flags.StringArrayVar(&opts.cliConfig, "cli-config", nil, "Override a setting of the CLI configuration file when using the context (KEY=VALUE)")
 	return cmd
 }
 
@@ -55,6 +57,10 @@ func runUpdate(dockerCLI command.Cli, name string, opts updateOptions) error {
 	if err := store.ValidateContextName(name); err != nil {
 		return err
 	}
+	cliConfig, err := parseCLIConfig(opts.cliConfig)
+	if err != nil {
+		return err
+	}
 	s := dockerCLI.ContextStore()
 	c, err := s.GetMetadata(name)
 	if err != nil {
@@ -67,13 +73,16 @@ func runUpdate(dockerCLI command.Cli, name string, opts updateOptions) error {
 	if opts.description != "" {
 		dockerContext.Description = opts.description
 	}
+	if err := setCLIConfig(&dockerContext, cliConfig); err != nil {
+		return err
+	}
 
 	c.Metadata = dockerContext
 
 	tlsDataToReset := make(map[string]*store.EndpointTLSData)
 
 	if opts.endpoint != nil {
-		dockerEP, dockerTLS, err := getDockerEndpointMetadataAndTLS(s, opts.endpoint)
+		dockerEP, dockerTLS, err := getDockerEndpointMetadataAndTLS(s, opts.endpoint, dockerCLI.ConfigFile())
 		if err != nil {
 			return fmt.Errorf("unable to create docker endpoint config: %w", err)
 		}
@@ -1,6 +1,7 @@
 package context
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/docker/cli/cli/command"
@@ -60,3 +61,33 @@ func TestUpdateInvalidDockerHost(t *testing.T) {
 	})
 	assert.ErrorContains(t, err, "unable to parse docker host")
 }
+
+func TestUpdateCLIConfig(t *testing.T) {
+	cli := makeFakeCli(t)
+	err := runCreate(cli, "test", createOptions{
+		endpoint:  map[string]string{},
+		cliConfig: []string{"psFormat=table {{.Names}}", "detachKeys=ctrl-x"},
+	})
+	assert.NilError(t, err)
+	assert.NilError(t, runUpdate(cli, "test", updateOptions{
+		cliConfig: []string{
+			"detachKeys=",
+			`proxies={"default":{"httpProxy":"http://proxy.example.com"}}`,
+		},
+	}))
+	c, err := cli.ContextStore().GetMetadata("test")
+	assert.NilError(t, err)
+	dc, err := command.GetDockerContext(c)
+	assert.NilError(t, err)
+	cliConfig, err := dc.CLIConfig()
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(cliConfig, map[string]json.RawMessage{
+		"psFormat": json.RawMessage(`"table {{.Names}}"`),
+		"proxies":  json.RawMessage(`{"default":{"httpProxy":"http://proxy.example.com"}}`),
+	}))
+
+	err = runUpdate(cli, "test", updateOptions{cliConfig: []string{"credsStore=secretservice"}})
+	assert.Check(t, is.Error(err, `setting "credsStore" cannot be overridden`))
+	err = runUpdate(cli, "test", updateOptions{cliConfig: []string{"psFormat"}})
+	assert.Check(t, is.Error(err, `invalid CLI configuration setting "psFormat": must be in KEY=VALUE format`))
+}
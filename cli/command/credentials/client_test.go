@@ -0,0 +1,83 @@
+package credentials
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/config/credentials"
+	"github.com/docker/cli/cli/config/types"
+	"gotest.tools/v3/assert"
+)
+
+// fakeStore is a credential helper that keeps credentials in memory.
+type fakeStore struct {
+	auths     map[string]types.AuthConfig
+	getAllErr error
+	storeErr  error
+	// corrupt makes the store return other credentials than were stored.
+	corrupt bool
+}
+
+func (s *fakeStore) Erase(serverAddress string) error {
+	delete(s.auths, serverAddress)
+	return nil
+}
+
+func (s *fakeStore) Get(serverAddress string) (types.AuthConfig, error) {
+	return s.auths[serverAddress], nil
+}
+
+func (s *fakeStore) GetAll() (map[string]types.AuthConfig, error) {
+	if s.getAllErr != nil {
+		return nil, s.getAllErr
+	}
+	return s.auths, nil
+}
+
+func (s *fakeStore) Store(authConfig types.AuthConfig) error {
+	if s.storeErr != nil {
+		return s.storeErr
+	}
+	if s.auths == nil {
+		s.auths = make(map[string]types.AuthConfig)
+	}
+	if s.corrupt {
+		authConfig.Password = "corrupted"
+	}
+	s.auths[authConfig.ServerAddress] = authConfig
+	return nil
+}
+
+// withFakeStores replaces the credential helpers with the given stores for
+// the duration of the test.
+func withFakeStores(t *testing.T, stores map[string]*fakeStore) {
+	t.Helper()
+	orig := newNativeStore
+	newNativeStore = func(_ *configfile.ConfigFile, helperSuffix string) credentials.Store {
+		s, ok := stores[helperSuffix]
+		if !ok {
+			t.Fatalf("unexpected credential helper: %s", helperSuffix)
+		}
+		return s
+	}
+	t.Cleanup(func() { newNativeStore = orig })
+}
+
+// newTestConfigFile returns a configuration file that can be saved.
+func newTestConfigFile(t *testing.T) *configfile.ConfigFile {
+	t.Helper()
+	return configfile.New(filepath.Join(t.TempDir(), "config.json"))
+}
+
+// loadConfigFile loads the configuration file that was saved by a command.
+func loadConfigFile(t *testing.T, filename string) *configfile.ConfigFile {
+	t.Helper()
+	f, err := os.Open(filename)
+	assert.NilError(t, err)
+	defer f.Close()
+	cfg := configfile.New(filename)
+	assert.NilError(t, cfg.LoadFromReader(f))
+	return cfg
+}
@@ -0,0 +1,109 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package credentials
+
+import (
+	"os/exec"
+	"slices"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/config/credentials"
+	"github.com/docker/cli/internal/commands"
+	"github.com/docker/cli/internal/registry"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	commands.Register(newCredentialsCommand)
+}
+
+// newCredentialsCommand returns a cobra command for `credentials` subcommands
+func newCredentialsCommand(dockerCLI command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "credentials",
+		Short: "Manage credential stores and helpers",
+		Args:  cli.NoArgs,
+		RunE:  command.ShowHelp(dockerCLI.Err()),
+
+		DisableFlagsInUseLine: true,
+	}
+	cmd.AddCommand(
+		newListCommand(dockerCLI),
+		newGetCommand(dockerCLI),
+		newSetCommand(dockerCLI),
+		newRemoveCommand(dockerCLI),
+		newMigrateCommand(dockerCLI),
+	)
+	return cmd
+}
+
+// fileStoreName is the name of the store that keeps credentials unencrypted
+// in the configuration file.
+const fileStoreName = "file"
+
+// credentialHelperPrefix is the prefix of the binaries of credential helpers.
+const credentialHelperPrefix = "docker-credential-" //nolint:gosec // ignore G101: Potential hardcoded credentials
+
+// var for unit testing.
+var newNativeStore = func(configFile *configfile.ConfigFile, helperSuffix string) credentials.Store {
+	return credentials.NewNativeStore(configFile, helperSuffix)
+}
+
+// var for unit testing.
+var lookPath = exec.LookPath
+
+// serverAddress returns the key under which the credentials for a registry
+// are stored. Credentials for Docker Hub are stored under the historical
+// index address, and credentials for other registries under their hostname.
+func serverAddress(name string) string {
+	hostname := credentials.ConvertToHostname(name)
+	if hostname == registry.IndexName || hostname == registry.IndexHostname {
+		return registry.IndexServer
+	}
+	return hostname
+}
+
+// configuredStore returns the name of the store that serves the credentials
+// for the given server address: its credential helper, the default credential
+// store, or the file store if neither are configured.
+func configuredStore(cfg *configfile.ConfigFile, address string) string {
+	if helper, ok := cfg.CredentialHelpers[address]; ok {
+		return helper
+	}
+	if cfg.CredentialsStore != "" {
+		return cfg.CredentialsStore
+	}
+	return fileStoreName
+}
+
+// getStore returns the store with the given name.
+func getStore(cfg *configfile.ConfigFile, name string) credentials.Store {
+	switch name {
+	case fileStoreName:
+		return credentials.NewFileStore(cfg)
+	case credentials.EncryptedFileStoreName:
+		return credentials.NewEncryptedFileStore(cfg)
+	default:
+		return newNativeStore(cfg, name)
+	}
+}
+
+// completeRegistries offers completion for the registries that have stored
+// credentials or a credential helper.
+func completeRegistries(dockerCLI command.Cli) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		cfg := dockerCLI.ConfigFile()
+		var registries []string
+		for address := range cfg.GetAuthConfigs() {
+			registries = append(registries, address)
+		}
+		for address := range cfg.CredentialHelpers {
+			registries = append(registries, address)
+		}
+		slices.Sort(registries)
+		return slices.Compact(registries), cobra.ShellCompDirectiveNoFileComp
+	}
+}
@@ -0,0 +1,72 @@
+package credentials
+
+import (
+	"github.com/docker/cli/cli/command/formatter"
+)
+
+const (
+	defaultCredentialsTableFormat = "table {{.Registry}}\t{{.Store}}\t{{.Username}}"
+
+	registryHeader = "REGISTRY"
+	storeHeader    = "STORE"
+	usernameHeader = "USERNAME"
+)
+
+// storedCredentials are the credentials for a registry in a store. Secrets
+// are never included.
+type storedCredentials struct {
+	registry string
+	store    string
+	username string
+}
+
+// newFormat returns a Format for rendering using a credentialsContext.
+func newFormat(source string) formatter.Format {
+	switch source {
+	case "", formatter.TableFormatKey:
+		return defaultCredentialsTableFormat
+	}
+	return formatter.Format(source)
+}
+
+// formatWrite writes the stored credentials using the context.
+func formatWrite(fmtCtx formatter.Context, creds []storedCredentials) error {
+	credsCtx := &credentialsContext{
+		HeaderContext: formatter.HeaderContext{
+			Header: formatter.SubHeaderContext{
+				"Registry": registryHeader,
+				"Store":    storeHeader,
+				"Username": usernameHeader,
+			},
+		},
+	}
+	return fmtCtx.Write(credsCtx, func(format func(subContext formatter.SubContext) error) error {
+		for _, c := range creds {
+			if err := format(&credentialsContext{c: c}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+type credentialsContext struct {
+	formatter.HeaderContext
+	c storedCredentials
+}
+
+func (c *credentialsContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *credentialsContext) Registry() string {
+	return c.c.registry
+}
+
+func (c *credentialsContext) Store() string {
+	return c.c.store
+}
+
+func (c *credentialsContext) Username() string {
+	return c.c.username
+}
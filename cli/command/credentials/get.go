@@ -0,0 +1,48 @@
+package credentials
+
+import (
+	"fmt"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+)
+
+func newGetCommand(dockerCLI command.Cli) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get REGISTRY",
+		Short: "Show the credential store that serves a registry",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runGet(dockerCLI, args[0])
+		},
+		ValidArgsFunction:     completeRegistries(dockerCLI),
+		DisableFlagsInUseLine: true,
+	}
+}
+
+// runGet prints the store that serves the credentials for the registry, and
+// the username that is stored for it. The secret is never printed.
+func runGet(dockerCLI command.Cli, registry string) error {
+	cfg := dockerCLI.ConfigFile()
+	address := serverAddress(registry)
+	store := configuredStore(cfg, address)
+
+	ac, err := getStore(cfg, store).Get(address)
+	if err != nil {
+		return fmt.Errorf("failed to get credentials for %s from %s store: %w", address, store, err)
+	}
+
+	out := dockerCLI.Out()
+	_, _ = fmt.Fprintln(out, "Registry:", address)
+	_, _ = fmt.Fprintln(out, "Store:   ", store)
+	switch {
+	case ac.Username != "":
+		_, _ = fmt.Fprintln(out, "Username:", ac.Username)
+	case hasCredentials(ac):
+		_, _ = fmt.Fprintln(out, "Username:", "(identity token)")
+	default:
+		_, _ = fmt.Fprintln(out, "Username:", "(not logged in)")
+	}
+	return nil
+}
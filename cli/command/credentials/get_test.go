@@ -0,0 +1,52 @@
+package credentials
+
+import (
+	"testing"
+
+	"github.com/docker/cli/cli/config/types"
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestGet(t *testing.T) {
+	withFakeStores(t, map[string]*fakeStore{
+		"desktop": {auths: map[string]types.AuthConfig{
+			"https://index.docker.io/v1/": {Username: "hubuser", Password: "secret"},
+		}},
+		"gcloud": {auths: map[string]types.AuthConfig{
+			"gcr.io": {IdentityToken: "token"},
+		}},
+	})
+	cfg := newTestConfigFile(t)
+	cfg.CredentialsStore = "desktop"
+	cfg.CredentialHelpers = map[string]string{"gcr.io": "gcloud"}
+
+	testCases := []struct {
+		registry string
+		expected string
+	}{
+		{
+			registry: "docker.io",
+			expected: "Registry: https://index.docker.io/v1/\nStore:    desktop\nUsername: hubuser\n",
+		},
+		{
+			registry: "https://gcr.io",
+			expected: "Registry: gcr.io\nStore:    gcloud\nUsername: (identity token)\n",
+		},
+		{
+			registry: "registry.example.com",
+			expected: "Registry: registry.example.com\nStore:    desktop\nUsername: (not logged in)\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.registry, func(t *testing.T) {
+			cli := test.NewFakeCli(nil)
+			cli.SetConfigFile(cfg)
+			cmd := newGetCommand(cli)
+			cmd.SetArgs([]string{tc.registry})
+			assert.NilError(t, cmd.Execute())
+			assert.Check(t, is.Equal(cli.OutBuffer().String(), tc.expected))
+		})
+	}
+}
@@ -0,0 +1,122 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package credentials
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/config/types"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+type listOptions struct {
+	format string
+}
+
+func newListCommand(dockerCLI command.Cli) *cobra.Command {
+	var options listOptions
+
+	cmd := &cobra.Command{
+		Use:     "ls [OPTIONS]",
+		Aliases: []string{"list"},
+		Short:   "List the registries with stored credentials",
+		Args:    cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runList(dockerCLI, options)
+		},
+		ValidArgsFunction:     cobra.NoFileCompletions,
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&options.format, "format", "", flagsHelper.FormatHelp)
+
+	return cmd
+}
+
+// runList lists the registries with credentials in each of the configured
+// stores. Stores that fail to list their credentials are reported as a
+// warning, so that the credentials in other stores are still listed.
+func runList(dockerCLI command.Cli, options listOptions) error {
+	creds, errs := listCredentials(dockerCLI.ConfigFile())
+	for _, err := range errs {
+		_, _ = fmt.Fprintln(dockerCLI.Err(), "WARNING:", err)
+	}
+
+	return formatWrite(formatter.Context{
+		Output: dockerCLI.Out(),
+		Format: newFormat(options.format),
+	}, creds)
+}
+
+// listCredentials returns the credentials in the configuration file, the
+// default credential store, and the credential helpers, sorted by registry.
+func listCredentials(cfg *configfile.ConfigFile) ([]storedCredentials, []error) {
+	var (
+		creds []storedCredentials
+		errs  []error
+	)
+	seen := make(map[storedCredentials]bool)
+	add := func(store string, auths map[string]types.AuthConfig) {
+		for address, ac := range auths {
+			if !hasCredentials(ac) {
+				continue
+			}
+			c := storedCredentials{registry: address, store: store, username: ac.Username}
+			if !seen[c] {
+				seen[c] = true
+				creds = append(creds, c)
+			}
+		}
+	}
+
+	add(fileStoreName, cfg.GetAuthConfigs())
+
+	stores := slices.Sorted(maps.Values(cfg.CredentialHelpers))
+	if cfg.CredentialsStore != "" {
+		stores = append(stores, cfg.CredentialsStore)
+	}
+	for _, name := range slices.Compact(stores) {
+		auths, err := getStore(cfg, name).GetAll()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to list credentials in %s store: %w", name, err))
+			continue
+		}
+		add(name, auths)
+	}
+
+	// Not all credential helpers support listing their credentials, so
+	// look up the registries that have a credential helper configured.
+	for _, address := range slices.Sorted(maps.Keys(cfg.CredentialHelpers)) {
+		name := cfg.CredentialHelpers[address]
+		ac, err := getStore(cfg, name).Get(address)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get credentials for %s from %s store: %w", address, name, err))
+			continue
+		}
+		add(name, map[string]types.AuthConfig{address: ac})
+	}
+
+	slices.SortFunc(creds, func(a, b storedCredentials) int {
+		if c := strings.Compare(a.registry, b.registry); c != 0 {
+			return c
+		}
+		return strings.Compare(a.store, b.store)
+	})
+	return creds, errs
+}
+
+// hasCredentials returns whether the auth config contains credentials, and
+// not just the email address that is kept in the configuration file.
+func hasCredentials(ac types.AuthConfig) bool {
+	return ac.Username != "" || ac.Password != "" || ac.IdentityToken != "" || ac.Auth != ""
+}
@@ -0,0 +1,55 @@
+package credentials
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/docker/cli/cli/config/types"
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/golden"
+)
+
+func TestList(t *testing.T) {
+	withFakeStores(t, map[string]*fakeStore{
+		"desktop": {auths: map[string]types.AuthConfig{
+			"https://index.docker.io/v1/": {Username: "hubuser", Password: "secret"},
+			"registry.example.com":        {Username: "shadowed", Password: "secret"},
+		}},
+		"gcloud": {
+			auths:     map[string]types.AuthConfig{"gcr.io": {Username: "_dcgcloud_token", Password: "secret"}},
+			getAllErr: errors.New("list is not supported"),
+		},
+	})
+	cfg := newTestConfigFile(t)
+	cfg.CredentialsStore = "desktop"
+	cfg.CredentialHelpers = map[string]string{"gcr.io": "gcloud"}
+	cfg.AuthConfigs = map[string]types.AuthConfig{
+		"registry.example.com":        {Username: "plain", Password: "secret"},
+		"https://index.docker.io/v1/": {},
+	}
+
+	testCases := []struct {
+		name   string
+		format string
+	}{
+		{name: "table"},
+		{name: "format", format: "{{.Registry}} {{.Store}}"},
+		{name: "json", format: "json"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := test.NewFakeCli(nil)
+			cli.SetConfigFile(cfg)
+			cmd := newListCommand(cli)
+			if tc.format != "" {
+				assert.NilError(t, cmd.Flags().Set("format", tc.format))
+			}
+			cmd.SetArgs([]string{})
+			assert.NilError(t, cmd.Execute())
+			golden.Assert(t, cli.OutBuffer().String(), "credentials-list."+tc.name+".golden")
+			assert.Check(t, is.Equal(cli.ErrBuffer().String(), "WARNING: failed to list credentials in gcloud store: list is not supported\n"))
+		})
+	}
+}
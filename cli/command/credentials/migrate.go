@@ -0,0 +1,124 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package credentials
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/spf13/cobra"
+)
+
+type migrateOptions struct {
+	registries []string
+	dryRun     bool
+}
+
+func newMigrateCommand(dockerCLI command.Cli) *cobra.Command {
+	var options migrateOptions
+
+	cmd := &cobra.Command{
+		Use:   "migrate [OPTIONS] [REGISTRY...]",
+		Short: "Move unencrypted credentials from the configuration file to a credential helper",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.registries = args
+			return runMigrate(dockerCLI, options)
+		},
+		ValidArgsFunction:     completeRegistries(dockerCLI),
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&options.dryRun, "dry-run", false, "Show the credentials that would be moved, without moving them")
+
+	return cmd
+}
+
+// runMigrate moves the credentials that are stored unencrypted in the
+// configuration file to the credential helper that serves their registry.
+// The credentials are only removed from the configuration file after they
+// are read back from the credential helper.
+func runMigrate(dockerCLI command.Cli, options migrateOptions) error {
+	cfg := dockerCLI.ConfigFile()
+
+	addresses := slices.Sorted(maps.Keys(cfg.GetAuthConfigs()))
+	if len(options.registries) > 0 {
+		addresses = addresses[:0]
+		for _, registry := range options.registries {
+			address := serverAddress(registry)
+			if !hasCredentials(cfg.GetAuthConfigs()[address]) {
+				return fmt.Errorf("no unencrypted credentials found for %s in %s", address, cfg.Filename)
+			}
+			addresses = append(addresses, address)
+		}
+	}
+
+	var migrated, skipped int
+	for _, address := range addresses {
+		ac := cfg.GetAuthConfigs()[address]
+		if !hasCredentials(ac) {
+			continue
+		}
+		store := configuredStore(cfg, address)
+		if store == fileStoreName {
+			_, _ = fmt.Fprintf(dockerCLI.Err(), "Skipping %s: no credential helper is configured\n", address)
+			skipped++
+			continue
+		}
+		if options.dryRun {
+			_, _ = fmt.Fprintf(dockerCLI.Out(), "Would move credentials for %s to the %s credential helper\n", address, store)
+			continue
+		}
+		if err := migrateCredentials(cfg, address, store); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(dockerCLI.Out(), "Moved credentials for %s to the %s credential helper\n", address, store)
+		migrated++
+	}
+
+	switch {
+	case skipped > 0 && migrated == 0 && !options.dryRun:
+		return errors.New("no credential helper is configured: use \"docker credentials set\" to configure one")
+	case skipped == 0 && migrated == 0 && !options.dryRun:
+		_, _ = fmt.Fprintf(dockerCLI.Out(), "No unencrypted credentials found in %s\n", cfg.Filename)
+	}
+	return nil
+}
+
+// migrateCredentials stores the credentials for the address in the credential
+// helper, and removes them from the configuration file. The credentials are
+// kept in the configuration file if they can't be read back from the helper.
+func migrateCredentials(cfg *configfile.ConfigFile, address, helper string) error {
+	ac := cfg.GetAuthConfigs()[address]
+	ac.ServerAddress = address
+
+	store := getStore(cfg, helper)
+	if err := store.Store(ac); err != nil {
+		return fmt.Errorf("failed to store credentials for %s in %s credential helper: %w", address, helper, err)
+	}
+
+	stored, err := store.Get(address)
+	if err == nil && (stored.Username != ac.Username || stored.Password != ac.Password || stored.IdentityToken != ac.IdentityToken) {
+		err = errors.New("stored credentials do not match")
+	}
+	if err != nil {
+		// Restore the unencrypted credentials, as the credential helper
+		// may already have removed them from the configuration file.
+		cfg.GetAuthConfigs()[address] = ac
+		if saveErr := cfg.Save(); saveErr != nil {
+			return errors.Join(fmt.Errorf("failed to verify credentials for %s in %s credential helper: %w", address, helper, err), saveErr)
+		}
+		return fmt.Errorf("failed to verify credentials for %s in %s credential helper, credentials are kept in %s: %w", address, helper, cfg.Filename, err)
+	}
+
+	// Keep the entry in the configuration file, as a credential helper does
+	// the same when storing credentials.
+	ac.Username, ac.Password, ac.Auth, ac.IdentityToken = "", "", "", ""
+	cfg.GetAuthConfigs()[address] = ac
+	return cfg.Save()
+}
@@ -0,0 +1,91 @@
+package credentials
+
+import (
+	"testing"
+
+	"github.com/docker/cli/cli/config/types"
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestMigrate(t *testing.T) {
+	desktop := &fakeStore{}
+	withFakeStores(t, map[string]*fakeStore{"desktop": desktop})
+	cfg := newTestConfigFile(t)
+	cfg.CredentialsStore = "desktop"
+	cfg.AuthConfigs = map[string]types.AuthConfig{
+		"https://index.docker.io/v1/": {Username: "hubuser", Password: "secret", ServerAddress: "https://index.docker.io/v1/"},
+		"registry.example.com":        {IdentityToken: "token", ServerAddress: "registry.example.com"},
+		"empty.example.com":           {ServerAddress: "empty.example.com"},
+	}
+	cli := test.NewFakeCli(nil)
+	cli.SetConfigFile(cfg)
+
+	cmd := newMigrateCommand(cli)
+	cmd.SetArgs([]string{"--dry-run"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Len(desktop.auths, 0))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), `Would move credentials for https://index.docker.io/v1/ to the desktop credential helper
+Would move credentials for registry.example.com to the desktop credential helper
+`))
+
+	cli.OutBuffer().Reset()
+	cmd = newMigrateCommand(cli)
+	cmd.SetArgs([]string{})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), `Moved credentials for https://index.docker.io/v1/ to the desktop credential helper
+Moved credentials for registry.example.com to the desktop credential helper
+`))
+	assert.Check(t, is.DeepEqual(desktop.auths, map[string]types.AuthConfig{
+		"https://index.docker.io/v1/": {Username: "hubuser", Password: "secret", ServerAddress: "https://index.docker.io/v1/"},
+		"registry.example.com":        {IdentityToken: "token", ServerAddress: "registry.example.com"},
+	}))
+
+	saved := loadConfigFile(t, cfg.Filename)
+	for address, ac := range saved.AuthConfigs {
+		assert.Check(t, !hasCredentials(ac), "credentials for %s were not removed", address)
+	}
+
+	cli.OutBuffer().Reset()
+	cmd = newMigrateCommand(cli)
+	cmd.SetArgs([]string{})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "No unencrypted credentials found in "+cfg.Filename+"\n"))
+}
+
+func TestMigrateVerifyFailed(t *testing.T) {
+	withFakeStores(t, map[string]*fakeStore{"desktop": {corrupt: true}})
+	cfg := newTestConfigFile(t)
+	cfg.CredentialHelpers = map[string]string{"registry.example.com": "desktop"}
+	cfg.AuthConfigs = map[string]types.AuthConfig{
+		"registry.example.com": {Username: "user", Password: "secret"},
+	}
+	cli := test.NewFakeCli(nil)
+	cli.SetConfigFile(cfg)
+
+	cmd := newMigrateCommand(cli)
+	cmd.SetArgs([]string{"registry.example.com"})
+	assert.Check(t, is.ErrorContains(cmd.Execute(), "failed to verify credentials for registry.example.com in desktop credential helper, credentials are kept in "+cfg.Filename))
+
+	saved := loadConfigFile(t, cfg.Filename)
+	assert.Check(t, is.Equal(saved.AuthConfigs["registry.example.com"].Password, "secret"))
+}
+
+func TestMigrateNoHelper(t *testing.T) {
+	cfg := newTestConfigFile(t)
+	cfg.AuthConfigs = map[string]types.AuthConfig{
+		"registry.example.com": {Username: "user", Password: "secret"},
+	}
+	cli := test.NewFakeCli(nil)
+	cli.SetConfigFile(cfg)
+
+	cmd := newMigrateCommand(cli)
+	cmd.SetArgs([]string{})
+	assert.Check(t, is.Error(cmd.Execute(), `no credential helper is configured: use "docker credentials set" to configure one`))
+	assert.Check(t, is.Equal(cli.ErrBuffer().String(), "Skipping registry.example.com: no credential helper is configured\n"))
+
+	cmd = newMigrateCommand(cli)
+	cmd.SetArgs([]string{"unknown.example.com"})
+	assert.Check(t, is.ErrorContains(cmd.Execute(), "no unencrypted credentials found for unknown.example.com"))
+}
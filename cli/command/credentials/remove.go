@@ -0,0 +1,78 @@
+package credentials
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+)
+
+type removeOptions struct {
+	registries  []string
+	defaultOnly bool
+}
+
+func newRemoveCommand(dockerCLI command.Cli) *cobra.Command {
+	var options removeOptions
+
+	cmd := &cobra.Command{
+		Use:     "rm [OPTIONS] [REGISTRY...]",
+		Aliases: []string{"remove"},
+		Short:   "Remove the credential helper of one or more registries",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch {
+			case options.defaultOnly && len(args) > 0:
+				return errors.New("a registry cannot be specified when using --default")
+			case !options.defaultOnly && len(args) == 0:
+				return errors.New("at least one registry is required, unless --default is set")
+			}
+			options.registries = args
+			return runRemove(dockerCLI, options)
+		},
+		ValidArgsFunction:     completeRegistries(dockerCLI),
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&options.defaultOnly, "default", false, "Remove the default credential store")
+
+	return cmd
+}
+
+// runRemove removes the credential helper of the registries, or the default
+// credential store, from the configuration file. Credentials that are stored
+// in the helper are not removed; use "docker logout" to remove them first.
+func runRemove(dockerCLI command.Cli, options removeOptions) error {
+	cfg := dockerCLI.ConfigFile()
+	if options.defaultOnly {
+		if cfg.CredentialsStore == "" {
+			return errors.New("no default credential store is configured")
+		}
+		cfg.CredentialsStore = ""
+		return cfg.Save()
+	}
+
+	var (
+		errs    []error
+		removed []string
+	)
+	for _, registry := range options.registries {
+		address := serverAddress(registry)
+		if _, ok := cfg.CredentialHelpers[address]; !ok {
+			errs = append(errs, fmt.Errorf("no credential helper is configured for %s", address))
+			continue
+		}
+		delete(cfg.CredentialHelpers, address)
+		removed = append(removed, address)
+	}
+	if len(removed) > 0 {
+		if err := cfg.Save(); err != nil {
+			return err
+		}
+		for _, address := range removed {
+			_, _ = fmt.Fprintln(dockerCLI.Out(), address)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,70 @@
+package credentials
+
+import (
+	"io"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestRemoveErrors(t *testing.T) {
+	testCases := []struct {
+		args          []string
+		expectedError string
+	}{
+		{
+			args:          []string{},
+			expectedError: "at least one registry is required, unless --default is set",
+		},
+		{
+			args:          []string{"--default", "gcr.io"},
+			expectedError: "a registry cannot be specified when using --default",
+		},
+		{
+			args:          []string{"--default"},
+			expectedError: "no default credential store is configured",
+		},
+		{
+			args:          []string{"gcr.io"},
+			expectedError: "no credential helper is configured for gcr.io",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.expectedError, func(t *testing.T) {
+			cli := test.NewFakeCli(nil)
+			cli.SetConfigFile(newTestConfigFile(t))
+			cmd := newRemoveCommand(cli)
+			cmd.SetArgs(tc.args)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			assert.Check(t, is.ErrorContains(cmd.Execute(), tc.expectedError))
+		})
+	}
+}
+
+func TestRemove(t *testing.T) {
+	cfg := newTestConfigFile(t)
+	cfg.CredentialsStore = "desktop"
+	cfg.CredentialHelpers = map[string]string{
+		"gcr.io":                      "gcloud",
+		"https://index.docker.io/v1/": "pass",
+		"registry.example.com":        "pass",
+	}
+	cli := test.NewFakeCli(nil)
+	cli.SetConfigFile(cfg)
+
+	cmd := newRemoveCommand(cli)
+	cmd.SetArgs([]string{"docker.io", "gcr.io", "unknown.example.com"})
+	assert.Check(t, is.Error(cmd.Execute(), "no credential helper is configured for unknown.example.com"))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "https://index.docker.io/v1/\ngcr.io\n"))
+
+	cmd = newRemoveCommand(cli)
+	cmd.SetArgs([]string{"--default"})
+	assert.NilError(t, cmd.Execute())
+
+	saved := loadConfigFile(t, cfg.Filename)
+	assert.Check(t, is.DeepEqual(saved.CredentialHelpers, map[string]string{"registry.example.com": "pass"}))
+	assert.Check(t, is.Equal(saved.CredentialsStore, ""))
+}
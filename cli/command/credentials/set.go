@@ -0,0 +1,72 @@
+package credentials
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/config/credentials"
+	"github.com/spf13/cobra"
+)
+
+type setOptions struct {
+	registry    string
+	helper      string
+	defaultOnly bool
+}
+
+func newSetCommand(dockerCLI command.Cli) *cobra.Command {
+	var options setOptions
+
+	cmd := &cobra.Command{
+		Use:   "set [OPTIONS] [REGISTRY] HELPER",
+		Short: "Set the credential helper for a registry",
+		Args:  cli.RequiresRangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch {
+			case options.defaultOnly && len(args) != 1:
+				return errors.New("a registry cannot be specified when using --default")
+			case !options.defaultOnly && len(args) != 2:
+				return errors.New("a registry and a credential helper are required, unless --default is set")
+			}
+			options.helper = args[len(args)-1]
+			if !options.defaultOnly {
+				options.registry = args[0]
+			}
+			return runSet(dockerCLI, options)
+		},
+		ValidArgsFunction:     cobra.NoFileCompletions,
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&options.defaultOnly, "default", false, "Set the default credential store, which is used for registries without a credential helper")
+
+	return cmd
+}
+
+// runSet configures the credential helper for a registry, or the default
+// credential store, in the configuration file. The credentials that are
+// already stored are not moved to the new helper.
+func runSet(dockerCLI command.Cli, options setOptions) error {
+	if options.helper == fileStoreName {
+		return errors.New("credentials are stored in the configuration file if no credential helper is set: use \"docker credentials rm\" to remove the credential helper")
+	}
+	if options.helper != credentials.EncryptedFileStoreName {
+		if _, err := lookPath(credentialHelperPrefix + options.helper); err != nil {
+			return fmt.Errorf("credential helper %q not found: %w", options.helper, err)
+		}
+	}
+
+	cfg := dockerCLI.ConfigFile()
+	if options.defaultOnly {
+		cfg.CredentialsStore = options.helper
+	} else {
+		if cfg.CredentialHelpers == nil {
+			cfg.CredentialHelpers = make(map[string]string)
+		}
+		cfg.CredentialHelpers[serverAddress(options.registry)] = options.helper
+	}
+	return cfg.Save()
+}
@@ -0,0 +1,88 @@
+package credentials
+
+import (
+	"io"
+	"os/exec"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func withFakeHelpers(t *testing.T, helpers ...string) {
+	t.Helper()
+	orig := lookPath
+	lookPath = func(file string) (string, error) {
+		for _, h := range helpers {
+			if file == credentialHelperPrefix+h {
+				return "/usr/local/bin/" + file, nil
+			}
+		}
+		return "", &exec.Error{Name: file, Err: exec.ErrNotFound}
+	}
+	t.Cleanup(func() { lookPath = orig })
+}
+
+func TestSetErrors(t *testing.T) {
+	withFakeHelpers(t, "desktop")
+	testCases := []struct {
+		args          []string
+		expectedError string
+	}{
+		{
+			args:          []string{"desktop"},
+			expectedError: "a registry and a credential helper are required, unless --default is set",
+		},
+		{
+			args:          []string{"--default", "registry.example.com", "desktop"},
+			expectedError: "a registry cannot be specified when using --default",
+		},
+		{
+			args:          []string{"registry.example.com", "file"},
+			expectedError: `use "docker credentials rm" to remove the credential helper`,
+		},
+		{
+			args:          []string{"registry.example.com", "unknown"},
+			expectedError: `credential helper "unknown" not found`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.expectedError, func(t *testing.T) {
+			cli := test.NewFakeCli(nil)
+			cli.SetConfigFile(newTestConfigFile(t))
+			cmd := newSetCommand(cli)
+			cmd.SetArgs(tc.args)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			assert.Check(t, is.ErrorContains(cmd.Execute(), tc.expectedError))
+		})
+	}
+}
+
+func TestSet(t *testing.T) {
+	withFakeHelpers(t, "desktop", "gcloud")
+	cfg := newTestConfigFile(t)
+	cli := test.NewFakeCli(nil)
+	cli.SetConfigFile(cfg)
+
+	cmd := newSetCommand(cli)
+	cmd.SetArgs([]string{"https://gcr.io/v2/", "gcloud"})
+	assert.NilError(t, cmd.Execute())
+
+	cmd = newSetCommand(cli)
+	cmd.SetArgs([]string{"--default", "desktop"})
+	assert.NilError(t, cmd.Execute())
+
+	// The encrypted file store is built in, and has no helper binary.
+	cmd = newSetCommand(cli)
+	cmd.SetArgs([]string{"registry.example.com", "encrypted-file"})
+	assert.NilError(t, cmd.Execute())
+
+	saved := loadConfigFile(t, cfg.Filename)
+	assert.Check(t, is.DeepEqual(saved.CredentialHelpers, map[string]string{
+		"gcr.io":               "gcloud",
+		"registry.example.com": "encrypted-file",
+	}))
+	assert.Check(t, is.Equal(saved.CredentialsStore, "desktop"))
+}
@@ -7,6 +7,7 @@
 	"errors"
 	"fmt"
 
+	"github.com/docker/cli/cli/config/configfile"
 	"github.com/docker/cli/cli/context/docker"
 	"github.com/docker/cli/cli/context/store"
 	cliflags "github.com/docker/cli/cli/flags"
@@ -54,7 +55,7 @@ type EndpointDefaultResolver interface {
 }
 
 // resolveDefaultContext creates a Metadata for the current CLI invocation parameters
-func resolveDefaultContext(opts *cliflags.ClientOptions, config store.Config) (*DefaultContext, error) {
+func resolveDefaultContext(opts *cliflags.ClientOptions, configFile *configfile.ConfigFile, config store.Config) (*DefaultContext, error) {
 	contextTLSData := store.ContextTLSData{
 		Endpoints: make(map[string]store.EndpointTLSData),
 	}
@@ -66,7 +67,7 @@ func resolveDefaultContext(opts *cliflags.ClientOptions, config store.Config) (*
 		Name: DefaultContextName,
 	}
 
-	dockerEP, err := resolveDefaultDockerEndpoint(opts)
+	dockerEP, err := resolveDefaultDockerEndpoint(opts, configFile)
 	if err != nil {
 		return nil, err
 	}
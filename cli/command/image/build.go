@@ -99,6 +99,7 @@ func newBuildCommand(dockerCLI command.Cli) *cobra.Command {
 		Args:  cli.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			options.context = args[0]
+			options.platform = command.DefaultPlatform(dockerCLI, options.platform)
 			return runBuild(cmd.Context(), dockerCLI, options)
 		},
 		Annotations: map[string]string{
@@ -2,13 +2,18 @@
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"net/http"
 	"strings"
 	"time"
 
+	"github.com/distribution/reference"
+	"github.com/docker/cli/internal/registryclient"
+	"github.com/docker/distribution"
 	"github.com/moby/moby/api/types/image"
 	"github.com/moby/moby/client"
+	"github.com/opencontainers/go-digest"
 )
 
 type fakeClient struct {
@@ -130,3 +135,49 @@ func (cli *fakeClient) ImageBuild(ctx context.Context, buildContext io.Reader, o
 	}
 	return client.ImageBuildResult{Body: io.NopCloser(strings.NewReader(""))}, nil
 }
+
+type fakeRegistryClient struct {
+	registryclient.RegistryClient
+	manifests     map[string]distribution.Manifest
+	mountBlobFunc func(ctx context.Context, source reference.Canonical, target reference.Named) error
+	copyBlobFunc  func(ctx context.Context, source reference.Canonical, target reference.Named) error
+	blobs         map[digest.Digest][]byte
+	pushed        map[string]distribution.Manifest
+}
+
+func (c *fakeRegistryClient) GetRawManifest(_ context.Context, ref reference.Named) (distribution.Manifest, error) {
+	if m, ok := c.manifests[ref.String()]; ok {
+		return m, nil
+	}
+	return nil, fmt.Errorf("no such manifest: %s", ref)
+}
+
+func (c *fakeRegistryClient) GetBlob(_ context.Context, ref reference.Canonical) ([]byte, error) {
+	if b, ok := c.blobs[ref.Digest()]; ok {
+		return b, nil
+	}
+	return nil, fmt.Errorf("no such blob: %s", ref)
+}
+
+func (c *fakeRegistryClient) MountBlob(ctx context.Context, source reference.Canonical, target reference.Named) error {
+	if c.mountBlobFunc != nil {
+		return c.mountBlobFunc(ctx, source, target)
+	}
+	return nil
+}
+
+func (c *fakeRegistryClient) CopyBlob(ctx context.Context, source reference.Canonical, target reference.Named) error {
+	if c.copyBlobFunc != nil {
+		return c.copyBlobFunc(ctx, source, target)
+	}
+	return nil
+}
+
+func (c *fakeRegistryClient) PutManifest(_ context.Context, ref reference.Named, mf distribution.Manifest) (digest.Digest, error) {
+	if c.pushed == nil {
+		c.pushed = make(map[string]distribution.Manifest)
+	}
+	c.pushed[ref.String()] = mf
+	_, payload, err := mf.Payload()
+	return digest.FromBytes(payload), err
+}
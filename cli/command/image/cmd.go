@@ -33,6 +33,7 @@ func newImageCommand(dockerCli command.Cli) *cobra.Command {
 	}
 	cmd.AddCommand(
 		newBuildCommand(dockerCli),
+		newCopyCommand(dockerCli),
 		newHistoryCommand(dockerCli),
 		newImportCommand(dockerCli),
 		newLoadCommand(dockerCli),
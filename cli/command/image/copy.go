@@ -0,0 +1,250 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package image
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/containerd/platforms"
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	manifesttypes "github.com/docker/cli/cli/manifest/types"
+	"github.com/docker/cli/internal/registryclient"
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/cobra"
+)
+
+type copyOptions struct {
+	source    string
+	target    string
+	platforms []string
+	insecure  bool
+	quiet     bool
+}
+
+// newCopyCommand creates a new `docker image copy` command
+func newCopyCommand(dockerCLI command.Cli) *cobra.Command {
+	var opts copyOptions
+
+	cmd := &cobra.Command{
+		Use:   "copy [OPTIONS] SOURCE_IMAGE[:TAG] TARGET_IMAGE[:TAG]",
+		Short: "Copy an image from one registry or repository to another",
+		Args:  cli.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.source = args[0]
+			opts.target = args[1]
+			return runCopy(cmd.Context(), dockerCLI, opts)
+		},
+		ValidArgsFunction:     completion.ImageNames(dockerCLI, 2),
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.StringSliceVar(&opts.platforms, "platform", nil, `Only copy the given platforms of a multi-platform image
+'os[/arch[/variant]]': Explicit platform (eg. linux/amd64)`)
+	flags.BoolVar(&opts.insecure, "insecure", false, "Allow communication with insecure registries")
+	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Only display the digest of the copied image")
+
+	_ = cmd.RegisterFlagCompletionFunc("platform", completion.Platforms())
+
+	return cmd
+}
+
+// runCopy copies an image between repositories, streaming the manifests and
+// blobs from the source registry to the target registry.
+func runCopy(ctx context.Context, dockerCLI command.Cli, opts copyOptions) error {
+	var matcher platforms.MatchComparer
+	if len(opts.platforms) > 0 {
+		ps, err := platforms.ParseAll(opts.platforms)
+		if err != nil {
+			return err
+		}
+		matcher = platforms.Any(ps...)
+	}
+
+	sourceRef, err := reference.ParseNormalizedNamed(opts.source)
+	if err != nil {
+		return err
+	}
+	sourceRef = reference.TagNameOnly(sourceRef)
+
+	targetRef, err := reference.ParseNormalizedNamed(opts.target)
+	if err != nil {
+		return err
+	}
+	if _, ok := targetRef.(reference.Digested); ok {
+		return errors.New("target image must be referenced by tag, not by digest")
+	}
+	targetRef = reference.TagNameOnly(targetRef)
+
+	var out io.Writer = dockerCLI.Out()
+	if opts.quiet {
+		out = io.Discard
+	}
+	c := &imageCopier{
+		client: command.NewRegistryClient(dockerCLI, opts.insecure),
+		out:    out,
+		source: reference.TrimNamed(sourceRef),
+		target: reference.TrimNamed(targetRef),
+		copied: make(map[digest.Digest]bool),
+	}
+
+	mfst, err := c.client.GetRawManifest(ctx, sourceRef)
+	if err != nil {
+		return err
+	}
+	if list, ok := mfst.(*manifestlist.DeserializedManifestList); ok {
+		mfst, err = c.copyList(ctx, list, matcher)
+	} else {
+		err = c.copyBlobs(ctx, mfst)
+	}
+	if err != nil {
+		return err
+	}
+
+	dgst, err := c.client.PutManifest(ctx, targetRef, mfst)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(out, "Copied %s to %s\n", reference.FamiliarString(sourceRef), reference.FamiliarString(targetRef))
+	_, _ = fmt.Fprintln(dockerCLI.Out(), dgst.String())
+	return nil
+}
+
+// imageCopier copies the contents of manifests from the source repository
+// to the target repository.
+type imageCopier struct {
+	client registryclient.RegistryClient
+	out    io.Writer
+	source reference.Named
+	target reference.Named
+
+	// copied are the blobs that are already in the target repository.
+	copied map[digest.Digest]bool
+}
+
+// copyList copies the manifests of a manifest list that match the platforms,
+// and returns the manifest list to push to the target repository. The
+// list is returned as-is if all its manifests are copied, so that its digest
+// is preserved.
+func (c *imageCopier) copyList(ctx context.Context, list *manifestlist.DeserializedManifestList, matcher platforms.MatchComparer) (distribution.Manifest, error) {
+	descriptors := filterManifestDescriptors(list.Manifests, matcher)
+	if len(descriptors) == 0 {
+		return nil, fmt.Errorf("%s has no manifests matching the given platforms", reference.FamiliarString(c.source))
+	}
+
+	for _, desc := range descriptors {
+		sourceRef, err := reference.WithDigest(c.source, desc.Digest)
+		if err != nil {
+			return nil, err
+		}
+		mfst, err := c.client.GetRawManifest(ctx, sourceRef)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.copyBlobs(ctx, mfst); err != nil {
+			return nil, err
+		}
+		targetRef, err := reference.WithDigest(c.target, desc.Digest)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := c.client.PutManifest(ctx, targetRef, mfst); err != nil {
+			return nil, err
+		}
+		_, _ = fmt.Fprintf(c.out, "Copied manifest %s (%s)\n", desc.Digest, platforms.Format(*manifesttypes.OCIPlatform(&desc.Platform)))
+	}
+
+	if len(descriptors) == len(list.Manifests) {
+		return list, nil
+	}
+	mediaType, _, err := list.Payload()
+	if err != nil {
+		return nil, err
+	}
+	return manifestlist.FromDescriptorsWithMediaType(descriptors, mediaType)
+}
+
+// filterManifestDescriptors returns the descriptors that match the platforms,
+// and the attestation manifests of the matching images.
+func filterManifestDescriptors(descriptors []manifestlist.ManifestDescriptor, matcher platforms.MatchComparer) []manifestlist.ManifestDescriptor {
+	if matcher == nil {
+		return descriptors
+	}
+	var filtered []manifestlist.ManifestDescriptor
+	matched := make(map[digest.Digest]bool)
+	for _, desc := range descriptors {
+		if desc.Annotations[manifesttypes.AnnotationReferenceType] == manifesttypes.ReferenceTypeAttestation {
+			continue
+		}
+		if matcher.Match(*manifesttypes.OCIPlatform(&desc.Platform)) {
+			matched[desc.Digest] = true
+			filtered = append(filtered, desc)
+		}
+	}
+	for _, desc := range descriptors {
+		if desc.Annotations[manifesttypes.AnnotationReferenceType] != manifesttypes.ReferenceTypeAttestation {
+			continue
+		}
+		if matched[digest.Digest(desc.Annotations[manifesttypes.AnnotationReferenceDigest])] {
+			filtered = append(filtered, desc)
+		}
+	}
+	return filtered
+}
+
+// copyBlobs makes the blobs referenced by the manifest available in the
+// target repository. Blobs are mounted if the source and target repository
+// are on the same registry, and copied otherwise. Blobs that already exist
+// in the target repository are skipped, and interrupted uploads are resumed,
+// so that an interrupted copy can be resumed.
+func (c *imageCopier) copyBlobs(ctx context.Context, mfst distribution.Manifest) error {
+	sameRegistry := reference.Domain(c.source) == reference.Domain(c.target)
+	for _, desc := range mfst.References() {
+		if c.copied[desc.Digest] || isForeignLayer(desc.MediaType) {
+			continue
+		}
+		sourceRef, err := reference.WithDigest(c.source, desc.Digest)
+		if err != nil {
+			return err
+		}
+		if sameRegistry {
+			// Fall back to copying the blob if it can't be mounted.
+			if err := c.client.MountBlob(ctx, sourceRef, c.target); err == nil {
+				c.copied[desc.Digest] = true
+				_, _ = fmt.Fprintf(c.out, "Mounted blob %s\n", desc.Digest)
+				continue
+			}
+		}
+		if err := c.client.CopyBlob(ctx, sourceRef, c.target); err != nil {
+			return err
+		}
+		c.copied[desc.Digest] = true
+		_, _ = fmt.Fprintf(c.out, "Copied blob %s\n", desc.Digest)
+	}
+	return nil
+}
+
+// isForeignLayer returns whether the layer is not stored in the registry,
+// such as the base layers of Windows images.
+func isForeignLayer(mediaType string) bool {
+	switch mediaType {
+	case schema2.MediaTypeForeignLayer,
+		ocispec.MediaTypeImageLayerNonDistributable,     //nolint:staticcheck // ignore SA1019: Non-distributable layers are deprecated, and not recommended for future use.
+		ocispec.MediaTypeImageLayerNonDistributableGzip, //nolint:staticcheck // ignore SA1019: Non-distributable layers are deprecated, and not recommended for future use.
+		ocispec.MediaTypeImageLayerNonDistributableZstd: //nolint:staticcheck // ignore SA1019: Non-distributable layers are deprecated, and not recommended for future use.
+		return true
+	default:
+		return false
+	}
+}
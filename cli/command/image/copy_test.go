@@ -0,0 +1,174 @@
+package image
+
+import (
+	"context"
+	"testing"
+
+	"github.com/distribution/reference"
+	manifesttypes "github.com/docker/cli/cli/manifest/types"
+	"github.com/docker/cli/internal/registryclient"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/opencontainers/go-digest"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func testImageManifest(t *testing.T, layer string) *schema2.DeserializedManifest {
+	t.Helper()
+	m, err := schema2.FromStruct(schema2.Manifest{
+		Versioned: schema2.SchemaVersion,
+		Config: distribution.Descriptor{
+			MediaType: schema2.MediaTypeImageConfig,
+			Digest:    digest.FromString("config-" + layer),
+			Size:      10,
+		},
+		Layers: []distribution.Descriptor{
+			{MediaType: schema2.MediaTypeLayer, Digest: digest.FromString("shared"), Size: 10},
+			{MediaType: schema2.MediaTypeLayer, Digest: digest.FromString(layer), Size: 10},
+		},
+	})
+	assert.NilError(t, err)
+	return m
+}
+
+func manifestDescriptor(t *testing.T, m distribution.Manifest, os, arch string, annotations map[string]string) manifestlist.ManifestDescriptor {
+	t.Helper()
+	mediaType, payload, err := m.Payload()
+	assert.NilError(t, err)
+	return manifestlist.ManifestDescriptor{
+		Descriptor: distribution.Descriptor{
+			MediaType:   mediaType,
+			Digest:      digest.FromBytes(payload),
+			Size:        int64(len(payload)),
+			Annotations: annotations,
+		},
+		Platform: manifestlist.PlatformSpec{OS: os, Architecture: arch},
+	}
+}
+
+func TestRunCopyErrors(t *testing.T) {
+	testCases := []struct {
+		doc           string
+		opts          copyOptions
+		expectedError string
+	}{
+		{
+			doc:           "target by digest",
+			opts:          copyOptions{source: "alpine", target: "example.com/alpine@" + digest.FromString("foo").String()},
+			expectedError: "target image must be referenced by tag, not by digest",
+		},
+		{
+			doc:           "invalid platform",
+			opts:          copyOptions{source: "alpine", target: "example.com/alpine", platforms: []string{"linux/amd64/v1/x"}},
+			expectedError: `cannot parse platform specifier`,
+		},
+		{
+			doc:           "missing source",
+			opts:          copyOptions{source: "alpine", target: "example.com/alpine"},
+			expectedError: "no such manifest: docker.io/library/alpine:latest",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.doc, func(t *testing.T) {
+			cli := test.NewFakeCli(nil)
+			cli.SetRegistryClient(&fakeRegistryClient{})
+			err := runCopy(context.Background(), cli, tc.opts)
+			assert.Check(t, is.ErrorContains(err, tc.expectedError))
+		})
+	}
+}
+
+func TestRunCopyManifest(t *testing.T) {
+	img := testImageManifest(t, "amd64")
+	var copied []string
+	client := &fakeRegistryClient{
+		manifests: map[string]distribution.Manifest{
+			"docker.io/library/alpine:3.0": img,
+		},
+		mountBlobFunc: func(context.Context, reference.Canonical, reference.Named) error {
+			t.Fatal("blobs must not be mounted across registries")
+			return nil
+		},
+		copyBlobFunc: func(_ context.Context, source reference.Canonical, target reference.Named) error {
+			assert.Check(t, is.Equal(target.String(), "example.com/alpine"))
+			copied = append(copied, source.String())
+			return nil
+		},
+	}
+	cli := test.NewFakeCli(nil)
+	cli.SetRegistryClient(client)
+
+	err := runCopy(context.Background(), cli, copyOptions{source: "alpine:3.0", target: "example.com/alpine:3.0", quiet: true})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(copied, []string{
+		"docker.io/library/alpine@" + digest.FromString("config-amd64").String(),
+		"docker.io/library/alpine@" + digest.FromString("shared").String(),
+		"docker.io/library/alpine@" + digest.FromString("amd64").String(),
+	}))
+	assert.Check(t, is.Equal(client.pushed["example.com/alpine:3.0"], distribution.Manifest(img)))
+	_, payload, _ := img.Payload()
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), digest.FromBytes(payload).String()+"\n"))
+}
+
+func TestRunCopyListWithPlatform(t *testing.T) {
+	amd64 := testImageManifest(t, "amd64")
+	arm64 := testImageManifest(t, "arm64")
+	amd64Desc := manifestDescriptor(t, amd64, "linux", "amd64", nil)
+	arm64Desc := manifestDescriptor(t, arm64, "linux", "arm64", nil)
+	attestation := testImageManifest(t, "attestation")
+	attestationDesc := manifestDescriptor(t, attestation, "unknown", "unknown", map[string]string{
+		manifesttypes.AnnotationReferenceType:   manifesttypes.ReferenceTypeAttestation,
+		manifesttypes.AnnotationReferenceDigest: amd64Desc.Digest.String(),
+	})
+	list, err := manifestlist.FromDescriptors([]manifestlist.ManifestDescriptor{amd64Desc, arm64Desc, attestationDesc})
+	assert.NilError(t, err)
+
+	var mounted, copied []digest.Digest
+	client := &fakeRegistryClient{
+		manifests: map[string]distribution.Manifest{
+			"example.com/alpine:3.0":                                list,
+			"example.com/alpine@" + amd64Desc.Digest.String():       amd64,
+			"example.com/alpine@" + arm64Desc.Digest.String():       arm64,
+			"example.com/alpine@" + attestationDesc.Digest.String(): attestation,
+		},
+		mountBlobFunc: func(_ context.Context, source reference.Canonical, target reference.Named) error {
+			if source.Digest() == digest.FromString("amd64") {
+				return registryclient.ErrBlobCreated{From: source, Target: target}
+			}
+			mounted = append(mounted, source.Digest())
+			return nil
+		},
+		copyBlobFunc: func(_ context.Context, source reference.Canonical, _ reference.Named) error {
+			copied = append(copied, source.Digest())
+			return nil
+		},
+	}
+	cli := test.NewFakeCli(nil)
+	cli.SetRegistryClient(client)
+
+	err = runCopy(context.Background(), cli, copyOptions{
+		source:    "example.com/alpine:3.0",
+		target:    "example.com/mirror/alpine:3.0",
+		platforms: []string{"linux/amd64"},
+	})
+	assert.NilError(t, err)
+
+	// Blobs that are shared between manifests are only mounted once.
+	assert.Check(t, is.DeepEqual(mounted, []digest.Digest{
+		digest.FromString("config-amd64"),
+		digest.FromString("shared"),
+		digest.FromString("config-attestation"),
+		digest.FromString("attestation"),
+	}))
+	assert.Check(t, is.DeepEqual(copied, []digest.Digest{digest.FromString("amd64")}))
+
+	assert.Check(t, is.Len(client.pushed, 3))
+	assert.Check(t, client.pushed["example.com/mirror/alpine@"+amd64Desc.Digest.String()] != nil)
+	assert.Check(t, client.pushed["example.com/mirror/alpine@"+attestationDesc.Digest.String()] != nil)
+	pushedList, ok := client.pushed["example.com/mirror/alpine:3.0"].(*manifestlist.DeserializedManifestList)
+	assert.Assert(t, ok)
+	assert.Check(t, is.DeepEqual(pushedList.Manifests, []manifestlist.ManifestDescriptor{amd64Desc, attestationDesc}))
+}
@@ -37,6 +37,7 @@ func newImportCommand(dockerCLI command.Cli) *cobra.Command {
 			if len(args) > 1 {
 				options.reference = args[1]
 			}
+			options.platform = command.DefaultPlatform(dockerCLI, options.platform)
 			return runImport(cmd.Context(), dockerCLI, options)
 		},
 		Annotations: map[string]string{
@@ -23,6 +23,7 @@ type inspectOptions struct {
 	format   string
 	refs     []string
 	platform string
+	remote   bool
 }
 
 // newInspectCommand creates a new cobra.Command for `docker image inspect`
@@ -51,6 +52,7 @@ func newInspectCommand(dockerCLI command.Cli) *cobra.Command {
 If the image or the server is not multi-platform capable, the command will error out if the platform does not match.
 'os[/arch[/variant]]': Explicit platform (eg. linux/amd64)`)
 	flags.SetAnnotation("platform", "version", []string{"1.49"})
+	flags.BoolVar(&opts.remote, "remote", false, "Inspect the image in the registry, without pulling it")
 
 	_ = cmd.RegisterFlagCompletionFunc("platform", completion.Platforms())
 	return cmd
@@ -66,6 +68,14 @@ func runInspect(ctx context.Context, dockerCLI command.Cli, opts inspectOptions)
 		platform = &p
 	}
 
+	if opts.remote {
+		registryClient := command.NewRegistryClient(dockerCLI, false)
+		return inspect.Inspect(dockerCLI.Out(), opts.refs, opts.format, func(ref string) (any, []byte, error) {
+			resp, err := inspectRemote(ctx, registryClient, ref, platform)
+			return remoteInspectResponse{InspectResponse: resp}, nil, err
+		})
+	}
+
 	apiClient := dockerCLI.Client()
 	return inspect.Inspect(dockerCLI.Out(), opts.refs, opts.format, func(ref string) (any, []byte, error) {
 		var buf bytes.Buffer
@@ -0,0 +1,171 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/containerd/platforms"
+	"github.com/distribution/reference"
+	manifesttypes "github.com/docker/cli/cli/manifest/types"
+	"github.com/docker/cli/internal/registryclient"
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/ocischema"
+	"github.com/docker/distribution/manifest/schema2"
+	dockerspec "github.com/moby/docker-image-spec/specs-go/v1"
+	"github.com/moby/moby/api/types/image"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// remoteImageConfig is the config of an image, including the Docker-specific
+// fields that are not part of the OCI image spec.
+type remoteImageConfig struct {
+	dockerspec.DockerOCIImage
+	Comment string `json:"comment,omitempty"`
+}
+
+// remoteInspectResponse is the inspect response of an image in a registry.
+type remoteInspectResponse struct {
+	image.InspectResponse
+
+	// Metadata shadows the metadata of the image in the local image cache,
+	// which does not apply to images in a registry, to omit it from the
+	// output.
+	Metadata *image.Metadata `json:"Metadata,omitempty"`
+}
+
+// inspectRemote returns the details of an image in a registry, in the same
+// format as the inspect of a local image. For multi-platform images, the
+// image for the given platform, or the default platform, is inspected.
+func inspectRemote(ctx context.Context, client registryclient.RegistryClient, name string, platform *ocispec.Platform) (image.InspectResponse, error) {
+	ref, err := reference.ParseNormalizedNamed(name)
+	if err != nil {
+		return image.InspectResponse{}, err
+	}
+	ref = reference.TagNameOnly(ref)
+
+	mfst, err := client.GetRawManifest(ctx, ref)
+	if err != nil {
+		return image.InspectResponse{}, err
+	}
+	mediaType, payload, err := mfst.Payload()
+	if err != nil {
+		return image.InspectResponse{}, err
+	}
+	desc := ocispec.Descriptor{
+		MediaType: mediaType,
+		Digest:    digest.FromBytes(payload),
+		Size:      int64(len(payload)),
+	}
+
+	matcher := platforms.Default()
+	if platform != nil {
+		matcher = platforms.Only(*platform)
+	}
+
+	if list, ok := mfst.(*manifestlist.DeserializedManifestList); ok {
+		dgst, err := selectManifest(list, matcher)
+		if err != nil {
+			return image.InspectResponse{}, fmt.Errorf("%s: %w", reference.FamiliarString(ref), err)
+		}
+		imgRef, err := reference.WithDigest(reference.TrimNamed(ref), dgst)
+		if err != nil {
+			return image.InspectResponse{}, err
+		}
+		mfst, err = client.GetRawManifest(ctx, imgRef)
+		if err != nil {
+			return image.InspectResponse{}, err
+		}
+	}
+
+	var configDesc distribution.Descriptor
+	var layers []distribution.Descriptor
+	switch m := mfst.(type) {
+	case *schema2.DeserializedManifest:
+		configDesc, layers = m.Config, m.Layers
+	case *ocischema.DeserializedManifest:
+		configDesc, layers = m.Config, m.Layers
+	default:
+		return image.InspectResponse{}, fmt.Errorf("%s: unsupported manifest type: %T", reference.FamiliarString(ref), mfst)
+	}
+
+	configRef, err := reference.WithDigest(reference.TrimNamed(ref), configDesc.Digest)
+	if err != nil {
+		return image.InspectResponse{}, err
+	}
+	configJSON, err := client.GetBlob(ctx, configRef)
+	if err != nil {
+		return image.InspectResponse{}, err
+	}
+	var cfg remoteImageConfig
+	if err := json.Unmarshal(configJSON, &cfg); err != nil {
+		return image.InspectResponse{}, fmt.Errorf("%s: invalid image config: %w", reference.FamiliarString(ref), err)
+	}
+	if platform != nil && !matcher.Match(cfg.Platform) {
+		return image.InspectResponse{}, fmt.Errorf("image %s was found but does not provide the specified platform (%s)", reference.FamiliarString(ref), platforms.FormatAll(*platform))
+	}
+
+	return newRemoteInspectResponse(ref, desc, configDesc.Digest, cfg, layers), nil
+}
+
+// selectManifest returns the digest of the image manifest in the list that
+// best matches the platform, ignoring attestations.
+func selectManifest(list *manifestlist.DeserializedManifestList, matcher platforms.MatchComparer) (digest.Digest, error) {
+	var best *manifestlist.ManifestDescriptor
+	for i, m := range list.Manifests {
+		if m.Annotations[manifesttypes.AnnotationReferenceType] == manifesttypes.ReferenceTypeAttestation {
+			continue
+		}
+		p := manifesttypes.OCIPlatform(&m.Platform)
+		if !matcher.Match(*p) {
+			continue
+		}
+		if best == nil || matcher.Less(*p, *manifesttypes.OCIPlatform(&best.Platform)) {
+			best = &list.Manifests[i]
+		}
+	}
+	if best == nil {
+		return "", fmt.Errorf("no matching manifest in the manifest list entries")
+	}
+	return best.Digest, nil
+}
+
+func newRemoteInspectResponse(ref reference.Named, desc ocispec.Descriptor, id digest.Digest, cfg remoteImageConfig, layers []distribution.Descriptor) image.InspectResponse {
+	resp := image.InspectResponse{
+		ID:           id.String(),
+		RepoTags:     []string{},
+		RepoDigests:  []string{reference.FamiliarName(ref) + "@" + desc.Digest.String()},
+		Comment:      cfg.Comment,
+		Author:       cfg.Author,
+		Config:       &cfg.Config,
+		Architecture: cfg.Architecture,
+		Variant:      cfg.Variant,
+		Os:           cfg.OS,
+		OsVersion:    cfg.OSVersion,
+		RootFS: image.RootFS{
+			Type: cfg.RootFS.Type,
+		},
+		Descriptor: &desc,
+	}
+	if tagged, ok := ref.(reference.Tagged); ok {
+		resp.RepoTags = append(resp.RepoTags, reference.FamiliarName(ref)+":"+tagged.Tag())
+	}
+	if cfg.Created != nil {
+		resp.Created = cfg.Created.Format(time.RFC3339Nano)
+	}
+	for _, l := range cfg.RootFS.DiffIDs {
+		resp.RootFS.Layers = append(resp.RootFS.Layers, l.String())
+	}
+	// The size of the image in the registry is the size of its compressed
+	// layers, as the image is not unpacked.
+	for _, l := range layers {
+		resp.Size += l.Size
+	}
+	return resp
+}
@@ -6,8 +6,12 @@
 	"testing"
 
 	"github.com/docker/cli/internal/test"
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/schema2"
 	"github.com/moby/moby/api/types/image"
 	"github.com/moby/moby/client"
+	"github.com/opencontainers/go-digest"
 	"gotest.tools/v3/assert"
 	is "gotest.tools/v3/assert/cmp"
 	"gotest.tools/v3/golden"
@@ -94,3 +98,69 @@ func TestNewInspectCommandSuccess(t *testing.T) {
 		})
 	}
 }
+
+func TestNewInspectCommandRemote(t *testing.T) {
+	configs := map[string]string{
+		"amd64": `{"architecture":"amd64","os":"linux","created":"2026-01-02T03:04:05Z","author":"docker","config":{"Entrypoint":["/entrypoint.sh"],"Labels":{"com.example.version":"1.0"}},"rootfs":{"type":"layers","diff_ids":["sha256:a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"]}}`,
+		"arm64": `{"architecture":"arm64","variant":"v8","os":"linux","config":{},"rootfs":{"type":"layers"}}`,
+	}
+	blobs := make(map[digest.Digest][]byte)
+	manifests := make(map[string]distribution.Manifest)
+	var descriptors []manifestlist.ManifestDescriptor
+	for _, arch := range []string{"amd64", "arm64"} {
+		cfg := []byte(configs[arch])
+		blobs[digest.FromBytes(cfg)] = cfg
+		m, err := schema2.FromStruct(schema2.Manifest{
+			Versioned: schema2.SchemaVersion,
+			Config:    distribution.Descriptor{MediaType: schema2.MediaTypeImageConfig, Digest: digest.FromBytes(cfg), Size: int64(len(cfg))},
+			Layers: []distribution.Descriptor{
+				{MediaType: schema2.MediaTypeLayer, Digest: digest.FromString(arch), Size: 1000},
+				{MediaType: schema2.MediaTypeLayer, Digest: digest.FromString(arch + "-2"), Size: 24},
+			},
+		})
+		assert.NilError(t, err)
+		desc := manifestDescriptor(t, m, "linux", arch, nil)
+		manifests["docker.io/library/alpine@"+desc.Digest.String()] = m
+		descriptors = append(descriptors, desc)
+	}
+	list, err := manifestlist.FromDescriptors(descriptors)
+	assert.NilError(t, err)
+	manifests["docker.io/library/alpine:3.0"] = list
+
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{
+			name: "remote",
+			args: []string{"--remote", "--platform", "linux/amd64", "alpine:3.0"},
+		},
+		{
+			name: "remote-format",
+			args: []string{"--remote", "--platform", "linux/arm64", "--format", "{{.Architecture}}/{{.Variant}} {{.Size}}", "alpine:3.0"},
+		},
+		{
+			name:          "remote-no-match",
+			args:          []string{"--remote", "--platform", "windows/amd64", "alpine:3.0"},
+			expectedError: "alpine:3.0: no matching manifest in the manifest list entries",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := test.NewFakeCli(&fakeClient{})
+			cli.SetRegistryClient(&fakeRegistryClient{manifests: manifests, blobs: blobs})
+			cmd := newInspectCommand(cli)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			cmd.SetArgs(tc.args)
+			err := cmd.Execute()
+			if tc.expectedError != "" {
+				assert.Check(t, is.Error(err, tc.expectedError))
+				return
+			}
+			assert.NilError(t, err)
+			golden.Assert(t, cli.OutBuffer().String(), fmt.Sprintf("inspect-command-success.%s.golden", tc.name))
+		})
+	}
+}
@@ -37,6 +37,7 @@ func newPullCommand(dockerCLI command.Cli) *cobra.Command {
 		Args:  cli.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts.remote = args[0]
+			opts.platform = command.DefaultPlatform(dockerCLI, opts.platform)
 			return runPull(cmd.Context(), dockerCLI, opts)
 		},
 		Annotations: map[string]string{
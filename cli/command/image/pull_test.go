@@ -6,6 +6,8 @@
 	"net/http"
 	"testing"
 
+	"github.com/containerd/platforms"
+	"github.com/docker/cli/cli/config/configfile"
 	"github.com/docker/cli/internal/test"
 	"github.com/moby/moby/client"
 	"gotest.tools/v3/assert"
@@ -88,3 +90,50 @@ func TestNewPullCommandSuccess(t *testing.T) {
 		})
 	}
 }
+
+func TestNewPullCommandDefaultPlatform(t *testing.T) {
+	testCases := []struct {
+		name             string
+		args             []string
+		env              string
+		expectedPlatform string
+	}{
+		{
+			name:             "config",
+			args:             []string{"image"},
+			expectedPlatform: "linux/arm64",
+		},
+		{
+			name:             "env",
+			args:             []string{"image"},
+			env:              "linux/s390x",
+			expectedPlatform: "linux/s390x",
+		},
+		{
+			name:             "flag",
+			args:             []string{"--platform", "linux/riscv64", "image"},
+			env:              "linux/s390x",
+			expectedPlatform: "linux/riscv64",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("DOCKER_DEFAULT_PLATFORM", tc.env)
+			cli := test.NewFakeCli(&fakeClient{
+				imagePullFunc: func(ref string, options client.ImagePullOptions) (client.ImagePullResponse, error) {
+					assert.Check(t, is.Len(options.Platforms, 1))
+					if len(options.Platforms) == 1 {
+						assert.Check(t, is.Equal(platforms.Format(options.Platforms[0]), tc.expectedPlatform))
+					}
+					return fakeStreamResult{ReadCloser: http.NoBody}, nil
+				},
+			})
+			cli.SetConfigFile(&configfile.ConfigFile{DefaultPlatform: "linux/arm64"})
+			cmd := newPullCommand(cli)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			cmd.SetArgs(tc.args)
+			assert.NilError(t, cmd.Execute())
+		})
+	}
+}
@@ -4,8 +4,9 @@
 package manifest
 
 import (
-	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"path/filepath"
 	"slices"
 
@@ -14,27 +15,27 @@
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/config"
 	"github.com/docker/cli/cli/manifest/store"
-	"github.com/docker/cli/internal/registryclient"
-	"github.com/moby/moby/api/types/registry"
+	"github.com/docker/cli/opts"
+	"github.com/docker/distribution/manifest/manifestlist"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/spf13/cobra"
 )
 
 type annotateOptions struct {
-	target     string // the target manifest list name (also transaction ID)
-	image      string // the manifest to annotate within the list
-	variant    string // an architecture variant
-	os         string
-	arch       string
-	osFeatures []string
-	osVersion  string
+	target      string // the target manifest list name (also transaction ID)
+	image       string // the manifest to annotate within the list
+	variant     string // an architecture variant
+	os          string
+	arch        string
+	osFeatures  []string
+	osVersion   string
+	annotations opts.ListOpts
 }
 
 // manifestStoreProvider is used in tests to provide a dummy store.
 type manifestStoreProvider interface {
 	// ManifestStore returns a store for local manifests
 	ManifestStore() store.Store
-	RegistryClient(bool) registryclient.RegistryClient
 }
 
 // newManifestStore returns a store for local manifests
@@ -48,73 +49,51 @@ func newManifestStore(dockerCLI command.Cli) store.Store {
 	return store.NewStore(filepath.Join(config.Dir(), "manifests"))
 }
 
-// newRegistryClient returns a client for communicating with a Docker distribution
-// registry
-func newRegistryClient(dockerCLI command.Cli, allowInsecure bool) registryclient.RegistryClient {
-	if msp, ok := dockerCLI.(manifestStoreProvider); ok {
-		// manifestStoreProvider is used in tests to provide a dummy store.
-		return msp.RegistryClient(allowInsecure)
-	}
-	cfg := dockerCLI.ConfigFile()
-	resolver := func(ctx context.Context, domainName string) registry.AuthConfig {
-		a, _ := cfg.GetAuthConfig(domainName)
-		return registry.AuthConfig{
-			Username:      a.Username,
-			Password:      a.Password,
-			ServerAddress: a.ServerAddress,
-
-			// TODO(thaJeztah): Are these expected to be included?
-			Auth:          a.Auth,
-			IdentityToken: a.IdentityToken,
-			RegistryToken: a.RegistryToken,
-		}
-	}
-	// FIXME(thaJeztah): this should use the userAgent as configured on the dockerCLI.
-	return registryclient.NewRegistryClient(resolver, command.UserAgent(), allowInsecure)
-}
-
 // NewAnnotateCommand creates a new `docker manifest annotate` command
 func newAnnotateCommand(dockerCLI command.Cli) *cobra.Command {
-	var opts annotateOptions
+	options := annotateOptions{
+		annotations: opts.NewListOpts(opts.ValidateLabel),
+	}
 
 	cmd := &cobra.Command{
 		Use:   "annotate [OPTIONS] MANIFEST_LIST MANIFEST",
 		Short: "Add additional information to a local image manifest",
 		Args:  cli.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			opts.target = args[0]
-			opts.image = args[1]
-			return runManifestAnnotate(dockerCLI, opts)
+			options.target = args[0]
+			options.image = args[1]
+			return runManifestAnnotate(dockerCLI, options)
 		},
 		DisableFlagsInUseLine: true,
 	}
 
 	flags := cmd.Flags()
 
-	flags.StringVar(&opts.os, "os", "", "Set operating system")
-	flags.StringVar(&opts.arch, "arch", "", "Set architecture")
-	flags.StringVar(&opts.osVersion, "os-version", "", "Set operating system version")
-	flags.StringSliceVar(&opts.osFeatures, "os-features", []string{}, "Set operating system feature")
-	flags.StringVar(&opts.variant, "variant", "", "Set architecture variant")
+	flags.StringVar(&options.os, "os", "", "Set operating system")
+	flags.StringVar(&options.arch, "arch", "", "Set architecture")
+	flags.StringVar(&options.osVersion, "os-version", "", "Set operating system version")
+	flags.StringSliceVar(&options.osFeatures, "os-features", []string{}, "Set operating system feature")
+	flags.StringVar(&options.variant, "variant", "", "Set architecture variant")
+	flags.Var(&options.annotations, "annotation", "Add an annotation to the manifest")
 
 	return cmd
 }
 
-func runManifestAnnotate(dockerCLI command.Cli, opts annotateOptions) error {
-	targetRef, err := normalizeReference(opts.target)
+func runManifestAnnotate(dockerCLI command.Cli, options annotateOptions) error {
+	targetRef, err := normalizeReference(options.target)
 	if err != nil {
-		return fmt.Errorf("annotate: error parsing name for manifest list %s: %w", opts.target, err)
+		return fmt.Errorf("annotate: error parsing name for manifest list %s: %w", options.target, err)
 	}
-	imgRef, err := normalizeReference(opts.image)
+	imgRef, err := normalizeReference(options.image)
 	if err != nil {
-		return fmt.Errorf("annotate: error parsing name for manifest %s: %w", opts.image, err)
+		return fmt.Errorf("annotate: error parsing name for manifest %s: %w", options.image, err)
 	}
 
 	manifestStore := newManifestStore(dockerCLI)
 	imageManifest, err := manifestStore.Get(targetRef, imgRef)
 	switch {
 	case errdefs.IsNotFound(err):
-		return fmt.Errorf("manifest for image %s does not exist in %s", opts.image, opts.target)
+		return fmt.Errorf("manifest for image %s does not exist in %s", options.image, options.target)
 	case err != nil:
 		return err
 	}
@@ -123,24 +102,38 @@ func runManifestAnnotate(dockerCLI command.Cli, opts annotateOptions) error {
 	if imageManifest.Descriptor.Platform == nil {
 		imageManifest.Descriptor.Platform = new(ocispec.Platform)
 	}
-	if opts.os != "" {
-		imageManifest.Descriptor.Platform.OS = opts.os
+	if options.os != "" {
+		imageManifest.Descriptor.Platform.OS = options.os
 	}
-	if opts.arch != "" {
-		imageManifest.Descriptor.Platform.Architecture = opts.arch
+	if options.arch != "" {
+		imageManifest.Descriptor.Platform.Architecture = options.arch
 	}
-	for _, osFeature := range opts.osFeatures {
+	for _, osFeature := range options.osFeatures {
 		imageManifest.Descriptor.Platform.OSFeatures = appendIfUnique(imageManifest.Descriptor.Platform.OSFeatures, osFeature)
 	}
-	if opts.variant != "" {
-		imageManifest.Descriptor.Platform.Variant = opts.variant
+	if options.variant != "" {
+		imageManifest.Descriptor.Platform.Variant = options.variant
+	}
+	if options.osVersion != "" {
+		imageManifest.Descriptor.Platform.OSVersion = options.osVersion
 	}
-	if opts.osVersion != "" {
-		imageManifest.Descriptor.Platform.OSVersion = opts.osVersion
+
+	if annotations := options.annotations.GetSlice(); len(annotations) > 0 {
+		metadata, err := manifestStore.GetListMetadata(targetRef)
+		if err != nil {
+			return err
+		}
+		if metadata.MediaType == manifestlist.MediaTypeManifestList {
+			return errors.New("annotations are only supported by OCI image indexes")
+		}
+		if imageManifest.Descriptor.Annotations == nil {
+			imageManifest.Descriptor.Annotations = make(map[string]string, len(annotations))
+		}
+		maps.Copy(imageManifest.Descriptor.Annotations, opts.ConvertKVStringsToMap(annotations))
 	}
 
 	if !isValidOSArch(imageManifest.Descriptor.Platform.OS, imageManifest.Descriptor.Platform.Architecture) {
-		return fmt.Errorf("manifest entry for image has unsupported os/arch combination: %s/%s", opts.os, opts.arch)
+		return fmt.Errorf("manifest entry for image has unsupported os/arch combination: %s/%s", options.os, options.arch)
 	}
 	return manifestStore.Save(targetRef, imgRef, imageManifest)
 }
@@ -5,7 +5,9 @@
 	"testing"
 
 	"github.com/docker/cli/cli/manifest/store"
+	"github.com/docker/cli/cli/manifest/types"
 	"github.com/docker/cli/internal/test"
+	"github.com/docker/distribution/manifest/manifestlist"
 	"gotest.tools/v3/assert"
 	is "gotest.tools/v3/assert/cmp"
 	"gotest.tools/v3/golden"
@@ -79,3 +81,44 @@ func TestManifestAnnotate(t *testing.T) {
 	expected := golden.Get(t, "inspect-annotate.golden")
 	assert.Check(t, is.Equal(string(expected), actual.String()))
 }
+
+func TestManifestAnnotateAnnotations(t *testing.T) {
+	manifestStore := store.NewStore(t.TempDir())
+
+	cli := test.NewFakeCli(nil)
+	cli.SetManifestStore(manifestStore)
+	namedRef := ref(t, "alpine:3.0")
+	imageManifest := fullImageManifest(t, namedRef)
+	err := manifestStore.Save(ref(t, "list:v1"), namedRef, imageManifest)
+	assert.NilError(t, err)
+
+	cmd := newAnnotateCommand(cli)
+	cmd.SetArgs([]string{"--annotation", "org.opencontainers.image.title=alpine", "example.com/list:v1", "example.com/alpine:3.0"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.NilError(t, cmd.Execute())
+
+	// The annotations of the manifests make the list an OCI image index.
+	cmd = newInspectCommand(cli)
+	cmd.SetArgs([]string{"example.com/list:v1"})
+	assert.NilError(t, cmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "inspect-manifest-list-annotated.golden")
+}
+
+func TestManifestAnnotateAnnotationsDockerFormat(t *testing.T) {
+	manifestStore := store.NewStore(t.TempDir())
+
+	cli := test.NewFakeCli(nil)
+	cli.SetManifestStore(manifestStore)
+	namedRef := ref(t, "alpine:3.0")
+	err := manifestStore.Save(ref(t, "list:v1"), namedRef, fullImageManifest(t, namedRef))
+	assert.NilError(t, err)
+	err = manifestStore.SaveListMetadata(ref(t, "list:v1"), types.ListMetadata{MediaType: manifestlist.MediaTypeManifestList})
+	assert.NilError(t, err)
+
+	cmd := newAnnotateCommand(cli)
+	cmd.SetArgs([]string{"--annotation", "foo=bar", "example.com/list:v1", "example.com/alpine:3.0"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Error(t, cmd.Execute(), "annotations are only supported by OCI image indexes")
+}
@@ -13,7 +13,10 @@
 type fakeRegistryClient struct {
 	getManifestFunc     func(ctx context.Context, ref reference.Named) (manifesttypes.ImageManifest, error)
 	getManifestListFunc func(ctx context.Context, ref reference.Named) ([]manifesttypes.ImageManifest, error)
+	getRawManifestFunc  func(ctx context.Context, ref reference.Named) (distribution.Manifest, error)
+	getBlobFunc         func(ctx context.Context, ref reference.Canonical) ([]byte, error)
 	mountBlobFunc       func(ctx context.Context, source reference.Canonical, target reference.Named) error
+	copyBlobFunc        func(ctx context.Context, source reference.Canonical, target reference.Named) error
 	putManifestFunc     func(ctx context.Context, source reference.Named, mf distribution.Manifest) (digest.Digest, error)
 }
 
@@ -31,6 +34,28 @@ func (c *fakeRegistryClient) GetManifestList(ctx context.Context, ref reference.
 	return nil, nil
 }
 
+func (c *fakeRegistryClient) GetRawManifest(ctx context.Context, ref reference.Named) (distribution.Manifest, error) {
+	if c.getRawManifestFunc != nil {
+		return c.getRawManifestFunc(ctx, ref)
+	}
+	return nil, nil
+}
+
+func (c *fakeRegistryClient) GetBlob(ctx context.Context, ref reference.Canonical) ([]byte, error) {
+	if c.getBlobFunc != nil {
+		return c.getBlobFunc(ctx, ref)
+	}
+	return nil, nil
+}
+
+func (*fakeRegistryClient) ListTags(context.Context, reference.Named) ([]string, error) {
+	return nil, nil
+}
+
+func (*fakeRegistryClient) ListRepositories(context.Context, string) ([]string, error) {
+	return nil, nil
+}
+
 func (c *fakeRegistryClient) MountBlob(ctx context.Context, source reference.Canonical, target reference.Named) error {
 	if c.mountBlobFunc != nil {
 		return c.mountBlobFunc(ctx, source, target)
@@ -38,6 +63,13 @@ func (c *fakeRegistryClient) MountBlob(ctx context.Context, source reference.Can
 	return nil
 }
 
+func (c *fakeRegistryClient) CopyBlob(ctx context.Context, source reference.Canonical, target reference.Named) error {
+	if c.copyBlobFunc != nil {
+		return c.copyBlobFunc(ctx, source, target)
+	}
+	return nil
+}
+
 func (c *fakeRegistryClient) PutManifest(ctx context.Context, ref reference.Named, mf distribution.Manifest) (digest.Digest, error) {
 	if c.putManifestFunc != nil {
 		return c.putManifestFunc(ctx, ref, mf)
@@ -45,4 +77,8 @@ func (c *fakeRegistryClient) PutManifest(ctx context.Context, ref reference.Name
 	return digest.Digest(""), nil
 }
 
+func (*fakeRegistryClient) DeleteManifest(context.Context, reference.Canonical) error {
+	return nil
+}
+
 var _ registryclient.RegistryClient = &fakeRegistryClient{}
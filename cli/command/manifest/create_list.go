@@ -1,47 +1,62 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
 package manifest
 
 import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 
 	"github.com/containerd/errdefs"
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/opts"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/spf13/cobra"
 )
 
 type createOpts struct {
-	amend    bool
-	insecure bool
+	amend       bool
+	insecure    bool
+	format      string
+	annotations opts.ListOpts
 }
 
 func newCreateListCommand(dockerCLI command.Cli) *cobra.Command {
-	opts := createOpts{}
+	options := createOpts{annotations: opts.NewListOpts(opts.ValidateLabel)}
 
 	cmd := &cobra.Command{
 		Use:   "create MANIFEST_LIST MANIFEST [MANIFEST...]",
 		Short: "Create a local manifest list for annotating and pushing to a registry",
 		Args:  cli.RequiresMinArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return createManifestList(cmd.Context(), dockerCLI, args, opts)
+			return createManifestList(cmd.Context(), dockerCLI, args, options)
 		},
 		DisableFlagsInUseLine: true,
 	}
 
 	flags := cmd.Flags()
-	flags.BoolVar(&opts.insecure, "insecure", false, "Allow communication with an insecure registry")
-	flags.BoolVarP(&opts.amend, "amend", "a", false, "Amend an existing manifest list")
+	flags.BoolVar(&options.insecure, "insecure", false, "Allow communication with an insecure registry")
+	flags.BoolVarP(&options.amend, "amend", "a", false, "Amend an existing manifest list")
+	flags.StringVar(&options.format, "format", "", `Format of the manifest list ("docker", "oci") (default based on the manifests)`)
+	flags.Var(&options.annotations, "annotation", "Add an annotation to the manifest list (requires --format=oci)")
 	return cmd
 }
 
-func createManifestList(ctx context.Context, dockerCLI command.Cli, args []string, opts createOpts) error {
+func createManifestList(ctx context.Context, dockerCLI command.Cli, args []string, options createOpts) error {
 	newRef := args[0]
 	targetRef, err := normalizeReference(newRef)
 	if err != nil {
 		return fmt.Errorf("error parsing name for manifest list %s: %w", newRef, err)
 	}
 
+	mediaType, err := mediaTypeForFormat(options.format)
+	if err != nil {
+		return err
+	}
+
 	manifestStore := newManifestStore(dockerCLI)
 	_, err = manifestStore.GetList(targetRef)
 	switch {
@@ -49,10 +64,27 @@ func createManifestList(ctx context.Context, dockerCLI command.Cli, args []strin
 		// New manifest list
 	case err != nil:
 		return err
-	case !opts.amend:
+	case !options.amend:
 		return errors.New("refusing to amend an existing manifest list with no --amend flag")
 	}
 
+	metadata, err := manifestStore.GetListMetadata(targetRef)
+	if err != nil {
+		return err
+	}
+	if mediaType != "" {
+		metadata.MediaType = mediaType
+	}
+	if options.annotations.Len() > 0 {
+		if metadata.MediaType != ocispec.MediaTypeImageIndex {
+			return errors.New("annotations are only supported by OCI image indexes (--format=oci)")
+		}
+		if metadata.Annotations == nil {
+			metadata.Annotations = make(map[string]string)
+		}
+		maps.Copy(metadata.Annotations, opts.ConvertKVStringsToMap(options.annotations.GetSlice()))
+	}
+
 	// Now create the local manifest list transaction by looking up the manifest schemas
 	// for the constituent images:
 	manifests := args[1:]
@@ -63,7 +95,7 @@ func createManifestList(ctx context.Context, dockerCLI command.Cli, args []strin
 			return err
 		}
 
-		manifest, err := getManifest(ctx, dockerCLI, targetRef, namedRef, opts.insecure)
+		manifest, err := getManifest(ctx, dockerCLI, targetRef, namedRef, options.insecure)
 		if err != nil {
 			return err
 		}
@@ -71,6 +103,9 @@ func createManifestList(ctx context.Context, dockerCLI command.Cli, args []strin
 			return err
 		}
 	}
+	if err := manifestStore.SaveListMetadata(targetRef, metadata); err != nil {
+		return err
+	}
 	_, _ = fmt.Fprintln(dockerCLI.Out(), "Created manifest list", targetRef.String())
 	return nil
 }
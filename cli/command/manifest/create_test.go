@@ -117,3 +117,63 @@ func TestManifestCreateNoManifest(t *testing.T) {
 	err := cmd.Execute()
 	assert.Error(t, err, "No such image: example.com/alpine:3.0")
 }
+
+func TestManifestCreateOCIFormat(t *testing.T) {
+	manifestStore := store.NewStore(t.TempDir())
+
+	cli := test.NewFakeCli(nil)
+	cli.SetManifestStore(manifestStore)
+	cli.SetRegistryClient(&fakeRegistryClient{
+		getManifestFunc: func(_ context.Context, ref reference.Named) (manifesttypes.ImageManifest, error) {
+			return fullImageManifest(t, ref), nil
+		},
+	})
+
+	cmd := newCreateListCommand(cli)
+	cmd.SetArgs([]string{
+		"--format", "oci",
+		"--annotation", "org.opencontainers.image.version=1.0",
+		"example.com/list:v1", "example.com/alpine:3.0",
+	})
+	cmd.SetOut(io.Discard)
+	assert.NilError(t, cmd.Execute())
+
+	cli = test.NewFakeCli(nil)
+	cli.SetManifestStore(manifestStore)
+	inspectCmd := newInspectCommand(cli)
+	inspectCmd.SetArgs([]string{"example.com/list:v1"})
+	assert.NilError(t, inspectCmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "inspect-manifest-list-oci.golden")
+}
+
+func TestManifestCreateFormatErrors(t *testing.T) {
+	testCases := []struct {
+		args          []string
+		expectedError string
+	}{
+		{
+			args:          []string{"--format", "foo", "example.com/list:v1", "example.com/alpine:3.0"},
+			expectedError: `invalid format "foo": only docker and oci are supported`,
+		},
+		{
+			args:          []string{"--annotation", "foo=bar", "example.com/list:v1", "example.com/alpine:3.0"},
+			expectedError: "annotations are only supported by OCI image indexes (--format=oci)",
+		},
+		{
+			args:          []string{"--format", "docker", "--annotation", "foo=bar", "example.com/list:v1", "example.com/alpine:3.0"},
+			expectedError: "annotations are only supported by OCI image indexes (--format=oci)",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expectedError, func(t *testing.T) {
+			cli := test.NewFakeCli(nil)
+			cli.SetManifestStore(store.NewStore(t.TempDir()))
+			cmd := newCreateListCommand(cli)
+			cmd.SetArgs(tc.args)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			assert.Error(t, cmd.Execute(), tc.expectedError)
+		})
+	}
+}
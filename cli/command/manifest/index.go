@@ -0,0 +1,129 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli/manifest/types"
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// Formats of manifest lists supported by "docker manifest create".
+const (
+	formatDocker = "docker"
+	formatOCI    = "oci"
+)
+
+// mediaTypeForFormat returns the media type of a manifest list in the given
+// format, or an empty string for the default format.
+func mediaTypeForFormat(format string) (string, error) {
+	switch format {
+	case "":
+		return "", nil
+	case formatDocker:
+		return manifestlist.MediaTypeManifestList, nil
+	case formatOCI:
+		return ocispec.MediaTypeImageIndex, nil
+	default:
+		return "", fmt.Errorf("invalid format %q: only %s and %s are supported", format, formatDocker, formatOCI)
+	}
+}
+
+// ociIndex is an OCI image index. Unlike [manifestlist.DeserializedManifestList],
+// it preserves the annotations of the index and its descriptors.
+type ociIndex struct {
+	ocispec.Index
+
+	// canonical is the canonical byte representation of the index.
+	canonical []byte
+}
+
+// newOCIIndex returns an OCI image index for the given descriptors.
+func newOCIIndex(descriptors []ocispec.Descriptor, annotations map[string]string) (*ociIndex, error) {
+	idx := ocispec.Index{
+		Versioned:   specs.Versioned{SchemaVersion: 2},
+		MediaType:   ocispec.MediaTypeImageIndex,
+		Manifests:   descriptors,
+		Annotations: annotations,
+	}
+	canonical, err := json.MarshalIndent(&idx, "", "   ")
+	if err != nil {
+		return nil, err
+	}
+	return &ociIndex{Index: idx, canonical: canonical}, nil
+}
+
+// References implements the [distribution.Manifest] interface.
+func (i *ociIndex) References() []distribution.Descriptor {
+	refs := make([]distribution.Descriptor, 0, len(i.Manifests))
+	for _, m := range i.Manifests {
+		refs = append(refs, distribution.Descriptor{
+			MediaType:   m.MediaType,
+			Size:        m.Size,
+			Digest:      m.Digest,
+			URLs:        m.URLs,
+			Annotations: m.Annotations,
+			Platform:    m.Platform,
+		})
+	}
+	return refs
+}
+
+// Payload implements the [distribution.Manifest] interface.
+func (i *ociIndex) Payload() (string, []byte, error) {
+	return ocispec.MediaTypeImageIndex, i.canonical, nil
+}
+
+// buildIndex returns the manifest list for the given manifests. It is an OCI
+// image index if the metadata of the list specifies so, or if the media type
+// of the list isn't specified and the manifests are OCI image manifests, or
+// have annotations or an artifact type that only an OCI image index can hold.
+func buildIndex(targetRepo reference.Named, manifests []types.ImageManifest, metadata types.ListMetadata) (distribution.Manifest, error) {
+	descriptors := make([]manifestlist.ManifestDescriptor, 0, len(manifests))
+	for _, img := range manifests {
+		mfd, err := buildManifestDescriptor(targetRepo, img)
+		if err != nil {
+			return nil, fmt.Errorf("failed to assemble ManifestDescriptor: %w", err)
+		}
+		descriptors = append(descriptors, mfd)
+	}
+
+	mediaType := metadata.MediaType
+	if mediaType == "" && slices.ContainsFunc(manifests, needsOCIIndex) {
+		mediaType = ocispec.MediaTypeImageIndex
+	}
+
+	switch mediaType {
+	case ocispec.MediaTypeImageIndex:
+		ociDescriptors := make([]ocispec.Descriptor, 0, len(manifests))
+		for i, img := range manifests {
+			ociDescriptors = append(ociDescriptors, ocispec.Descriptor{
+				MediaType:    descriptors[i].MediaType,
+				Digest:       descriptors[i].Digest,
+				Size:         descriptors[i].Size,
+				Annotations:  img.Descriptor.Annotations,
+				Platform:     img.Descriptor.Platform,
+				ArtifactType: img.Descriptor.ArtifactType,
+			})
+		}
+		return newOCIIndex(ociDescriptors, metadata.Annotations)
+	case manifestlist.MediaTypeManifestList:
+		return manifestlist.FromDescriptorsWithMediaType(descriptors, manifestlist.MediaTypeManifestList)
+	default:
+		return manifestlist.FromDescriptors(descriptors)
+	}
+}
+
+// needsOCIIndex returns whether the manifest has properties that are only
+// preserved in an OCI image index.
+func needsOCIIndex(img types.ImageManifest) bool {
+	return len(img.Descriptor.Annotations) > 0 || img.Descriptor.ArtifactType != ""
+}
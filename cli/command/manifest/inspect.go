@@ -10,7 +10,6 @@
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/manifest/types"
-	"github.com/docker/distribution/manifest/manifestlist"
 	"github.com/spf13/cobra"
 )
 
@@ -69,13 +68,18 @@ func runInspect(ctx context.Context, dockerCli command.Cli, opts inspectOptions)
 	}
 
 	// Try a local manifest list first
-	localManifestList, err := newManifestStore(dockerCli).GetList(namedRef)
+	manifestStore := newManifestStore(dockerCli)
+	localManifestList, err := manifestStore.GetList(namedRef)
 	if err == nil {
-		return printManifestList(dockerCli, namedRef, localManifestList, opts)
+		metadata, err := manifestStore.GetListMetadata(namedRef)
+		if err != nil {
+			return err
+		}
+		return printManifestList(dockerCli, namedRef, localManifestList, metadata, opts)
 	}
 
 	// Next try a remote manifest
-	registryClient := newRegistryClient(dockerCli, opts.insecure)
+	registryClient := command.NewRegistryClient(dockerCli, opts.insecure)
 	imageManifest, err := registryClient.GetManifest(ctx, namedRef)
 	if err == nil {
 		return printManifest(dockerCli, imageManifest, opts)
@@ -86,7 +90,7 @@ func runInspect(ctx context.Context, dockerCli command.Cli, opts inspectOptions)
 	if err != nil {
 		return err
 	}
-	return printManifestList(dockerCli, namedRef, manifestList, opts)
+	return printManifestList(dockerCli, namedRef, manifestList, types.ListMetadata{}, opts)
 }
 
 func printManifest(dockerCli command.Cli, manifest types.ImageManifest, opts inspectOptions) error {
@@ -110,31 +114,23 @@ func printManifest(dockerCli command.Cli, manifest types.ImageManifest, opts ins
 	return nil
 }
 
-func printManifestList(dockerCli command.Cli, namedRef reference.Named, list []types.ImageManifest, opts inspectOptions) error {
+func printManifestList(dockerCli command.Cli, namedRef reference.Named, list []types.ImageManifest, metadata types.ListMetadata, opts inspectOptions) error {
 	if !opts.verbose {
-		targetRepo := reference.TrimNamed(namedRef)
-
-		manifests := make([]manifestlist.ManifestDescriptor, 0, len(list))
 		// More than one response. This is a manifest list.
-		for _, img := range list {
-			mfd, err := buildManifestDescriptor(targetRepo, img)
-			if err != nil {
-				return fmt.Errorf("failed to assemble ManifestDescriptor: %w", err)
-			}
-			manifests = append(manifests, mfd)
-		}
-		deserializedML, err := manifestlist.FromDescriptors(manifests)
+		index, err := buildIndex(reference.TrimNamed(namedRef), list, metadata)
 		if err != nil {
 			return err
 		}
-		jsonBytes, err := deserializedML.MarshalJSON()
+		_, jsonBytes, err := index.Payload()
 		if err != nil {
 			return err
 		}
 		_, _ = fmt.Fprintln(dockerCli.Out(), string(jsonBytes))
 		return nil
 	}
-	jsonBytes, err := json.MarshalIndent(list, "", "\t")
+	// Show attestations under the image they describe, instead of as
+	// separate (unknown/unknown) platform images.
+	jsonBytes, err := json.MarshalIndent(types.GroupAttestations(list), "", "\t")
 	if err != nil {
 		return err
 	}
@@ -135,3 +135,38 @@ func TestInspectcommandRemoteManifest(t *testing.T) {
 	expected := golden.Get(t, "inspect-manifest.golden")
 	assert.Check(t, is.Equal(string(expected), actual.String()))
 }
+
+func TestInspectCommandRemoteManifestListAttestations(t *testing.T) {
+	refStore := store.NewStore(t.TempDir())
+
+	cli := test.NewFakeCli(nil)
+	cli.SetManifestStore(refStore)
+	cli.SetRegistryClient(&fakeRegistryClient{
+		getManifestFunc: func(_ context.Context, ref reference.Named) (types.ImageManifest, error) {
+			return types.ImageManifest{}, errors.New("example.com/alpine:3.0 is a manifest list")
+		},
+		getManifestListFunc: func(_ context.Context, ref reference.Named) ([]types.ImageManifest, error) {
+			img := fullImageManifest(t, ref)
+			attestation := types.ImageManifest{
+				Ref: img.Ref,
+				Descriptor: ocispec.Descriptor{
+					MediaType: ocispec.MediaTypeImageManifest,
+					Digest:    "sha256:ba37d84ab09b9bf7fcfd8ae5f4b3ffdb3a1ab65a4f1ad2b6b6df0d2e7b4f5a3c",
+					Size:      839,
+					Platform:  &ocispec.Platform{Architecture: "unknown", OS: "unknown"},
+					Annotations: map[string]string{
+						types.AnnotationReferenceType:   types.ReferenceTypeAttestation,
+						types.AnnotationReferenceDigest: img.Descriptor.Digest.String(),
+					},
+				},
+			}
+			return []types.ImageManifest{img, attestation}, nil
+		},
+	})
+
+	cmd := newInspectCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{"--verbose", "example.com/alpine:3.0"})
+	assert.NilError(t, cmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "inspect-manifest-list-attestations.golden")
+}
@@ -1,3 +1,6 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
 package manifest
 
 import (
@@ -5,6 +8,7 @@
 	"encoding/json"
 	"fmt"
 	"io"
+	"slices"
 
 	"github.com/distribution/reference"
 	"github.com/docker/cli/cli"
@@ -15,6 +19,7 @@
 	"github.com/docker/distribution/manifest/manifestlist"
 	"github.com/docker/distribution/manifest/ocischema"
 	"github.com/docker/distribution/manifest/schema2"
+	"github.com/opencontainers/go-digest"
 	"github.com/spf13/cobra"
 )
 
@@ -36,7 +41,7 @@ type manifestBlob struct {
 
 type pushRequest struct {
 	targetRef     reference.Named
-	list          *manifestlist.DeserializedManifestList
+	list          distribution.Manifest
 	mountRequests []mountRequest
 	manifestBlobs []manifestBlob
 	insecure      bool
@@ -68,15 +73,20 @@ func runPush(ctx context.Context, dockerCli command.Cli, opts pushOpts) error {
 		return err
 	}
 
-	manifests, err := newManifestStore(dockerCli).GetList(targetRef)
+	manifestStore := newManifestStore(dockerCli)
+	manifests, err := manifestStore.GetList(targetRef)
 	if err != nil {
 		return err
 	}
 	if len(manifests) == 0 {
 		return fmt.Errorf("%s not found", targetRef)
 	}
+	metadata, err := manifestStore.GetListMetadata(targetRef)
+	if err != nil {
+		return err
+	}
 
-	req, err := buildPushRequest(manifests, targetRef, opts.insecure)
+	req, err := buildPushRequest(manifests, metadata, targetRef, opts.insecure)
 	if err != nil {
 		return err
 	}
@@ -90,11 +100,11 @@ func runPush(ctx context.Context, dockerCli command.Cli, opts pushOpts) error {
 	return nil
 }
 
-func buildPushRequest(manifests []types.ImageManifest, targetRef reference.Named, insecure bool) (pushRequest, error) {
+func buildPushRequest(manifests []types.ImageManifest, metadata types.ListMetadata, targetRef reference.Named, insecure bool) (pushRequest, error) {
 	req := pushRequest{targetRef: targetRef, insecure: insecure}
 
 	var err error
-	req.list, err = buildManifestList(manifests, targetRef)
+	req.list, err = buildManifestList(manifests, metadata, targetRef)
 	if err != nil {
 		return req, err
 	}
@@ -121,23 +131,15 @@ func buildPushRequest(manifests []types.ImageManifest, targetRef reference.Named
 	return req, nil
 }
 
-func buildManifestList(manifests []types.ImageManifest, targetRef reference.Named) (*manifestlist.DeserializedManifestList, error) {
-	targetRepo := reference.TrimNamed(targetRef)
-	descriptors := make([]manifestlist.ManifestDescriptor, 0, len(manifests))
+func buildManifestList(manifests []types.ImageManifest, metadata types.ListMetadata, targetRef reference.Named) (distribution.Manifest, error) {
 	for _, imageManifest := range manifests {
 		if imageManifest.Descriptor.Platform == nil ||
 			imageManifest.Descriptor.Platform.Architecture == "" ||
 			imageManifest.Descriptor.Platform.OS == "" {
 			return nil, fmt.Errorf("manifest %s must have an OS and Architecture to be pushed to a registry", imageManifest.Ref)
 		}
-		descriptor, err := buildManifestDescriptor(targetRepo, imageManifest)
-		if err != nil {
-			return nil, err
-		}
-		descriptors = append(descriptors, descriptor)
 	}
-
-	return manifestlist.FromDescriptors(descriptors)
+	return buildIndex(reference.TrimNamed(targetRef), manifests, metadata)
 }
 
 func buildManifestDescriptor(targetRepo reference.Named, imageManifest types.ImageManifest) (manifestlist.ManifestDescriptor, error) {
@@ -149,9 +151,10 @@ func buildManifestDescriptor(targetRepo reference.Named, imageManifest types.Ima
 
 	manifest := manifestlist.ManifestDescriptor{
 		Descriptor: distribution.Descriptor{
-			Digest:    imageManifest.Descriptor.Digest,
-			Size:      imageManifest.Descriptor.Size,
-			MediaType: imageManifest.Descriptor.MediaType,
+			Digest:      imageManifest.Descriptor.Digest,
+			Size:        imageManifest.Descriptor.Size,
+			MediaType:   imageManifest.Descriptor.MediaType,
+			Annotations: imageManifest.Descriptor.Annotations,
 		},
 	}
 
@@ -246,7 +249,7 @@ func buildPutManifestRequest(imageManifest types.ImageManifest, targetRef refere
 }
 
 func pushList(ctx context.Context, dockerCLI command.Cli, req pushRequest) error {
-	registryClient := newRegistryClient(dockerCLI, req.insecure)
+	registryClient := command.NewRegistryClient(dockerCLI, req.insecure)
 
 	if err := mountBlobs(ctx, registryClient, req.targetRef, req.manifestBlobs); err != nil {
 		return err
@@ -274,18 +277,68 @@ func pushReferences(ctx context.Context, out io.Writer, client registryclient.Re
 	return nil
 }
 
+// mountBlobs makes the blobs of the manifests available in the target
+// repository. Blobs are mounted from the repository of their manifest, or
+// any of the other source repositories, which are all on the same registry
+// as the target repository. Blobs that can't be mounted are copied.
 func mountBlobs(ctx context.Context, client registryclient.RegistryClient, ref reference.Named, blobs []manifestBlob) error {
+	var sourceRepos []reference.Named
 	for _, blob := range blobs {
-		err := client.MountBlob(ctx, blob.canonical, ref)
+		repo := reference.TrimNamed(blob.canonical)
+		if !slices.ContainsFunc(sourceRepos, func(r reference.Named) bool { return r.Name() == repo.Name() }) {
+			sourceRepos = append(sourceRepos, repo)
+		}
+	}
+
+	done := make(map[digest.Digest]bool)
+	for _, blob := range blobs {
+		if done[blob.canonical.Digest()] {
+			continue
+		}
+		mounted, err := mountBlob(ctx, client, ref, blob, sourceRepos)
+		if err != nil {
+			return err
+		}
+		if !mounted {
+			// Foreign layers of Windows images are not stored in the registry,
+			// so can't be mounted or copied.
+			if blob.os == "windows" {
+				continue
+			}
+			if err := client.CopyBlob(ctx, blob.canonical, ref); err != nil {
+				return fmt.Errorf("error mounting %s to %s: %w", blob.canonical, ref, err)
+			}
+		}
+		done[blob.canonical.Digest()] = true
+	}
+	return nil
+}
+
+// mountBlob mounts the blob from its own repository, or from one of the
+// other source repositories. It returns false if the blob could not be
+// mounted from any of the repositories.
+func mountBlob(ctx context.Context, client registryclient.RegistryClient, ref reference.Named, blob manifestBlob, sourceRepos []reference.Named) (bool, error) {
+	sources := []reference.Canonical{blob.canonical}
+	for _, repo := range sourceRepos {
+		if repo.Name() == blob.canonical.Name() {
+			continue
+		}
+		source, err := reference.WithDigest(repo, blob.canonical.Digest())
+		if err != nil {
+			return false, err
+		}
+		sources = append(sources, source)
+	}
+	for _, source := range sources {
+		err := client.MountBlob(ctx, source, ref)
 		switch err.(type) {
 		case nil:
+			return true, nil
 		case registryclient.ErrBlobCreated:
-			if blob.os != "windows" {
-				return fmt.Errorf("error mounting %s to %s", blob.canonical, ref)
-			}
+			// The registry did not mount the blob from this repository.
 		default:
-			return err
+			return false, err
 		}
 	}
-	return nil
+	return false, nil
 }
@@ -2,6 +2,7 @@
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
 	"testing"
@@ -9,8 +10,13 @@
 	"github.com/distribution/reference"
 	"github.com/docker/cli/cli/manifest/store"
 	manifesttypes "github.com/docker/cli/cli/manifest/types"
+	"github.com/docker/cli/internal/registryclient"
 	"github.com/docker/cli/internal/test"
+	"github.com/docker/distribution"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
 )
 
 func newFakeRegistryClient() *fakeRegistryClient {
@@ -68,3 +74,101 @@ func TestManifestPush(t *testing.T) {
 	err = cmd.Execute()
 	assert.NilError(t, err)
 }
+
+func TestManifestPushOCIIndex(t *testing.T) {
+	manifestStore := store.NewStore(t.TempDir())
+
+	var pushed distribution.Manifest
+	registry := newFakeRegistryClient()
+	registry.putManifestFunc = func(_ context.Context, ref reference.Named, mf distribution.Manifest) (digest.Digest, error) {
+		if _, ok := ref.(reference.Tagged); ok {
+			pushed = mf
+		}
+		return "", nil
+	}
+
+	cli := test.NewFakeCli(nil)
+	cli.SetManifestStore(manifestStore)
+	cli.SetRegistryClient(registry)
+
+	listRef := ref(t, "list:v1")
+	namedRef := ref(t, "alpine:3.0")
+	assert.NilError(t, manifestStore.Save(listRef, namedRef, fullImageManifest(t, namedRef)))
+	assert.NilError(t, manifestStore.SaveListMetadata(listRef, manifesttypes.ListMetadata{
+		MediaType:   ocispec.MediaTypeImageIndex,
+		Annotations: map[string]string{"org.opencontainers.image.version": "1.0"},
+	}))
+
+	cmd := newPushListCommand(cli)
+	cmd.SetArgs([]string{"example.com/list:v1"})
+	assert.NilError(t, cmd.Execute())
+
+	assert.Assert(t, pushed != nil)
+	mediaType, payload, err := pushed.Payload()
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(mediaType, ocispec.MediaTypeImageIndex))
+	var index ocispec.Index
+	assert.NilError(t, json.Unmarshal(payload, &index))
+	assert.Check(t, is.Equal(index.MediaType, ocispec.MediaTypeImageIndex))
+	assert.Check(t, is.DeepEqual(index.Annotations, map[string]string{"org.opencontainers.image.version": "1.0"}))
+	assert.Check(t, is.Len(index.Manifests, 1))
+}
+
+func TestMountBlobs(t *testing.T) {
+	target := ref(t, "target:latest")
+	blob := func(repo string) manifestBlob {
+		canonical, err := reference.WithDigest(ref(t, repo), "sha256:88286f41530e93dffd4b964e1db22ce4939fffa4a4c665dab8591fbab03d4926")
+		assert.NilError(t, err)
+		return manifestBlob{canonical: canonical, os: "linux"}
+	}
+
+	t.Run("mount from other source repository", func(t *testing.T) {
+		var mounted, copied []string
+		registry := &fakeRegistryClient{
+			mountBlobFunc: func(_ context.Context, source reference.Canonical, target reference.Named) error {
+				mounted = append(mounted, source.Name())
+				if source.Name() == "example.com/one" {
+					return registryclient.ErrBlobCreated{From: source, Target: target}
+				}
+				return nil
+			},
+			copyBlobFunc: func(_ context.Context, source reference.Canonical, _ reference.Named) error {
+				copied = append(copied, source.Name())
+				return nil
+			},
+		}
+		err := mountBlobs(context.Background(), registry, target, []manifestBlob{blob("one"), blob("two")})
+		assert.NilError(t, err)
+		assert.Check(t, is.DeepEqual(mounted, []string{"example.com/one", "example.com/two"}))
+		assert.Check(t, is.Len(copied, 0))
+	})
+
+	t.Run("copy if mounting fails", func(t *testing.T) {
+		var copied []string
+		registry := &fakeRegistryClient{
+			mountBlobFunc: func(_ context.Context, source reference.Canonical, target reference.Named) error {
+				return registryclient.ErrBlobCreated{From: source, Target: target}
+			},
+			copyBlobFunc: func(_ context.Context, source reference.Canonical, _ reference.Named) error {
+				copied = append(copied, source.Name())
+				return nil
+			},
+		}
+		err := mountBlobs(context.Background(), registry, target, []manifestBlob{blob("one"), blob("two")})
+		assert.NilError(t, err)
+		assert.Check(t, is.DeepEqual(copied, []string{"example.com/one"}))
+	})
+
+	t.Run("copy error", func(t *testing.T) {
+		registry := &fakeRegistryClient{
+			mountBlobFunc: func(_ context.Context, source reference.Canonical, target reference.Named) error {
+				return registryclient.ErrBlobCreated{From: source, Target: target}
+			},
+			copyBlobFunc: func(context.Context, reference.Canonical, reference.Named) error {
+				return errors.New("blob unknown")
+			},
+		}
+		err := mountBlobs(context.Background(), registry, target, []manifestBlob{blob("one")})
+		assert.Check(t, is.ErrorContains(err, "blob unknown"))
+	})
+}
@@ -73,11 +73,11 @@ func getManifest(ctx context.Context, dockerCLI command.Cli, listRef, namedRef r
 	data, err := newManifestStore(dockerCLI).Get(listRef, namedRef)
 	switch {
 	case errdefs.IsNotFound(err):
-		return newRegistryClient(dockerCLI, insecure).GetManifest(ctx, namedRef)
+		return command.NewRegistryClient(dockerCLI, insecure).GetManifest(ctx, namedRef)
 	case err != nil:
 		return types.ImageManifest{}, err
 	case len(data.Raw) == 0:
-		return newRegistryClient(dockerCLI, insecure).GetManifest(ctx, namedRef)
+		return command.NewRegistryClient(dockerCLI, insecure).GetManifest(ctx, namedRef)
 	default:
 		return data, nil
 	}
@@ -0,0 +1,105 @@
+package plugincli
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/internal/registryclient"
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/ocischema"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+)
+
+type fakeRegistryClient struct {
+	registryclient.RegistryClient
+	manifests map[string]distribution.Manifest
+	blobs     map[digest.Digest][]byte
+	blobPulls int
+}
+
+func (c *fakeRegistryClient) GetRawManifest(_ context.Context, ref reference.Named) (distribution.Manifest, error) {
+	if m, ok := c.manifests[ref.String()]; ok {
+		return m, nil
+	}
+	return nil, fmt.Errorf("no such manifest: %s", ref)
+}
+
+func (c *fakeRegistryClient) GetBlob(_ context.Context, ref reference.Canonical) ([]byte, error) {
+	c.blobPulls++
+	if b, ok := c.blobs[ref.Digest()]; ok {
+		return b, nil
+	}
+	return nil, fmt.Errorf("no such blob: %s", ref)
+}
+
+// addPlugin adds a plugin artifact for the current platform to the client,
+// as a manifest list with the given tag, and returns the digest of the
+// manifest list.
+func (c *fakeRegistryClient) addPlugin(t *testing.T, repo, tag, title, version string, binary []byte) digest.Digest {
+	t.Helper()
+	if c.manifests == nil {
+		c.manifests = map[string]distribution.Manifest{}
+		c.blobs = map[digest.Digest][]byte{}
+	}
+	layer := distribution.Descriptor{
+		MediaType: mediaTypePluginBinary,
+		Digest:    digest.FromBytes(binary),
+		Size:      int64(len(binary)),
+	}
+	if title != "" {
+		layer.Annotations = map[string]string{ocispec.AnnotationTitle: title}
+	}
+	m, err := ocischema.FromStruct(ocischema.Manifest{
+		Versioned: ocischema.SchemaVersion,
+		Config: distribution.Descriptor{
+			MediaType: "application/vnd.oci.empty.v1+json",
+			Digest:    digest.FromString("{}"),
+			Size:      2,
+		},
+		Layers:      []distribution.Descriptor{layer},
+		Annotations: map[string]string{ocispec.AnnotationVersion: version},
+	})
+	assert.NilError(t, err)
+	mediaType, payload, err := m.Payload()
+	assert.NilError(t, err)
+	mDigest := digest.FromBytes(payload)
+
+	list, err := manifestlist.FromDescriptorsWithMediaType([]manifestlist.ManifestDescriptor{{
+		Descriptor: distribution.Descriptor{MediaType: mediaType, Digest: mDigest, Size: int64(len(payload))},
+		Platform:   manifestlist.PlatformSpec{OS: runtime.GOOS, Architecture: runtime.GOARCH},
+	}}, ocispec.MediaTypeImageIndex)
+	assert.NilError(t, err)
+	_, listPayload, err := list.Payload()
+	assert.NilError(t, err)
+	listDigest := digest.FromBytes(listPayload)
+
+	c.manifests[repo+":"+tag] = list
+	c.manifests[repo+"@"+listDigest.String()] = list
+	c.manifests[repo+"@"+mDigest.String()] = m
+	c.blobs[layer.Digest] = binary
+	return listDigest
+}
+
+// tamperPlatformManifest replaces the manifest for the platform of the plugin
+// in repo with the one of the plugin in otherRepo, without updating the
+// manifest list that refers to it.
+func (c *fakeRegistryClient) tamperPlatformManifest(repo, otherRepo string) {
+	var other distribution.Manifest
+	for ref, m := range c.manifests {
+		if _, ok := m.(*ocischema.DeserializedManifest); ok && strings.HasPrefix(ref, otherRepo+"@") {
+			other = m
+		}
+	}
+	for ref, m := range c.manifests {
+		if _, ok := m.(*ocischema.DeserializedManifest); ok && strings.HasPrefix(ref, repo+"@") {
+			c.manifests[ref] = other
+		}
+	}
+}
@@ -0,0 +1,68 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package plugincli
+
+import (
+	"regexp"
+	"runtime"
+	"slices"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli-plugins/metadata"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/internal/commands"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	commands.Register(newPluginCLICommand)
+}
+
+// newPluginCLICommand returns a cobra command for `plugin-cli` subcommands
+func newPluginCLICommand(dockerCLI command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plugin-cli",
+		Short: "Manage CLI plugins",
+		Args:  cli.NoArgs,
+		RunE:  command.ShowHelp(dockerCLI.Err()),
+
+		DisableFlagsInUseLine: true,
+	}
+	cmd.AddCommand(
+		newInstallCommand(dockerCLI),
+		newListCommand(dockerCLI),
+		newUpdateCommand(dockerCLI),
+		newRemoveCommand(dockerCLI),
+	)
+	return cmd
+}
+
+// validPluginName matches the names of plugins that are accepted by the
+// plugin manager.
+var validPluginName = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+
+// binaryName returns the name of the binary of a plugin.
+func binaryName(name string) string {
+	if runtime.GOOS == "windows" {
+		return metadata.NamePrefix + name + ".exe"
+	}
+	return metadata.NamePrefix + name
+}
+
+// completeInstalledPlugins offers completion for the plugins that were
+// installed with "docker plugin-cli install".
+func completeInstalledPlugins(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	installed, err := loadInstalled()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	var names []string
+	for name := range installed {
+		if !slices.Contains(args, name) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
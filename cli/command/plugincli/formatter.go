@@ -0,0 +1,87 @@
+package plugincli
+
+import (
+	"time"
+
+	"github.com/docker/cli/cli/command/formatter"
+)
+
+const (
+	defaultPluginsTableFormat = "table {{.Name}}\t{{.Version}}\t{{.Reference}}"
+
+	nameHeader      = "NAME"
+	versionHeader   = "VERSION"
+	referenceHeader = "REFERENCE"
+	digestHeader    = "DIGEST"
+	installedHeader = "INSTALLED"
+)
+
+// newFormat returns a Format for rendering using a pluginContext.
+func newFormat(source string, quiet bool) formatter.Format {
+	switch source {
+	case "", formatter.TableFormatKey:
+		if quiet {
+			return "{{.Name}}"
+		}
+		return defaultPluginsTableFormat
+	}
+	return formatter.Format(source)
+}
+
+// namedPlugin is an installed plugin and its name.
+type namedPlugin struct {
+	name string
+	installedPlugin
+}
+
+// formatWrite writes the installed plugins using the context.
+func formatWrite(fmtCtx formatter.Context, plugins []namedPlugin) error {
+	pluginCtx := &pluginContext{
+		HeaderContext: formatter.HeaderContext{
+			Header: formatter.SubHeaderContext{
+				"Name":      nameHeader,
+				"Version":   versionHeader,
+				"Reference": referenceHeader,
+				"Digest":    digestHeader,
+				"Installed": installedHeader,
+			},
+		},
+	}
+	return fmtCtx.Write(pluginCtx, func(format func(subContext formatter.SubContext) error) error {
+		for _, p := range plugins {
+			if err := format(&pluginContext{p: p}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+type pluginContext struct {
+	formatter.HeaderContext
+	p namedPlugin
+}
+
+func (c *pluginContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *pluginContext) Name() string {
+	return c.p.name
+}
+
+func (c *pluginContext) Version() string {
+	return c.p.Version
+}
+
+func (c *pluginContext) Reference() string {
+	return c.p.Reference
+}
+
+func (c *pluginContext) Digest() string {
+	return c.p.Digest.String()
+}
+
+func (c *pluginContext) Installed() string {
+	return c.p.installedPlugin.Installed.Format(time.RFC3339)
+}
@@ -0,0 +1,243 @@
+package plugincli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/containerd/platforms"
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli-plugins/manager"
+	"github.com/docker/cli/cli-plugins/metadata"
+	"github.com/docker/cli/cli/command"
+	manifesttypes "github.com/docker/cli/cli/manifest/types"
+	"github.com/docker/cli/internal/registryclient"
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/ocischema"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/cobra"
+)
+
+// mediaTypePluginBinary is the media type of the layer that contains the
+// binary of a CLI plugin in an OCI artifact.
+const mediaTypePluginBinary = "application/vnd.docker.cli-plugin.binary.v1"
+
+type installOptions struct {
+	reference string
+	force     bool
+	insecure  bool
+}
+
+// newInstallCommand creates a new `docker plugin-cli install` command
+func newInstallCommand(dockerCLI command.Cli) *cobra.Command {
+	var options installOptions
+
+	cmd := &cobra.Command{
+		Use:   "install [OPTIONS] REFERENCE",
+		Short: "Install a CLI plugin from a registry",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.reference = args[0]
+			return runInstall(cmd.Context(), dockerCLI, options)
+		},
+		ValidArgsFunction:     cobra.NoFileCompletions,
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&options.force, "force", "f", false, "Replace a plugin that was not installed from a registry")
+	flags.BoolVar(&options.insecure, "insecure", false, "Allow communication with an insecure registry")
+
+	return cmd
+}
+
+func runInstall(ctx context.Context, dockerCLI command.Cli, options installOptions) error {
+	ref, err := reference.ParseNormalizedNamed(options.reference)
+	if err != nil {
+		return err
+	}
+	ref = reference.TagNameOnly(ref)
+
+	installed, err := loadInstalled()
+	if err != nil {
+		return err
+	}
+	p, err := pullPlugin(ctx, command.NewRegistryClient(dockerCLI, options.insecure), ref)
+	if err != nil {
+		return err
+	}
+	if _, ok := installed[p.name]; !ok && !options.force {
+		binary := filepath.Join(manager.UserPluginDir(), binaryName(p.name))
+		if _, err := os.Lstat(binary); err == nil {
+			return fmt.Errorf("plugin %q is already installed in %s; use --force to replace it", p.name, binary)
+		}
+	}
+	if err := p.install(installed); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(dockerCLI.Out(), "Installed plugin %s %s (%s)\n", p.name, p.record.Version, p.record.Digest)
+	return nil
+}
+
+// pulledPlugin is a plugin binary that was pulled from a registry, and
+// verified against its digest.
+type pulledPlugin struct {
+	name   string
+	binary []byte
+	record installedPlugin
+}
+
+// install writes the binary of the plugin to the plugin directory of the
+// user, and records the plugin as installed.
+func (p pulledPlugin) install(installed map[string]installedPlugin) error {
+	if err := writeFileAtomic(filepath.Join(manager.UserPluginDir(), binaryName(p.name)), p.binary, 0o755); err != nil {
+		return err
+	}
+	installed[p.name] = p.record
+	return saveInstalled(installed)
+}
+
+// pullPlugin pulls the binary of a plugin for the current platform from the
+// OCI artifact the reference refers to. The artifact is either an image
+// manifest with a single layer of type [mediaTypePluginBinary], or a
+// manifest list of such manifests for multiple platforms.
+func pullPlugin(ctx context.Context, client registryclient.RegistryClient, ref reference.Named) (pulledPlugin, error) {
+	mfst, err := client.GetRawManifest(ctx, ref)
+	if err != nil {
+		return pulledPlugin{}, err
+	}
+	_, payload, err := mfst.Payload()
+	if err != nil {
+		return pulledPlugin{}, err
+	}
+	dgst := digest.FromBytes(payload)
+	if canonical, ok := ref.(reference.Canonical); ok && canonical.Digest() != dgst {
+		return pulledPlugin{}, fmt.Errorf("%s: manifest digest %s does not match the reference", reference.FamiliarString(ref), dgst)
+	}
+
+	repo := reference.TrimNamed(ref)
+	if list, ok := mfst.(*manifestlist.DeserializedManifestList); ok {
+		platformDigest, err := selectManifest(list, platforms.Default())
+		if err != nil {
+			return pulledPlugin{}, fmt.Errorf("%s: %w", reference.FamiliarString(ref), err)
+		}
+		platformRef, err := reference.WithDigest(repo, platformDigest)
+		if err != nil {
+			return pulledPlugin{}, err
+		}
+		mfst, err = client.GetRawManifest(ctx, platformRef)
+		if err != nil {
+			return pulledPlugin{}, err
+		}
+		// The manifest is not verified by the client, and the digest of
+		// the layer is taken from it, so it must be verified against the
+		// digest in the manifest list.
+		_, payload, err := mfst.Payload()
+		if err != nil {
+			return pulledPlugin{}, err
+		}
+		if dgst := digest.FromBytes(payload); dgst != platformDigest {
+			return pulledPlugin{}, fmt.Errorf("%s: digest of the manifest for the platform (%s) does not match the manifest list (%s)", reference.FamiliarString(ref), dgst, platformDigest)
+		}
+	}
+
+	m, ok := mfst.(*ocischema.DeserializedManifest)
+	if !ok {
+		return pulledPlugin{}, fmt.Errorf("%s: unsupported manifest type: %T", reference.FamiliarString(ref), mfst)
+	}
+	layer, err := pluginLayer(m.Layers)
+	if err != nil {
+		return pulledPlugin{}, fmt.Errorf("%s: %w", reference.FamiliarString(ref), err)
+	}
+	name, err := pluginName(repo, layer)
+	if err != nil {
+		return pulledPlugin{}, fmt.Errorf("%s: %w", reference.FamiliarString(ref), err)
+	}
+
+	layerRef, err := reference.WithDigest(repo, layer.Digest)
+	if err != nil {
+		return pulledPlugin{}, err
+	}
+	// The content of the blob is verified against its digest by the client.
+	binary, err := client.GetBlob(ctx, layerRef)
+	if err != nil {
+		return pulledPlugin{}, err
+	}
+	if int64(len(binary)) != layer.Size {
+		return pulledPlugin{}, fmt.Errorf("%s: size of the plugin binary (%d) does not match the manifest (%d)", reference.FamiliarString(ref), len(binary), layer.Size)
+	}
+
+	version := m.Annotations[ocispec.AnnotationVersion]
+	if tagged, ok := ref.(reference.Tagged); ok && version == "" {
+		version = tagged.Tag()
+	}
+	return pulledPlugin{
+		name:   name,
+		binary: binary,
+		record: installedPlugin{
+			Reference: reference.FamiliarString(ref),
+			Digest:    dgst,
+			Version:   version,
+			Installed: time.Now().UTC(),
+		},
+	}, nil
+}
+
+// selectManifest returns the digest of the manifest in the list that best
+// matches the platform, ignoring attestations.
+func selectManifest(list *manifestlist.DeserializedManifestList, matcher platforms.MatchComparer) (digest.Digest, error) {
+	var best *manifestlist.ManifestDescriptor
+	for i, m := range list.Manifests {
+		if m.Annotations[manifesttypes.AnnotationReferenceType] == manifesttypes.ReferenceTypeAttestation {
+			continue
+		}
+		p := manifesttypes.OCIPlatform(&m.Platform)
+		if !matcher.Match(*p) {
+			continue
+		}
+		if best == nil || matcher.Less(*p, *manifesttypes.OCIPlatform(&best.Platform)) {
+			best = &list.Manifests[i]
+		}
+	}
+	if best == nil {
+		return "", errors.New("the plugin is not available for the " + platforms.Format(platforms.DefaultSpec()) + " platform")
+	}
+	return best.Digest, nil
+}
+
+// pluginLayer returns the layer that contains the binary of the plugin.
+func pluginLayer(layers []distribution.Descriptor) (distribution.Descriptor, error) {
+	var found []distribution.Descriptor
+	for _, l := range layers {
+		if l.MediaType == mediaTypePluginBinary {
+			found = append(found, l)
+		}
+	}
+	if len(found) != 1 {
+		return distribution.Descriptor{}, fmt.Errorf("expected a single layer of type %s, found %d", mediaTypePluginBinary, len(found))
+	}
+	return found[0], nil
+}
+
+// pluginName returns the name of the plugin, from the title of its layer
+// ("docker-<name>"), or from the name of the repository if the layer does
+// not have a title.
+func pluginName(repo reference.Named, layer distribution.Descriptor) (string, error) {
+	name := layer.Annotations[ocispec.AnnotationTitle]
+	if name == "" {
+		name = path.Base(reference.Path(repo))
+	}
+	name = strings.TrimSuffix(strings.TrimPrefix(name, metadata.NamePrefix), ".exe")
+	if !validPluginName.MatchString(name) {
+		return "", fmt.Errorf("invalid plugin name %q: must match %s", name, validPluginName)
+	}
+	return name, nil
+}
@@ -0,0 +1,142 @@
+package plugincli
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/cli/cli-plugins/manager"
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestInstall(t *testing.T) {
+	config.SetDir(t.TempDir())
+	client := &fakeRegistryClient{}
+	dgst := client.addPlugin(t, "registry.example.com/plugins/docker-hello", "1.0", "docker-hello", "1.0.0", []byte("hello"))
+	cli := test.NewFakeCli(nil)
+	cli.SetRegistryClient(client)
+
+	cmd := newInstallCommand(cli)
+	cmd.SetArgs([]string{"registry.example.com/plugins/docker-hello:1.0"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "Installed plugin hello 1.0.0 ("+dgst.String()+")\n"))
+
+	binary := filepath.Join(manager.UserPluginDir(), binaryName("hello"))
+	content, err := os.ReadFile(binary)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(content), "hello"))
+	if fi, err := os.Stat(binary); assert.Check(t, err) {
+		assert.Check(t, fi.Mode().Perm()&0o111 != 0, "plugin binary is not executable")
+	}
+
+	installed, err := loadInstalled()
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(installed["hello"].Reference, "registry.example.com/plugins/docker-hello:1.0"))
+	assert.Check(t, is.Equal(installed["hello"].Digest, dgst))
+	assert.Check(t, is.Equal(installed["hello"].Version, "1.0.0"))
+}
+
+func TestInstallNameFromRepository(t *testing.T) {
+	config.SetDir(t.TempDir())
+	client := &fakeRegistryClient{}
+	client.addPlugin(t, "registry.example.com/plugins/docker-hello", "latest", "", "", []byte("hello"))
+	cli := test.NewFakeCli(nil)
+	cli.SetRegistryClient(client)
+
+	cmd := newInstallCommand(cli)
+	cmd.SetArgs([]string{"registry.example.com/plugins/docker-hello"})
+	assert.NilError(t, cmd.Execute())
+
+	installed, err := loadInstalled()
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(installed["hello"].Version, "latest"))
+}
+
+func TestInstallErrors(t *testing.T) {
+	const mismatchRef = "registry.example.com/plugins/docker-hello@sha256:0000000000000000000000000000000000000000000000000000000000000000"
+	testCases := []struct {
+		doc           string
+		args          []string
+		existing      bool
+		expectedError string
+	}{
+		{
+			doc:           "no reference",
+			args:          []string{},
+			expectedError: "'install' requires 1 argument",
+		},
+		{
+			doc:           "unknown reference",
+			args:          []string{"registry.example.com/plugins/docker-unknown"},
+			expectedError: "no such manifest: registry.example.com/plugins/docker-unknown:latest",
+		},
+		{
+			doc:           "invalid name",
+			args:          []string{"registry.example.com/plugins/docker-invalid"},
+			expectedError: `invalid plugin name "Invalid-Name"`,
+		},
+		{
+			doc:           "digest mismatch",
+			args:          []string{mismatchRef},
+			expectedError: "does not match the reference",
+		},
+		{
+			doc:           "tampered platform manifest",
+			args:          []string{"registry.example.com/plugins/docker-tampered"},
+			expectedError: "digest of the manifest for the platform",
+		},
+		{
+			doc:           "not installed from a registry",
+			args:          []string{"registry.example.com/plugins/docker-hello"},
+			existing:      true,
+			expectedError: `plugin "hello" is already installed in`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.doc, func(t *testing.T) {
+			config.SetDir(t.TempDir())
+			if tc.existing {
+				assert.NilError(t, os.MkdirAll(manager.UserPluginDir(), 0o755))
+				assert.NilError(t, os.WriteFile(filepath.Join(manager.UserPluginDir(), binaryName("hello")), []byte("manual"), 0o755))
+			}
+			client := &fakeRegistryClient{}
+			client.addPlugin(t, "registry.example.com/plugins/docker-hello", "latest", "docker-hello", "1.0.0", []byte("hello"))
+			client.addPlugin(t, "registry.example.com/plugins/docker-invalid", "latest", "docker-Invalid-Name", "1.0.0", []byte("invalid"))
+			client.manifests[mismatchRef] = client.manifests["registry.example.com/plugins/docker-hello:latest"]
+			client.addPlugin(t, "registry.example.com/plugins/docker-tampered", "latest", "docker-tampered", "1.0.0", []byte("tampered"))
+			client.tamperPlatformManifest("registry.example.com/plugins/docker-tampered", "registry.example.com/plugins/docker-hello")
+			cli := test.NewFakeCli(nil)
+			cli.SetRegistryClient(client)
+
+			cmd := newInstallCommand(cli)
+			cmd.SetArgs(tc.args)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			assert.Check(t, is.ErrorContains(cmd.Execute(), tc.expectedError))
+		})
+	}
+}
+
+func TestInstallForce(t *testing.T) {
+	config.SetDir(t.TempDir())
+	assert.NilError(t, os.MkdirAll(manager.UserPluginDir(), 0o755))
+	binary := filepath.Join(manager.UserPluginDir(), binaryName("hello"))
+	assert.NilError(t, os.WriteFile(binary, []byte("manual"), 0o755))
+
+	client := &fakeRegistryClient{}
+	client.addPlugin(t, "registry.example.com/plugins/docker-hello", "latest", "docker-hello", "1.0.0", []byte("hello"))
+	cli := test.NewFakeCli(nil)
+	cli.SetRegistryClient(client)
+
+	cmd := newInstallCommand(cli)
+	cmd.SetArgs([]string{"--force", "registry.example.com/plugins/docker-hello"})
+	assert.NilError(t, cmd.Execute())
+
+	content, err := os.ReadFile(binary)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(content), "hello"))
+}
@@ -0,0 +1,90 @@
+package plugincli
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/docker/cli/cli-plugins/manager"
+	"github.com/opencontainers/go-digest"
+)
+
+// installedFileName is the name of the file in the plugin directory of the
+// user that records the plugins that were installed from a registry. The
+// file is ignored by the plugin manager, as it does not have the prefix of
+// plugin binaries.
+const installedFileName = "installed.json"
+
+// installedPlugin is a plugin that was installed from a registry.
+type installedPlugin struct {
+	// Reference is the reference the plugin was installed from, which is
+	// used to update the plugin.
+	Reference string `json:"reference"`
+
+	// Digest is the digest of the manifest, or manifest list, the plugin
+	// was installed from.
+	Digest digest.Digest `json:"digest"`
+
+	// Version is the version of the plugin, as annotated in its manifest,
+	// or the tag it was installed from.
+	Version string `json:"version,omitempty"`
+
+	// Installed is the time the plugin was installed or last updated.
+	Installed time.Time `json:"installed"`
+}
+
+// loadInstalled returns the plugins that were installed from a registry,
+// by their name.
+func loadInstalled() (map[string]installedPlugin, error) {
+	installed := map[string]installedPlugin{}
+	b, err := os.ReadFile(filepath.Join(manager.UserPluginDir(), installedFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return installed, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &installed); err != nil {
+		return nil, err
+	}
+	return installed, nil
+}
+
+// saveInstalled records the plugins that were installed from a registry.
+func saveInstalled(installed map[string]installedPlugin) error {
+	b, err := json.MarshalIndent(installed, "", "\t")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(manager.UserPluginDir(), installedFileName), b, 0o644)
+}
+
+// writeFileAtomic writes the file to a temporary file in the same directory,
+// which is renamed to the file, so that a running plugin, or the file, is
+// never partially written.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,58 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package plugincli
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+type listOptions struct {
+	format string
+	quiet  bool
+}
+
+func newListCommand(dockerCLI command.Cli) *cobra.Command {
+	var options listOptions
+
+	cmd := &cobra.Command{
+		Use:     "ls [OPTIONS]",
+		Aliases: []string{"list"},
+		Short:   "List the CLI plugins that were installed from a registry",
+		Args:    cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runList(dockerCLI, options)
+		},
+		ValidArgsFunction:     cobra.NoFileCompletions,
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&options.quiet, "quiet", "q", false, "Only display plugin names")
+	flags.StringVar(&options.format, "format", "", flagsHelper.FormatHelp)
+
+	return cmd
+}
+
+func runList(dockerCLI command.Cli, options listOptions) error {
+	installed, err := loadInstalled()
+	if err != nil {
+		return err
+	}
+	plugins := make([]namedPlugin, 0, len(installed))
+	for _, name := range slices.Sorted(maps.Keys(installed)) {
+		plugins = append(plugins, namedPlugin{name: name, installedPlugin: installed[name]})
+	}
+
+	return formatWrite(formatter.Context{
+		Output: dockerCLI.Out(),
+		Format: newFormat(options.format, options.quiet),
+	}, plugins)
+}
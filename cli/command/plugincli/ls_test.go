@@ -0,0 +1,49 @@
+package plugincli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/golden"
+)
+
+func TestList(t *testing.T) {
+	installed := map[string]installedPlugin{
+		"hello": {
+			Reference: "registry.example.com/plugins/docker-hello:1.0",
+			Digest:    "sha256:b5bb9d8014a0f9b1d61e21e796d78dccdf1352f23cd32812f4850b878ae4944c",
+			Version:   "1.0.0",
+			Installed: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		"audit": {
+			Reference: "registry.example.com/plugins/docker-audit:latest",
+			Digest:    "sha256:7d865e959b2466918c9863afca942d0fb89d7c9ac0c99bafc3749504ded97730",
+			Version:   "latest",
+			Installed: time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC),
+		},
+	}
+	testCases := []struct {
+		doc    string
+		args   []string
+		golden string
+	}{
+		{doc: "table", args: []string{}, golden: "plugin-cli-list.table.golden"},
+		{doc: "quiet", args: []string{"--quiet"}, golden: "plugin-cli-list.quiet.golden"},
+		{doc: "format", args: []string{"--format", "{{.Name}} {{.Digest}} {{.Installed}}"}, golden: "plugin-cli-list.format.golden"},
+		{doc: "json", args: []string{"--format", "json"}, golden: "plugin-cli-list.json.golden"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.doc, func(t *testing.T) {
+			config.SetDir(t.TempDir())
+			assert.NilError(t, saveInstalled(installed))
+			cli := test.NewFakeCli(nil)
+			cmd := newListCommand(cli)
+			cmd.SetArgs(tc.args)
+			assert.NilError(t, cmd.Execute())
+			golden.Assert(t, cli.OutBuffer().String(), tc.golden)
+		})
+	}
+}
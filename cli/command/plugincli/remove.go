@@ -0,0 +1,66 @@
+package plugincli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli-plugins/manager"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+)
+
+// newRemoveCommand creates a new `docker plugin-cli rm` command
+func newRemoveCommand(dockerCLI command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "rm PLUGIN [PLUGIN...]",
+		Aliases: []string{"remove"},
+		Short:   "Remove one or more CLI plugins that were installed from a registry",
+		Args:    cli.RequiresMinArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRemove(dockerCLI, args)
+		},
+		ValidArgsFunction:     completeInstalledPlugins,
+		DisableFlagsInUseLine: true,
+	}
+	return cmd
+}
+
+// runRemove removes the binaries of the plugins from the plugin directory of
+// the user. Only plugins that were installed with "docker plugin-cli install"
+// can be removed.
+func runRemove(dockerCLI command.Cli, names []string) error {
+	installed, err := loadInstalled()
+	if err != nil {
+		return err
+	}
+
+	var (
+		errs    []error
+		removed []string
+	)
+	for _, name := range names {
+		if _, ok := installed[name]; !ok {
+			errs = append(errs, fmt.Errorf("plugin %q was not installed from a registry", name))
+			continue
+		}
+		err := os.Remove(filepath.Join(manager.UserPluginDir(), binaryName(name)))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+			continue
+		}
+		delete(installed, name)
+		removed = append(removed, name)
+	}
+	if len(removed) > 0 {
+		if err := saveInstalled(installed); err != nil {
+			return err
+		}
+		for _, name := range removed {
+			_, _ = fmt.Fprintln(dockerCLI.Out(), name)
+		}
+	}
+	return errors.Join(errs...)
+}
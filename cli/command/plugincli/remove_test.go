@@ -0,0 +1,43 @@
+package plugincli
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/cli/cli-plugins/manager"
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestRemove(t *testing.T) {
+	config.SetDir(t.TempDir())
+	client := &fakeRegistryClient{}
+	client.addPlugin(t, "registry.example.com/plugins/docker-hello", "latest", "docker-hello", "1.0.0", []byte("hello"))
+	cli := test.NewFakeCli(nil)
+	cli.SetRegistryClient(client)
+	cmd := newInstallCommand(cli)
+	cmd.SetArgs([]string{"registry.example.com/plugins/docker-hello"})
+	assert.NilError(t, cmd.Execute())
+	manual := filepath.Join(manager.UserPluginDir(), binaryName("manual"))
+	assert.NilError(t, os.WriteFile(manual, []byte("manual"), 0o755))
+	cli.ResetOutputBuffers()
+
+	cmd = newRemoveCommand(cli)
+	cmd.SetArgs([]string{"hello", "manual"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Check(t, is.Error(cmd.Execute(), `plugin "manual" was not installed from a registry`))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "hello\n"))
+
+	_, err := os.Stat(filepath.Join(manager.UserPluginDir(), binaryName("hello")))
+	assert.Check(t, os.IsNotExist(err))
+	_, err = os.Stat(manual)
+	assert.Check(t, err)
+	installed, err := loadInstalled()
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(installed, 0))
+}
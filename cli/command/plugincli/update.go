@@ -0,0 +1,104 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package plugincli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/internal/registryclient"
+	"github.com/spf13/cobra"
+)
+
+type updateOptions struct {
+	plugins  []string
+	all      bool
+	insecure bool
+}
+
+// newUpdateCommand creates a new `docker plugin-cli update` command
+func newUpdateCommand(dockerCLI command.Cli) *cobra.Command {
+	var options updateOptions
+
+	cmd := &cobra.Command{
+		Use:   "update [OPTIONS] [PLUGIN...]",
+		Short: "Update CLI plugins that were installed from a registry",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch {
+			case options.all && len(args) > 0:
+				return errors.New("a plugin cannot be specified when using --all")
+			case !options.all && len(args) == 0:
+				return errors.New("at least one plugin is required, unless --all is set")
+			}
+			options.plugins = args
+			return runUpdate(cmd.Context(), dockerCLI, options)
+		},
+		ValidArgsFunction:     completeInstalledPlugins,
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&options.all, "all", "a", false, "Update all plugins that were installed from a registry")
+	flags.BoolVar(&options.insecure, "insecure", false, "Allow communication with an insecure registry")
+
+	return cmd
+}
+
+// runUpdate updates the plugins to the current version of the reference they
+// were installed from. Plugins are only pulled if the digest of the reference
+// changed.
+func runUpdate(ctx context.Context, dockerCLI command.Cli, options updateOptions) error {
+	installed, err := loadInstalled()
+	if err != nil {
+		return err
+	}
+	names := options.plugins
+	if options.all {
+		names = slices.Sorted(maps.Keys(installed))
+	}
+
+	client := command.NewRegistryClient(dockerCLI, options.insecure)
+	var errs []error
+	for _, name := range names {
+		current, ok := installed[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("plugin %q was not installed from a registry", name))
+			continue
+		}
+		ref, err := reference.ParseNormalizedNamed(current.Reference)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("plugin %q: %w", name, err))
+			continue
+		}
+		dgst, err := registryclient.ResolveDigest(ctx, client, ref)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("plugin %q: %w", name, err))
+			continue
+		}
+		if dgst == current.Digest {
+			_, _ = fmt.Fprintf(dockerCLI.Out(), "Plugin %s is up to date (%s)\n", name, current.Version)
+			continue
+		}
+		p, err := pullPlugin(ctx, client, ref)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("plugin %q: %w", name, err))
+			continue
+		}
+		if p.name != name {
+			errs = append(errs, fmt.Errorf("plugin %q: %s now contains the %q plugin", name, current.Reference, p.name))
+			continue
+		}
+		if err := p.install(installed); err != nil {
+			errs = append(errs, fmt.Errorf("plugin %q: %w", name, err))
+			continue
+		}
+		_, _ = fmt.Fprintf(dockerCLI.Out(), "Updated plugin %s from %s to %s (%s)\n", name, current.Version, p.record.Version, p.record.Digest)
+	}
+	return errors.Join(errs...)
+}
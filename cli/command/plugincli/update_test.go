@@ -0,0 +1,78 @@
+package plugincli
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/cli/cli-plugins/manager"
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestUpdate(t *testing.T) {
+	config.SetDir(t.TempDir())
+	client := &fakeRegistryClient{}
+	client.addPlugin(t, "registry.example.com/plugins/docker-hello", "latest", "docker-hello", "1.0.0", []byte("hello"))
+	client.addPlugin(t, "registry.example.com/plugins/docker-world", "latest", "docker-world", "2.0.0", []byte("world"))
+	cli := test.NewFakeCli(nil)
+	cli.SetRegistryClient(client)
+	for _, ref := range []string{"registry.example.com/plugins/docker-hello", "registry.example.com/plugins/docker-world"} {
+		cmd := newInstallCommand(cli)
+		cmd.SetArgs([]string{ref})
+		assert.NilError(t, cmd.Execute())
+	}
+	cli.ResetOutputBuffers()
+
+	dgst := client.addPlugin(t, "registry.example.com/plugins/docker-hello", "latest", "docker-hello", "1.1.0", []byte("hello, again"))
+	client.blobPulls = 0
+
+	cmd := newUpdateCommand(cli)
+	cmd.SetArgs([]string{"--all"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "Updated plugin hello from 1.0.0 to 1.1.0 ("+dgst.String()+")\nPlugin world is up to date (2.0.0)\n"))
+	assert.Check(t, is.Equal(client.blobPulls, 1))
+
+	content, err := os.ReadFile(filepath.Join(manager.UserPluginDir(), binaryName("hello")))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(content), "hello, again"))
+	installed, err := loadInstalled()
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(installed["hello"].Version, "1.1.0"))
+	assert.Check(t, is.Equal(installed["hello"].Digest, dgst))
+}
+
+func TestUpdateErrors(t *testing.T) {
+	testCases := []struct {
+		args          []string
+		expectedError string
+	}{
+		{
+			args:          []string{},
+			expectedError: "at least one plugin is required, unless --all is set",
+		},
+		{
+			args:          []string{"--all", "hello"},
+			expectedError: "a plugin cannot be specified when using --all",
+		},
+		{
+			args:          []string{"hello"},
+			expectedError: `plugin "hello" was not installed from a registry`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.expectedError, func(t *testing.T) {
+			config.SetDir(t.TempDir())
+			cli := test.NewFakeCli(nil)
+			cli.SetRegistryClient(&fakeRegistryClient{})
+			cmd := newUpdateCommand(cli)
+			cmd.SetArgs(tc.args)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			assert.Check(t, is.ErrorContains(cmd.Execute(), tc.expectedError))
+		})
+	}
+}
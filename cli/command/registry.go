@@ -15,6 +15,8 @@
 	"github.com/docker/cli/cli/hints"
 	"github.com/docker/cli/cli/streams"
 	"github.com/docker/cli/internal/prompt"
+	"github.com/docker/cli/internal/registry"
+	"github.com/docker/cli/internal/registryclient"
 	"github.com/docker/cli/internal/tui"
 	"github.com/moby/moby/api/pkg/authconfig"
 	registrytypes "github.com/moby/moby/api/types/registry"
@@ -59,6 +61,42 @@ func ResolveAuthConfig(cfg *configfile.ConfigFile, index *registrytypes.IndexInf
 	}
 }
 
+// registryClientProvider is used in tests to provide a dummy registry client.
+type registryClientProvider interface {
+	RegistryClient(allowInsecure bool) registryclient.RegistryClient
+}
+
+// NewRegistryClient returns a client for communicating with a registry
+// directly, without going through the daemon, using the credentials that
+// are stored by "docker login".
+func NewRegistryClient(dockerCLI Cli, allowInsecure bool) registryclient.RegistryClient {
+	if p, ok := dockerCLI.(registryClientProvider); ok {
+		return p.RegistryClient(allowInsecure)
+	}
+	return registryclient.NewRegistryClient(registryAuthResolver(dockerCLI.ConfigFile()), UserAgent(), allowInsecure)
+}
+
+// registryAuthResolver returns a resolver for the credentials of a registry
+// that are stored in the config file. Credentials for Docker Hub are looked
+// up by [authConfigKey], which is the key "docker login" stores them under.
+func registryAuthResolver(cfg *configfile.ConfigFile) registryclient.AuthConfigResolver {
+	return func(ctx context.Context, indexName string) registrytypes.AuthConfig {
+		configKey := indexName
+		if indexName == registry.IndexName {
+			configKey = authConfigKey
+		}
+		a, _ := cfg.GetAuthConfig(configKey)
+		return registrytypes.AuthConfig{
+			Username:      a.Username,
+			Password:      a.Password,
+			ServerAddress: a.ServerAddress,
+			Auth:          a.Auth,
+			IdentityToken: a.IdentityToken,
+			RegistryToken: a.RegistryToken,
+		}
+	}
+}
+
 // GetDefaultAuthConfig gets the default auth config given a serverAddress
 // If credentials for given serverAddress exists in the credential store, the configuration will be populated with values in it
 func GetDefaultAuthConfig(cfg *configfile.ConfigFile, checkCredStore bool, serverAddress string, isDefaultRegistry bool) (registrytypes.AuthConfig, error) {
@@ -0,0 +1,60 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+type catalogOptions struct {
+	host     string
+	format   string
+	insecure bool
+}
+
+// newCatalogCommand creates a new `docker registry catalog` command
+func newCatalogCommand(dockerCLI command.Cli) *cobra.Command {
+	var options catalogOptions
+
+	cmd := &cobra.Command{
+		Use:   "catalog [OPTIONS] HOST",
+		Short: "List the repositories in a registry",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.host = args[0]
+			return runCatalog(cmd.Context(), dockerCLI, options)
+		},
+		ValidArgsFunction:     cobra.NoFileCompletions,
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&options.format, "format", "", flagsHelper.FormatHelp)
+	flags.BoolVar(&options.insecure, "insecure", false, "Allow communication with an insecure registry")
+
+	return cmd
+}
+
+func runCatalog(ctx context.Context, dockerCLI command.Cli, options catalogOptions) error {
+	host := strings.TrimSuffix(options.host, "/")
+	if host == "" || strings.Contains(host, "/") {
+		return fmt.Errorf("invalid registry host %q: must be a hostname, optionally with a port", options.host)
+	}
+
+	repos, err := command.NewRegistryClient(dockerCLI, options.insecure).ListRepositories(ctx, host)
+	if err != nil {
+		return err
+	}
+
+	catalogCtx := formatter.Context{
+		Output: dockerCLI.Out(),
+		Format: newCatalogFormat(options.format),
+	}
+	return catalogFormatWrite(catalogCtx, repos)
+}
@@ -0,0 +1,36 @@
+package registry
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/golden"
+)
+
+func TestCatalogInvalidHost(t *testing.T) {
+	cli := test.NewFakeCli(nil)
+	cli.SetRegistryClient(&fakeRegistryClient{})
+	cmd := newCatalogCommand(cli)
+	cmd.SetArgs([]string{"localhost:5000/team"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Check(t, is.Error(cmd.Execute(), `invalid registry host "localhost:5000/team": must be a hostname, optionally with a port`))
+}
+
+func TestCatalog(t *testing.T) {
+	cli := test.NewFakeCli(nil)
+	cli.SetRegistryClient(&fakeRegistryClient{
+		listRepositoriesFunc: func(_ context.Context, hostname string) ([]string, error) {
+			assert.Check(t, is.Equal(hostname, "localhost:5000"))
+			return []string{"library/alpine", "team/app"}, nil
+		},
+	})
+	cmd := newCatalogCommand(cli)
+	cmd.SetArgs([]string{"localhost:5000/"})
+	assert.NilError(t, cmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "catalog-command-success.golden")
+}
@@ -0,0 +1,55 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/internal/registryclient"
+	"github.com/docker/distribution"
+	"github.com/opencontainers/go-digest"
+)
+
+type fakeRegistryClient struct {
+	registryclient.RegistryClient
+	manifests            map[string]distribution.Manifest
+	blobs                map[digest.Digest][]byte
+	listTagsFunc         func(ctx context.Context, ref reference.Named) ([]string, error)
+	listRepositoriesFunc func(ctx context.Context, hostname string) ([]string, error)
+	deleteManifestFunc   func(ctx context.Context, ref reference.Canonical) error
+}
+
+func (c *fakeRegistryClient) GetRawManifest(_ context.Context, ref reference.Named) (distribution.Manifest, error) {
+	if m, ok := c.manifests[ref.String()]; ok {
+		return m, nil
+	}
+	return nil, fmt.Errorf("no such manifest: %s", ref)
+}
+
+func (c *fakeRegistryClient) GetBlob(_ context.Context, ref reference.Canonical) ([]byte, error) {
+	if b, ok := c.blobs[ref.Digest()]; ok {
+		return b, nil
+	}
+	return nil, fmt.Errorf("no such blob: %s", ref)
+}
+
+func (c *fakeRegistryClient) ListTags(ctx context.Context, ref reference.Named) ([]string, error) {
+	if c.listTagsFunc != nil {
+		return c.listTagsFunc(ctx, ref)
+	}
+	return nil, nil
+}
+
+func (c *fakeRegistryClient) ListRepositories(ctx context.Context, hostname string) ([]string, error) {
+	if c.listRepositoriesFunc != nil {
+		return c.listRepositoriesFunc(ctx, hostname)
+	}
+	return nil, nil
+}
+
+func (c *fakeRegistryClient) DeleteManifest(ctx context.Context, ref reference.Canonical) error {
+	if c.deleteManifestFunc != nil {
+		return c.deleteManifestFunc(ctx, ref)
+	}
+	return nil
+}
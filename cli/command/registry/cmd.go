@@ -0,0 +1,30 @@
+package registry
+
+import (
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/internal/commands"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	commands.Register(newRegistryCommand)
+}
+
+// newRegistryCommand returns a cobra command for `registry` subcommands
+func newRegistryCommand(dockerCLI command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "registry",
+		Short: "Manage repositories and tags in a registry",
+		Args:  cli.NoArgs,
+		RunE:  command.ShowHelp(dockerCLI.Err()),
+
+		DisableFlagsInUseLine: true,
+	}
+	cmd.AddCommand(
+		newCatalogCommand(dockerCLI),
+		newDeleteCommand(dockerCLI),
+		newTagsCommand(dockerCLI),
+	)
+	return cmd
+}
@@ -0,0 +1,248 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	manifesttypes "github.com/docker/cli/cli/manifest/types"
+	"github.com/docker/cli/internal/registryclient"
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/ocischema"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/opencontainers/go-digest"
+	"github.com/spf13/cobra"
+)
+
+type deleteOptions struct {
+	image     string
+	dryRun    bool
+	olderThan time.Duration
+	keep      int
+	insecure  bool
+}
+
+// newDeleteCommand creates a new `docker registry delete` command
+func newDeleteCommand(dockerCLI command.Cli) *cobra.Command {
+	var options deleteOptions
+
+	cmd := &cobra.Command{
+		Use:   "delete [OPTIONS] REPOSITORY[:TAG|@DIGEST]",
+		Short: "Delete images from a registry",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.image = args[0]
+			return runDelete(cmd.Context(), dockerCLI, options)
+		},
+		ValidArgsFunction:     cobra.NoFileCompletions,
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&options.dryRun, "dry-run", false, "Show the images that would be deleted, without deleting them")
+	flags.DurationVar(&options.olderThan, "older-than", 0, "Delete the tags of the repository with images created longer ago than the given duration (e.g. 720h)")
+	flags.IntVar(&options.keep, "keep", 0, "Delete the tags of the repository, except for the given number of most recently created images")
+	flags.BoolVar(&options.insecure, "insecure", false, "Allow communication with an insecure registry")
+
+	return cmd
+}
+
+// taggedImage is a tag in a repository, and the image it refers to.
+type taggedImage struct {
+	tag     string
+	digest  digest.Digest
+	created time.Time
+}
+
+func runDelete(ctx context.Context, dockerCLI command.Cli, options deleteOptions) error {
+	named, err := reference.ParseNormalizedNamed(options.image)
+	if err != nil {
+		return err
+	}
+	retention := options.olderThan != 0 || options.keep != 0
+	switch {
+	case options.olderThan < 0 || options.keep < 0:
+		return errors.New("--older-than and --keep must not be negative")
+	case retention && !reference.IsNameOnly(named):
+		return errors.New("--older-than and --keep can only be used with a repository, not with a tag or digest")
+	case !retention && reference.IsNameOnly(named):
+		return errors.New("a tag or digest is required, unless --older-than or --keep is set")
+	}
+
+	client := command.NewRegistryClient(dockerCLI, options.insecure)
+	repo := reference.TrimNamed(named)
+
+	if !retention {
+		dgst, err := registryclient.ResolveDigest(ctx, client, named)
+		if err != nil {
+			return err
+		}
+		return deleteImage(ctx, dockerCLI.Out(), client, repo, dgst, []string{reference.FamiliarString(named)}, options.dryRun)
+	}
+
+	images, err := listTaggedImages(ctx, client, repo)
+	if err != nil {
+		return err
+	}
+	for _, img := range images {
+		if img.created.IsZero() {
+			_, _ = fmt.Fprintf(dockerCLI.Err(), "Skipping %s:%s: the image does not have a creation date\n", reference.FamiliarName(repo), img.tag)
+		}
+	}
+
+	// Tags are deleted by deleting the manifest they refer to, which also
+	// deletes the other tags that refer to the manifest. Tags that are kept
+	// therefore also keep the manifest for all their other tags.
+	toDelete := selectExpired(images, options.keep, options.olderThan, time.Now())
+	var digests []digest.Digest
+	tags := make(map[digest.Digest][]string)
+	for _, img := range toDelete {
+		if !slices.Contains(digests, img.digest) {
+			digests = append(digests, img.digest)
+		}
+		tags[img.digest] = append(tags[img.digest], reference.FamiliarName(repo)+":"+img.tag)
+	}
+	for _, dgst := range digests {
+		if err := deleteImage(ctx, dockerCLI.Out(), client, repo, dgst, tags[dgst], options.dryRun); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// selectExpired returns the images that are not among the keep most recently
+// created images, and that were created longer than olderThan ago. Images
+// are counted by their digest, not by their tags. Images without a creation
+// date, and images that have the same digest as an image that is kept, are
+// never selected.
+func selectExpired(images []taggedImage, keep int, olderThan time.Duration, now time.Time) []taggedImage {
+	images = slices.Clone(images)
+	slices.SortStableFunc(images, func(a, b taggedImage) int {
+		return b.created.Compare(a.created)
+	})
+
+	var (
+		expired []taggedImage
+		recent  []digest.Digest
+	)
+	kept := make(map[digest.Digest]bool)
+	for _, img := range images {
+		if !img.created.IsZero() && len(recent) < keep && !slices.Contains(recent, img.digest) {
+			recent = append(recent, img.digest)
+		}
+		switch {
+		case img.created.IsZero(),
+			slices.Contains(recent, img.digest),
+			olderThan > 0 && now.Sub(img.created) < olderThan:
+			kept[img.digest] = true
+		default:
+			expired = append(expired, img)
+		}
+	}
+	return slices.DeleteFunc(expired, func(img taggedImage) bool { return kept[img.digest] })
+}
+
+func deleteImage(ctx context.Context, out io.Writer, client registryclient.RegistryClient, repo reference.Named, dgst digest.Digest, tags []string, dryRun bool) error {
+	ref, err := reference.WithDigest(repo, dgst)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		_, _ = fmt.Fprintf(out, "Would delete %s (%s)\n", strings.Join(tags, ", "), dgst)
+		return nil
+	}
+	if err := client.DeleteManifest(ctx, ref); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(out, "Deleted %s (%s)\n", strings.Join(tags, ", "), dgst)
+	return nil
+}
+
+// listTaggedImages returns the tags of the repository, with the digest and
+// creation date of the images they refer to.
+func listTaggedImages(ctx context.Context, client registryclient.RegistryClient, repo reference.Named) ([]taggedImage, error) {
+	tags, err := client.ListTags(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	images := make([]taggedImage, 0, len(tags))
+	for _, tag := range tags {
+		ref, err := reference.WithTag(repo, tag)
+		if err != nil {
+			return nil, err
+		}
+		mfst, err := client.GetRawManifest(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+		_, payload, err := mfst.Payload()
+		if err != nil {
+			return nil, err
+		}
+		created, err := imageCreated(ctx, client, repo, mfst)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, taggedImage{tag: tag, digest: digest.FromBytes(payload), created: created})
+	}
+	return images, nil
+}
+
+// imageCreated returns the creation date from the image config. The creation
+// date of a multi-platform image is the creation date of its first image.
+// A zero time is returned if the image does not have a creation date.
+func imageCreated(ctx context.Context, client registryclient.RegistryClient, repo reference.Named, mfst distribution.Manifest) (time.Time, error) {
+	var config digest.Digest
+	switch m := mfst.(type) {
+	case *manifestlist.DeserializedManifestList:
+		for _, desc := range m.Manifests {
+			if desc.Annotations[manifesttypes.AnnotationReferenceType] == manifesttypes.ReferenceTypeAttestation {
+				continue
+			}
+			ref, err := reference.WithDigest(repo, desc.Digest)
+			if err != nil {
+				return time.Time{}, err
+			}
+			img, err := client.GetRawManifest(ctx, ref)
+			if err != nil {
+				return time.Time{}, err
+			}
+			return imageCreated(ctx, client, repo, img)
+		}
+		return time.Time{}, nil
+	case *schema2.DeserializedManifest:
+		config = m.Config.Digest
+	case *ocischema.DeserializedManifest:
+		config = m.Config.Digest
+	default:
+		return time.Time{}, nil
+	}
+
+	ref, err := reference.WithDigest(repo, config)
+	if err != nil {
+		return time.Time{}, err
+	}
+	configJSON, err := client.GetBlob(ctx, ref)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var cfg struct {
+		Created *time.Time `json:"created,omitempty"`
+	}
+	if err := json.Unmarshal(configJSON, &cfg); err != nil || cfg.Created == nil {
+		return time.Time{}, nil
+	}
+	return *cfg.Created, nil
+}
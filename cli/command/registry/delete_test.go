@@ -0,0 +1,226 @@
+package registry
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/opencontainers/go-digest"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// testImage returns an image manifest with the given creation date, and the
+// config blob it refers to.
+func testImage(t *testing.T, created string) (*schema2.DeserializedManifest, []byte) {
+	t.Helper()
+	config := []byte(`{"architecture":"amd64","os":"linux"}`)
+	if created != "" {
+		config = []byte(`{"architecture":"amd64","os":"linux","created":"` + created + `"}`)
+	}
+	m, err := schema2.FromStruct(schema2.Manifest{
+		Versioned: schema2.SchemaVersion,
+		Config: distribution.Descriptor{
+			MediaType: schema2.MediaTypeImageConfig,
+			Digest:    digest.FromBytes(config),
+			Size:      int64(len(config)),
+		},
+	})
+	assert.NilError(t, err)
+	return m, config
+}
+
+func manifestDigest(t *testing.T, m distribution.Manifest) digest.Digest {
+	t.Helper()
+	_, payload, err := m.Payload()
+	assert.NilError(t, err)
+	return digest.FromBytes(payload)
+}
+
+// newDeleteTestClient returns a client for a repository with the tags
+// v1, v2, v3, latest (the same image as v3), and nodate (an image without
+// a creation date).
+func newDeleteTestClient(t *testing.T, deleted *[]string) *fakeRegistryClient {
+	t.Helper()
+	client := &fakeRegistryClient{
+		manifests: map[string]distribution.Manifest{},
+		blobs:     map[digest.Digest][]byte{},
+		listTagsFunc: func(context.Context, reference.Named) ([]string, error) {
+			return []string{"v1", "v2", "v3", "latest", "nodate"}, nil
+		},
+		deleteManifestFunc: func(_ context.Context, ref reference.Canonical) error {
+			*deleted = append(*deleted, ref.String())
+			return nil
+		},
+	}
+	for tag, created := range map[string]string{
+		"v1":     "2020-01-01T00:00:00Z",
+		"v2":     "2021-01-01T00:00:00Z",
+		"v3":     "2022-01-01T00:00:00Z",
+		"latest": "2022-01-01T00:00:00Z",
+		"nodate": "",
+	} {
+		m, config := testImage(t, created)
+		client.manifests["localhost:5000/app:"+tag] = m
+		client.blobs[m.Config.Digest] = config
+	}
+	return client
+}
+
+func TestDeleteErrors(t *testing.T) {
+	testCases := []struct {
+		args          []string
+		expectedError string
+	}{
+		{
+			args:          []string{},
+			expectedError: "'delete' requires 1 argument",
+		},
+		{
+			args:          []string{"localhost:5000/app"},
+			expectedError: "a tag or digest is required, unless --older-than or --keep is set",
+		},
+		{
+			args:          []string{"--keep", "2", "localhost:5000/app:v1"},
+			expectedError: "--older-than and --keep can only be used with a repository, not with a tag or digest",
+		},
+		{
+			args:          []string{"--keep", "-1", "localhost:5000/app"},
+			expectedError: "--older-than and --keep must not be negative",
+		},
+		{
+			args:          []string{"localhost:5000/app:unknown"},
+			expectedError: "no such manifest: localhost:5000/app:unknown",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.expectedError, func(t *testing.T) {
+			cli := test.NewFakeCli(nil)
+			cli.SetRegistryClient(&fakeRegistryClient{})
+			cmd := newDeleteCommand(cli)
+			cmd.SetArgs(tc.args)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			assert.Check(t, is.ErrorContains(cmd.Execute(), tc.expectedError))
+		})
+	}
+}
+
+func TestDeleteTag(t *testing.T) {
+	var deleted []string
+	client := newDeleteTestClient(t, &deleted)
+	cli := test.NewFakeCli(nil)
+	cli.SetRegistryClient(client)
+
+	cmd := newDeleteCommand(cli)
+	cmd.SetArgs([]string{"localhost:5000/app:v1"})
+	assert.NilError(t, cmd.Execute())
+
+	dgst := manifestDigest(t, client.manifests["localhost:5000/app:v1"])
+	assert.Check(t, is.DeepEqual(deleted, []string{"localhost:5000/app@" + dgst.String()}))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "Deleted localhost:5000/app:v1 ("+dgst.String()+")\n"))
+}
+
+func TestDeleteDigest(t *testing.T) {
+	var deleted []string
+	cli := test.NewFakeCli(nil)
+	cli.SetRegistryClient(&fakeRegistryClient{
+		deleteManifestFunc: func(_ context.Context, ref reference.Canonical) error {
+			deleted = append(deleted, ref.String())
+			return nil
+		},
+	})
+
+	ref := "localhost:5000/app@" + digest.FromString("foo").String()
+	cmd := newDeleteCommand(cli)
+	cmd.SetArgs([]string{"--dry-run", ref})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Len(deleted, 0))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "Would delete "+ref+" ("+digest.FromString("foo").String()+")\n"))
+}
+
+func TestDeleteKeep(t *testing.T) {
+	testCases := []struct {
+		doc      string
+		keep     string
+		expected []string
+	}{
+		{
+			doc:      "keep one",
+			keep:     "1",
+			expected: []string{"v2", "v1"},
+		},
+		{
+			// v3 and latest are the same image, so v2 must be kept as well.
+			doc:      "keep distinct images",
+			keep:     "2",
+			expected: []string{"v1"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.doc, func(t *testing.T) {
+			var deleted []string
+			client := newDeleteTestClient(t, &deleted)
+			cli := test.NewFakeCli(nil)
+			cli.SetRegistryClient(client)
+
+			cmd := newDeleteCommand(cli)
+			cmd.SetArgs([]string{"--keep", tc.keep, "localhost:5000/app"})
+			assert.NilError(t, cmd.Execute())
+
+			var expectedDeleted []string
+			var expectedOut string
+			for _, tag := range tc.expected {
+				dgst := manifestDigest(t, client.manifests["localhost:5000/app:"+tag])
+				expectedDeleted = append(expectedDeleted, "localhost:5000/app@"+dgst.String())
+				expectedOut += "Deleted localhost:5000/app:" + tag + " (" + dgst.String() + ")\n"
+			}
+			assert.Check(t, is.DeepEqual(deleted, expectedDeleted))
+			assert.Check(t, is.Equal(cli.OutBuffer().String(), expectedOut))
+			assert.Check(t, is.Equal(cli.ErrBuffer().String(), "Skipping localhost:5000/app:nodate: the image does not have a creation date\n"))
+		})
+	}
+}
+
+func TestSelectExpired(t *testing.T) {
+	now := time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC)
+	images := []taggedImage{
+		{tag: "v1", digest: "sha256:1", created: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{tag: "v2", digest: "sha256:2", created: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{tag: "v3", digest: "sha256:3", created: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{tag: "stable", digest: "sha256:2", created: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{tag: "latest", digest: "sha256:3", created: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{tag: "nodate", digest: "sha256:4"},
+	}
+	tags := func(images []taggedImage) []string {
+		var tags []string
+		for _, img := range images {
+			tags = append(tags, img.tag)
+		}
+		return tags
+	}
+
+	testCases := []struct {
+		doc       string
+		keep      int
+		olderThan time.Duration
+		expected  []string
+	}{
+		{doc: "keep", keep: 1, expected: []string{"v2", "stable", "v1"}},
+		{doc: "keep distinct images", keep: 2, expected: []string{"v1"}},
+		{doc: "keep all", keep: 3},
+		{doc: "older than", olderThan: 48 * time.Hour, expected: []string{"v2", "stable", "v1"}},
+		{doc: "older than and keep", olderThan: 48 * time.Hour, keep: 2, expected: []string{"v1"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.doc, func(t *testing.T) {
+			expired := selectExpired(images, tc.keep, tc.olderThan, now)
+			assert.Check(t, is.DeepEqual(tags(expired), tc.expected))
+		})
+	}
+}
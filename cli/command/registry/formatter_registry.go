@@ -0,0 +1,101 @@
+package registry
+
+import (
+	"github.com/docker/cli/cli/command/formatter"
+)
+
+const (
+	defaultTagsTableFormat    = "table {{.Repository}}\t{{.Tag}}"
+	defaultCatalogTableFormat = "table {{.Repository}}"
+
+	repositoryHeader = "REPOSITORY"
+	tagHeader        = "TAG"
+)
+
+// newTagsFormat returns a Format for rendering using a tagContext.
+func newTagsFormat(source string) formatter.Format {
+	switch source {
+	case "", formatter.TableFormatKey:
+		return defaultTagsTableFormat
+	}
+	return formatter.Format(source)
+}
+
+// newCatalogFormat returns a Format for rendering using a repositoryContext.
+func newCatalogFormat(source string) formatter.Format {
+	switch source {
+	case "", formatter.TableFormatKey:
+		return defaultCatalogTableFormat
+	}
+	return formatter.Format(source)
+}
+
+// tagsFormatWrite writes the tags of a repository using the context.
+func tagsFormatWrite(fmtCtx formatter.Context, repository string, tags []string) error {
+	tagsCtx := &tagContext{
+		HeaderContext: formatter.HeaderContext{
+			Header: formatter.SubHeaderContext{
+				"Repository": repositoryHeader,
+				"Tag":        tagHeader,
+			},
+		},
+	}
+	return fmtCtx.Write(tagsCtx, func(format func(subContext formatter.SubContext) error) error {
+		for _, tag := range tags {
+			if err := format(&tagContext{repository: repository, tag: tag}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// catalogFormatWrite writes the repositories of a registry using the context.
+func catalogFormatWrite(fmtCtx formatter.Context, repositories []string) error {
+	repoCtx := &repositoryContext{
+		HeaderContext: formatter.HeaderContext{
+			Header: formatter.SubHeaderContext{
+				"Repository": repositoryHeader,
+			},
+		},
+	}
+	return fmtCtx.Write(repoCtx, func(format func(subContext formatter.SubContext) error) error {
+		for _, repo := range repositories {
+			if err := format(&repositoryContext{repository: repo}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+type tagContext struct {
+	formatter.HeaderContext
+	repository string
+	tag        string
+}
+
+func (c *tagContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *tagContext) Repository() string {
+	return c.repository
+}
+
+func (c *tagContext) Tag() string {
+	return c.tag
+}
+
+type repositoryContext struct {
+	formatter.HeaderContext
+	repository string
+}
+
+func (c *repositoryContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *repositoryContext) Repository() string {
+	return c.repository
+}
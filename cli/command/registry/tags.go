@@ -0,0 +1,63 @@
+package registry
+
+import (
+	"context"
+	"errors"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+type tagsOptions struct {
+	repository string
+	format     string
+	insecure   bool
+}
+
+// newTagsCommand creates a new `docker registry tags` command
+func newTagsCommand(dockerCLI command.Cli) *cobra.Command {
+	var options tagsOptions
+
+	cmd := &cobra.Command{
+		Use:   "tags [OPTIONS] REPOSITORY",
+		Short: "List the tags of a repository in a registry",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.repository = args[0]
+			return runTags(cmd.Context(), dockerCLI, options)
+		},
+		ValidArgsFunction:     cobra.NoFileCompletions,
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&options.format, "format", "", flagsHelper.FormatHelp)
+	flags.BoolVar(&options.insecure, "insecure", false, "Allow communication with an insecure registry")
+
+	return cmd
+}
+
+func runTags(ctx context.Context, dockerCLI command.Cli, options tagsOptions) error {
+	repo, err := reference.ParseNormalizedNamed(options.repository)
+	if err != nil {
+		return err
+	}
+	if !reference.IsNameOnly(repo) {
+		return errors.New("repository must not have a tag or digest")
+	}
+
+	tags, err := command.NewRegistryClient(dockerCLI, options.insecure).ListTags(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	tagsCtx := formatter.Context{
+		Output: dockerCLI.Out(),
+		Format: newTagsFormat(options.format),
+	}
+	return tagsFormatWrite(tagsCtx, reference.FamiliarName(repo), tags)
+}
@@ -0,0 +1,71 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/golden"
+)
+
+func TestTagsErrors(t *testing.T) {
+	testCases := []struct {
+		args          []string
+		expectedError string
+	}{
+		{
+			args:          []string{},
+			expectedError: "'tags' requires 1 argument",
+		},
+		{
+			args:          []string{"alpine:latest"},
+			expectedError: "repository must not have a tag or digest",
+		},
+		{
+			args:          []string{"UPPERCASE"},
+			expectedError: "repository name (library/UPPERCASE) must be lowercase",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.expectedError, func(t *testing.T) {
+			cli := test.NewFakeCli(nil)
+			cli.SetRegistryClient(&fakeRegistryClient{})
+			cmd := newTagsCommand(cli)
+			cmd.SetArgs(tc.args)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			assert.Check(t, is.ErrorContains(cmd.Execute(), tc.expectedError))
+		})
+	}
+}
+
+func TestTags(t *testing.T) {
+	testCases := []struct {
+		name string
+		args []string
+	}{
+		{name: "table", args: []string{"localhost:5000/team/app"}},
+		{name: "format", args: []string{"--format", "{{.Repository}}:{{.Tag}}", "localhost:5000/team/app"}},
+		{name: "json", args: []string{"--format", "json", "localhost:5000/team/app"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := test.NewFakeCli(nil)
+			cli.SetRegistryClient(&fakeRegistryClient{
+				listTagsFunc: func(_ context.Context, ref reference.Named) ([]string, error) {
+					assert.Check(t, is.Equal(ref.String(), "localhost:5000/team/app"))
+					return []string{"1.0", "1.1", "latest"}, nil
+				},
+			})
+			cmd := newTagsCommand(cli)
+			cmd.SetArgs(tc.args)
+			assert.NilError(t, cmd.Execute())
+			golden.Assert(t, cli.OutBuffer().String(), fmt.Sprintf("tags-command-success.%s.golden", tc.name))
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package command
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/config/types"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestRegistryAuthResolver(t *testing.T) {
+	tests := []struct {
+		doc          string
+		stored       []types.AuthConfig
+		indexName    string
+		expectedUser string
+	}{
+		{
+			doc:          "Docker Hub credentials stored by docker login",
+			stored:       []types.AuthConfig{{ServerAddress: authConfigKey, Username: "login-user", Password: "p"}},
+			indexName:    "docker.io",
+			expectedUser: "login-user",
+		},
+		{
+			// Credentials stored under "docker.io" were not found before
+			// either, as the config file looks up Docker Hub by authConfigKey.
+			doc:       "Docker Hub credentials stored under docker.io",
+			stored:    []types.AuthConfig{{ServerAddress: "docker.io", Username: "legacy-user", Password: "p"}},
+			indexName: "docker.io",
+		},
+		{
+			doc:          "registry credentials stored by hostname",
+			stored:       []types.AuthConfig{{ServerAddress: "registry.example.com", Username: "registry-user", Password: "p"}},
+			indexName:    "registry.example.com",
+			expectedUser: "registry-user",
+		},
+		{
+			doc:          "registry credentials stored by URL",
+			stored:       []types.AuthConfig{{ServerAddress: "https://registry.example.com/v1/", Username: "registry-user", Password: "p"}},
+			indexName:    "registry.example.com",
+			expectedUser: "registry-user",
+		},
+		{
+			doc:       "Docker Hub credentials are not used for other registries",
+			stored:    []types.AuthConfig{{ServerAddress: authConfigKey, Username: "login-user", Password: "p"}},
+			indexName: "registry.example.com",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			cfg := configfile.New(filepath.Join(t.TempDir(), "config.json"))
+			for _, a := range tc.stored {
+				cfg.AuthConfigs[a.ServerAddress] = a
+			}
+			a := registryAuthResolver(cfg)(context.Background(), tc.indexName)
+			assert.Check(t, is.Equal(a.Username, tc.expectedUser))
+
+			// The manifest commands looked up the credentials by the name
+			// of the index; they must find the same credentials.
+			previous, err := cfg.GetAuthConfig(tc.indexName)
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(a.Username, previous.Username))
+		})
+	}
+}
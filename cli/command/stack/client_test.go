@@ -2,6 +2,7 @@
 
 import (
 	"context"
+	"errors"
 	"strings"
 
 	"github.com/docker/cli/cli/compose/convert"
@@ -35,6 +36,8 @@ type fakeClient struct {
 	networkRemoveFunc func(networkID string) error
 	secretRemoveFunc  func(secretID string) (client.SecretRemoveResult, error)
 	configRemoveFunc  func(configID string) (client.ConfigRemoveResult, error)
+	secretInspectFunc func(secretID string) (client.SecretInspectResult, error)
+	configInspectFunc func(configID string) (client.ConfigInspectResult, error)
 }
 
 func (*fakeClient) ServerVersion(context.Context, client.ServerVersionOptions) (client.ServerVersionResult, error) {
@@ -172,6 +175,20 @@ func (cli *fakeClient) ConfigRemove(_ context.Context, configID string, _ client
 	return client.ConfigRemoveResult{}, nil
 }
 
+func (cli *fakeClient) SecretInspect(_ context.Context, secretID string, _ client.SecretInspectOptions) (client.SecretInspectResult, error) {
+	if cli.secretInspectFunc != nil {
+		return cli.secretInspectFunc(secretID)
+	}
+	return client.SecretInspectResult{}, notFound{errors.New("no such secret: " + secretID)}
+}
+
+func (cli *fakeClient) ConfigInspect(_ context.Context, configID string, _ client.ConfigInspectOptions) (client.ConfigInspectResult, error) {
+	if cli.configInspectFunc != nil {
+		return cli.configInspectFunc(configID)
+	}
+	return client.ConfigInspectResult{}, notFound{errors.New("no such config: " + configID)}
+}
+
 func (*fakeClient) ServiceInspect(_ context.Context, serviceID string, _ client.ServiceInspectOptions) (client.ServiceInspectResult, error) {
 	return client.ServiceInspectResult{
 		Service: swarm.Service{
@@ -40,6 +40,7 @@ func newStackCommand(dockerCLI command.Cli) *cobra.Command {
 		newListCommand(dockerCLI),
 		newPsCommand(dockerCLI),
 		newRemoveCommand(dockerCLI),
+		newRollbackCommand(dockerCLI),
 		newServicesCommand(dockerCLI),
 		newConfigCommand(dockerCLI),
 	)
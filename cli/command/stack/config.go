@@ -15,6 +15,7 @@
 // configOptions holds docker stack config options
 type configOptions struct {
 	composeFiles      []string
+	profiles          []string
 	skipInterpolation bool
 }
 
@@ -31,7 +32,7 @@ func newConfigCommand(dockerCLI command.Cli) *cobra.Command {
 				return err
 			}
 
-			cfg, err := outputConfig(configDetails, opts.skipInterpolation)
+			cfg, err := outputConfig(configDetails, opts.skipInterpolation, opts.profiles)
 			if err != nil {
 				return err
 			}
@@ -45,14 +46,17 @@ func newConfigCommand(dockerCLI command.Cli) *cobra.Command {
 
 	flags := cmd.Flags()
 	flags.StringSliceVarP(&opts.composeFiles, "compose-file", "c", []string{}, `Path to a Compose file, or "-" to read from stdin`)
+	flags.StringSliceVar(&opts.profiles, "profile", nil, "Specify a profile to enable")
 	flags.BoolVar(&opts.skipInterpolation, "skip-interpolation", false, "Skip interpolation and output only merged config")
 	return cmd
 }
 
 // outputConfig returns the merged and interpolated config file
-func outputConfig(configFiles composetypes.ConfigDetails, skipInterpolation bool) (string, error) {
+func outputConfig(configFiles composetypes.ConfigDetails, skipInterpolation bool, profiles []string) (string, error) {
+	profiles = getProfiles(profiles, configFiles.Environment)
 	optsFunc := func(opts *composeLoader.Options) {
 		opts.SkipInterpolation = skipInterpolation
+		opts.Profiles = profiles
 	}
 	config, err := composeLoader.Load(configFiles, optsFunc)
 	if err != nil {
@@ -91,9 +91,47 @@ func TestConfigMergeInterpolation(t *testing.T) {
 				Environment: map[string]string{
 					"VERSION": "1.0",
 				},
-			}, tc.skipInterpolation)
+			}, tc.skipInterpolation, nil)
 			assert.Check(t, err)
 			assert.Equal(t, tc.expected, actual)
 		})
 	}
 }
+
+func TestConfigProfiles(t *testing.T) {
+	configData, err := loader.ParseYAML([]byte(`version: "3.14"
+services:
+  foo:
+    image: busybox:latest
+  debug:
+    image: busybox:latest
+    profiles: [debug]
+`))
+	assert.NilError(t, err)
+	configDetails := composetypes.ConfigDetails{
+		ConfigFiles: []composetypes.ConfigFile{
+			{Config: configData, Filename: "firstConfig"},
+		},
+	}
+
+	actual, err := outputConfig(configDetails, false, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, actual, `version: "3.14"
+services:
+  foo:
+    image: busybox:latest
+`)
+
+	configDetails.Environment = map[string]string{"COMPOSE_PROFILES": "debug"}
+	actual, err = outputConfig(configDetails, false, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, actual, `version: "3.14"
+services:
+  debug:
+    image: busybox:latest
+    profiles:
+      - debug
+  foo:
+    image: busybox:latest
+`)
+}
@@ -18,12 +18,15 @@
 // deployOptions holds docker stack deploy options
 type deployOptions struct {
 	composefiles     []string
+	profiles         []string
 	namespace        string
 	resolveImage     string
 	sendRegistryAuth bool
 	prune            bool
 	detach           bool
-	quiet            bool
+	dryRun           bool
+	format           string
+	waitOptions
 }
 
 func newDeployCommand(dockerCLI command.Cli) *cobra.Command {
@@ -56,6 +59,7 @@ func newDeployCommand(dockerCLI command.Cli) *cobra.Command {
 	// ".yaml" and ".yml" file-extensions, but this doesn't appear to be supported
 	// by other shells.
 	_ = cmd.MarkFlagFilename("compose-file", "yaml", "yml")
+	flags.StringSliceVar(&opts.profiles, "profile", nil, "Specify a profile to enable")
 
 	flags.BoolVar(&opts.sendRegistryAuth, "with-registry-auth", false, "Send registry authentication details to Swarm agents")
 	flags.BoolVar(&opts.prune, "prune", false, "Prune services that are no longer referenced")
@@ -65,6 +69,10 @@ func newDeployCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.SetAnnotation("resolve-image", "version", []string{"1.30"})
 	flags.BoolVarP(&opts.detach, "detach", "d", true, "Exit immediately instead of waiting for the stack services to converge")
 	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Suppress progress output")
+	flags.DurationVar(&opts.timeout, "timeout", 0, "Maximum time to wait for the stack services to converge (default no timeout)")
+	flags.StringVar(&opts.report, "report", "", "Write the final state of the stack services as JSON to a file")
+	flags.BoolVar(&opts.dryRun, "dry-run", false, "Show the changes that would be made, without deploying the stack")
+	flags.StringVar(&opts.format, "format", "", `Format the output of --dry-run ("json")`)
 	return cmd
 }
 
@@ -86,7 +94,15 @@ func runDeploy(ctx context.Context, dockerCLI command.Cli, flags *pflag.FlagSet,
 		return fmt.Errorf("invalid option %s for flag --resolve-image", opts.resolveImage)
 	}
 
-	if opts.detach && !flags.Changed("detach") {
+	if opts.format != "" && !opts.dryRun {
+		return errors.New("--format can only be used with --dry-run")
+	}
+
+	if opts.detach && (opts.timeout != 0 || opts.report != "") {
+		return errors.New("--timeout and --report can only be used with --detach=false")
+	}
+
+	if opts.detach && !flags.Changed("detach") && !opts.dryRun {
 		_, _ = fmt.Fprintln(dockerCLI.Err(), "Since --detach=false was not specified, tasks will be created in the background.\n"+
 			"In a future release, --detach=false will become the default.")
 	}
@@ -2,12 +2,10 @@
 
 import (
 	"context"
-	"errors"
 	"fmt"
 
 	"github.com/containerd/errdefs"
 	"github.com/docker/cli/cli/command"
-	"github.com/docker/cli/cli/command/service"
 	"github.com/docker/cli/cli/compose/convert"
 	composetypes "github.com/docker/cli/cli/compose/types"
 	"github.com/moby/moby/api/types/container"
@@ -23,6 +21,10 @@ func deployCompose(ctx context.Context, dockerCli command.Cli, opts *deployOptio
 
 	namespace := convert.NewNamespace(opts.namespace)
 
+	if opts.dryRun {
+		return dryRunCompose(ctx, dockerCli, opts, namespace, config)
+	}
+
 	if opts.prune {
 		services := map[string]struct{}{}
 		for _, svc := range config.Services {
@@ -70,7 +72,7 @@ func deployCompose(ctx context.Context, dockerCli command.Cli, opts *deployOptio
 		return nil
 	}
 
-	return waitOnServices(ctx, dockerCli, serviceIDs, opts.quiet)
+	return waitOnStack(ctx, dockerCli, opts.namespace, serviceIDs, opts.waitOptions)
 }
 
 func getServicesDeclaredNetworks(serviceConfigs []composetypes.ServiceConfig) map[string]struct{} {
@@ -300,13 +302,3 @@ func deployServices(ctx context.Context, dockerCLI command.Cli, services map[str
 
 	return serviceIDs, nil
 }
-
-func waitOnServices(ctx context.Context, dockerCli command.Cli, serviceIDs []string, quiet bool) error {
-	var errs []error
-	for _, serviceID := range serviceIDs {
-		if err := service.WaitOnService(ctx, dockerCli, serviceID, quiet); err != nil {
-			errs = append(errs, fmt.Errorf("%s: %w", serviceID, err))
-		}
-	}
-	return errors.Join(errs...)
-}
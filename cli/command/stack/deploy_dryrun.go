@@ -0,0 +1,365 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package stack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+
+	"github.com/containerd/errdefs"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/compose/convert"
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"github.com/moby/moby/api/types/swarm"
+	"github.com/moby/moby/client"
+)
+
+// Actions performed on a resource by stack deploy.
+const (
+	actionCreate    = "create"
+	actionUpdate    = "update"
+	actionRemove    = "remove"
+	actionUnchanged = "unchanged"
+)
+
+// deployPlan describes the changes that stack deploy would make.
+type deployPlan struct {
+	Services []resourceChange `json:"services"`
+	Networks []resourceChange `json:"networks"`
+	Secrets  []resourceChange `json:"secrets"`
+	Configs  []resourceChange `json:"configs"`
+}
+
+// resourceChange describes the change to a single service, network, secret,
+// or config.
+type resourceChange struct {
+	Name    string        `json:"name"`
+	Action  string        `json:"action"`
+	Changes []fieldChange `json:"changes,omitempty"`
+}
+
+// fieldChange describes the change to a single field of a resource's spec.
+// Field is the path of the field, using the field names of the API types.
+type fieldChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old,omitempty"`
+	New   any    `json:"new,omitempty"`
+}
+
+// dryRunClient adds the secrets and configs that would be created by the
+// deploy to the results of SecretList and ConfigList, so that services that
+// reference them can be converted without creating them.
+type dryRunClient struct {
+	client.APIClient
+	secrets []swarm.SecretSpec
+	configs []swarm.ConfigSpec
+}
+
+func (c *dryRunClient) SecretList(ctx context.Context, options client.SecretListOptions) (client.SecretListResult, error) {
+	res, err := c.APIClient.SecretList(ctx, options)
+	if err != nil {
+		return res, err
+	}
+	for _, spec := range c.secrets {
+		if !slices.ContainsFunc(res.Items, func(s swarm.Secret) bool { return s.Spec.Name == spec.Name }) {
+			res.Items = append(res.Items, swarm.Secret{Spec: spec})
+		}
+	}
+	return res, nil
+}
+
+func (c *dryRunClient) ConfigList(ctx context.Context, options client.ConfigListOptions) (client.ConfigListResult, error) {
+	res, err := c.APIClient.ConfigList(ctx, options)
+	if err != nil {
+		return res, err
+	}
+	for _, spec := range c.configs {
+		if !slices.ContainsFunc(res.Items, func(c swarm.Config) bool { return c.Spec.Name == spec.Name }) {
+			res.Items = append(res.Items, swarm.Config{Spec: spec})
+		}
+	}
+	return res, nil
+}
+
+// dryRunCompose computes the changes that deployCompose would make, and
+// writes them to the CLI's output, without calling any API that modifies
+// the swarm.
+func dryRunCompose(ctx context.Context, dockerCLI command.Cli, opts *deployOptions, namespace convert.Namespace, config *composetypes.Config) error {
+	apiClient := dockerCLI.Client()
+
+	serviceNetworks := getServicesDeclaredNetworks(config.Services)
+	networks, externalNetworks := convert.Networks(namespace, config.Networks, serviceNetworks)
+	if err := validateExternalNetworks(ctx, apiClient, externalNetworks); err != nil {
+		return err
+	}
+	secrets, err := convert.Secrets(namespace, config.Secrets)
+	if err != nil {
+		return err
+	}
+	configs, err := convert.Configs(namespace, config.Configs)
+	if err != nil {
+		return err
+	}
+	services, err := convert.Services(ctx, namespace, config, &dryRunClient{
+		APIClient: apiClient,
+		secrets:   secrets,
+		configs:   configs,
+	})
+	if err != nil {
+		return err
+	}
+
+	var plan deployPlan
+	if plan.Services, err = planServices(ctx, apiClient, namespace, services, opts.resolveImage, opts.prune); err != nil {
+		return err
+	}
+	if plan.Networks, err = planNetworks(ctx, apiClient, namespace, networks); err != nil {
+		return err
+	}
+	if plan.Secrets, err = planSecrets(ctx, apiClient, secrets); err != nil {
+		return err
+	}
+	if plan.Configs, err = planConfigs(ctx, apiClient, configs); err != nil {
+		return err
+	}
+	return writePlan(dockerCLI.Out(), plan, opts.format)
+}
+
+func planServices(ctx context.Context, apiClient client.APIClient, namespace convert.Namespace, services map[string]swarm.ServiceSpec, resolveImage string, prune bool) ([]resourceChange, error) {
+	existingServices, err := getStackServices(ctx, apiClient, namespace.Name())
+	if err != nil {
+		return nil, err
+	}
+	existingServiceMap := make(map[string]swarm.Service)
+	for _, svc := range existingServices.Items {
+		existingServiceMap[svc.Spec.Name] = svc
+	}
+
+	var changes []resourceChange
+	for _, internalName := range slices.Sorted(maps.Keys(services)) {
+		serviceSpec := services[internalName]
+		name := namespace.Scope(internalName)
+		svc, exists := existingServiceMap[name]
+		if !exists {
+			changes = append(changes, resourceChange{Name: name, Action: actionCreate})
+			continue
+		}
+
+		// Apply the same changes to the spec as deployServices does, so that
+		// only actual changes are reported. When resolving images, the digest
+		// of an image that did not change in the compose file is assumed to
+		// be unchanged.
+		image := serviceSpec.TaskTemplate.ContainerSpec.Image
+		if image == svc.Spec.Labels[convert.LabelImage] {
+			serviceSpec.TaskTemplate.ContainerSpec.Image = svc.Spec.TaskTemplate.ContainerSpec.Image
+			if resolveImage != resolveImageNever && serviceSpec.TaskTemplate.Placement != nil && svc.Spec.TaskTemplate.Placement != nil {
+				serviceSpec.TaskTemplate.Placement.Platforms = svc.Spec.TaskTemplate.Placement.Platforms
+			}
+		}
+		serviceSpec.TaskTemplate.ForceUpdate = svc.Spec.TaskTemplate.ForceUpdate
+
+		fieldChanges, err := diffSpecs(svc.Spec, serviceSpec)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, newResourceChange(name, fieldChanges))
+	}
+
+	if prune {
+		for _, name := range slices.Sorted(maps.Keys(existingServiceMap)) {
+			if _, exists := services[namespace.Descope(name)]; !exists {
+				changes = append(changes, resourceChange{Name: name, Action: actionRemove})
+			}
+		}
+	}
+	return changes, nil
+}
+
+func planNetworks(ctx context.Context, apiClient client.APIClient, namespace convert.Namespace, networks map[string]client.NetworkCreateOptions) ([]resourceChange, error) {
+	existingNetworks, err := getStackNetworks(ctx, apiClient, namespace.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	// Existing networks are not updated by stack deploy.
+	var changes []resourceChange
+	for _, name := range slices.Sorted(maps.Keys(networks)) {
+		action := actionCreate
+		for _, nw := range existingNetworks.Items {
+			if nw.Name == name {
+				action = actionUnchanged
+				break
+			}
+		}
+		changes = append(changes, resourceChange{Name: name, Action: action})
+	}
+	return changes, nil
+}
+
+func planSecrets(ctx context.Context, apiClient client.APIClient, secrets []swarm.SecretSpec) ([]resourceChange, error) {
+	var changes []resourceChange
+	for _, secretSpec := range secrets {
+		res, err := apiClient.SecretInspect(ctx, secretSpec.Name, client.SecretInspectOptions{})
+		switch {
+		case err == nil:
+			// The secret's data is not returned by the API, so cannot be
+			// compared.
+			secretSpec.Data = nil
+			fieldChanges, err := diffSpecs(res.Secret.Spec, secretSpec)
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, newResourceChange(secretSpec.Name, fieldChanges))
+		case errdefs.IsNotFound(err):
+			changes = append(changes, resourceChange{Name: secretSpec.Name, Action: actionCreate})
+		default:
+			return nil, err
+		}
+	}
+	return changes, nil
+}
+
+func planConfigs(ctx context.Context, apiClient client.APIClient, configs []swarm.ConfigSpec) ([]resourceChange, error) {
+	var changes []resourceChange
+	for _, configSpec := range configs {
+		res, err := apiClient.ConfigInspect(ctx, configSpec.Name, client.ConfigInspectOptions{})
+		switch {
+		case err == nil:
+			fieldChanges, err := diffSpecs(res.Config.Spec, configSpec)
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, newResourceChange(configSpec.Name, fieldChanges))
+		case errdefs.IsNotFound(err):
+			changes = append(changes, resourceChange{Name: configSpec.Name, Action: actionCreate})
+		default:
+			return nil, err
+		}
+	}
+	return changes, nil
+}
+
+func newResourceChange(name string, fieldChanges []fieldChange) resourceChange {
+	if len(fieldChanges) == 0 {
+		return resourceChange{Name: name, Action: actionUnchanged}
+	}
+	return resourceChange{Name: name, Action: actionUpdate, Changes: fieldChanges}
+}
+
+// diffSpecs returns the fields that differ between two specs. Specs are
+// compared by their JSON representation, so that fields that are omitted
+// when empty are considered equal to their zero value.
+func diffSpecs(oldSpec, newSpec any) ([]fieldChange, error) {
+	oldFields, err := flattenSpec(oldSpec)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := flattenSpec(newSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := slices.Collect(maps.Keys(oldFields))
+	for k := range newFields {
+		if _, ok := oldFields[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+
+	var changes []fieldChange
+	for _, k := range keys {
+		oldValue, newValue := oldFields[k], newFields[k]
+		if !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, fieldChange{Field: k, Old: oldValue, New: newValue})
+		}
+	}
+	return changes, nil
+}
+
+// flattenSpec returns the fields of a spec by their path, for example,
+// "TaskTemplate.ContainerSpec.Env[0]".
+func flattenSpec(spec any) (map[string]any, error) {
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	fields := make(map[string]any)
+	flattenValue(fields, "", v)
+	return fields, nil
+}
+
+func flattenValue(fields map[string]any, prefix string, v any) {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, elem := range val {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+			flattenValue(fields, key, elem)
+		}
+	case []any:
+		for i, elem := range val {
+			flattenValue(fields, prefix+"["+strconv.Itoa(i)+"]", elem)
+		}
+	default:
+		fields[prefix] = val
+	}
+}
+
+func writePlan(out io.Writer, plan deployPlan, format string) error {
+	switch format {
+	case "":
+		writePlanText(out, plan)
+		return nil
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "    ")
+		return enc.Encode(plan)
+	default:
+		return fmt.Errorf("invalid format %q: only json is supported", format)
+	}
+}
+
+func writePlanText(out io.Writer, plan deployPlan) {
+	sections := []struct {
+		kind    string
+		changes []resourceChange
+	}{
+		{kind: "service", changes: plan.Services},
+		{kind: "network", changes: plan.Networks},
+		{kind: "secret", changes: plan.Secrets},
+		{kind: "config", changes: plan.Configs},
+	}
+	for _, section := range sections {
+		for _, c := range section.changes {
+			_, _ = fmt.Fprintf(out, "%-9s %s %s\n", c.Action, section.kind, c.Name)
+			for _, fc := range c.Changes {
+				_, _ = fmt.Fprintf(out, "    %s: %s => %s\n", fc.Field, formatPlanValue(fc.Old), formatPlanValue(fc.New))
+			}
+		}
+	}
+}
+
+func formatPlanValue(v any) string {
+	if v == nil {
+		return "<none>"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
@@ -0,0 +1,150 @@
+package stack
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/cli/cli/compose/convert"
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"github.com/docker/cli/internal/test"
+	"github.com/moby/moby/api/types/swarm"
+	"github.com/moby/moby/client"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
+)
+
+func TestDryRunCompose(t *testing.T) {
+	namespace := convert.NewNamespace("mystack")
+
+	foo, bar := "foo", "bar"
+	existing, err := convert.Service(namespace, composetypes.ServiceConfig{
+		Name:  "web",
+		Image: "nginx:1",
+		Environment: composetypes.MappingWithEquals{
+			"FOO": &foo,
+		},
+	}, nil, nil, nil, nil)
+	assert.NilError(t, err)
+	existing.TaskTemplate.ContainerSpec.Image = "nginx:1@sha256:deadbeef"
+	existing.TaskTemplate.ForceUpdate = 3
+
+	var mutated bool
+	fakeCli := test.NewFakeCli(&fakeClient{
+		serviceListFunc: func(client.ServiceListOptions) (client.ServiceListResult, error) {
+			return client.ServiceListResult{
+				Items: []swarm.Service{
+					{ID: "ID-web", Spec: existing},
+					serviceFromName("mystack_old"),
+				},
+			}, nil
+		},
+		configInspectFunc: func(string) (client.ConfigInspectResult, error) {
+			return client.ConfigInspectResult{
+				Config: swarm.Config{
+					Spec: swarm.ConfigSpec{
+						Annotations: swarm.Annotations{
+							Name:   "mystack_conf",
+							Labels: map[string]string{convert.LabelNamespace: "mystack"},
+						},
+						Data: []byte("old"),
+					},
+				},
+			}, nil
+		},
+		serviceUpdateFunc: func(string, client.ServiceUpdateOptions) (client.ServiceUpdateResult, error) {
+			mutated = true
+			return client.ServiceUpdateResult{}, nil
+		},
+	})
+
+	configFile := fs.NewFile(t, "config", fs.WithContent("new"))
+	config := &composetypes.Config{
+		Services: []composetypes.ServiceConfig{
+			{
+				Name:  "web",
+				Image: "nginx:1",
+				Environment: composetypes.MappingWithEquals{
+					"FOO": &bar,
+				},
+				Secrets: []composetypes.ServiceSecretConfig{{Source: "token"}},
+			},
+			{
+				Name:  "db",
+				Image: "postgres:16",
+			},
+		},
+		Secrets: map[string]composetypes.SecretConfig{
+			"token": {Driver: "vault"},
+		},
+		Configs: map[string]composetypes.ConfigObjConfig{
+			"conf": {File: configFile.Path()},
+		},
+	}
+
+	opts := &deployOptions{namespace: "mystack", prune: true, resolveImage: resolveImageAlways}
+	err = dryRunCompose(context.Background(), fakeCli, opts, namespace, config)
+	assert.NilError(t, err)
+	assert.Check(t, !mutated)
+	assert.Check(t, is.Equal(fakeCli.OutBuffer().String(), `create    service mystack_db
+update    service mystack_web
+    TaskTemplate.ContainerSpec.Env[0]: "FOO=foo" => "FOO=bar"
+    TaskTemplate.ContainerSpec.Secrets[0].File.GID: <none> => "0"
+    TaskTemplate.ContainerSpec.Secrets[0].File.Mode: <none> => 292
+    TaskTemplate.ContainerSpec.Secrets[0].File.Name: <none> => "token"
+    TaskTemplate.ContainerSpec.Secrets[0].File.UID: <none> => "0"
+    TaskTemplate.ContainerSpec.Secrets[0].SecretID: <none> => ""
+    TaskTemplate.ContainerSpec.Secrets[0].SecretName: <none> => "mystack_token"
+remove    service mystack_old
+create    network mystack_default
+create    secret mystack_token
+update    config mystack_conf
+    Data: "b2xk" => "bmV3"
+`))
+}
+
+func TestDryRunComposeJSON(t *testing.T) {
+	namespace := convert.NewNamespace("mystack")
+	fakeCli := test.NewFakeCli(&fakeClient{})
+
+	config := &composetypes.Config{
+		Services: []composetypes.ServiceConfig{{Name: "web", Image: "nginx:1"}},
+	}
+
+	opts := &deployOptions{namespace: "mystack", format: "json"}
+	err := dryRunCompose(context.Background(), fakeCli, opts, namespace, config)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(fakeCli.OutBuffer().String(), `{
+    "services": [
+        {
+            "name": "mystack_web",
+            "action": "create"
+        }
+    ],
+    "networks": [
+        {
+            "name": "mystack_default",
+            "action": "create"
+        }
+    ],
+    "secrets": null,
+    "configs": null
+}
+`))
+}
+
+func TestDiffSpecs(t *testing.T) {
+	changes, err := diffSpecs(
+		swarm.ServiceSpec{
+			Annotations: swarm.Annotations{Name: "foo", Labels: map[string]string{"a": "1", "b": "2"}},
+		},
+		swarm.ServiceSpec{
+			Annotations: swarm.Annotations{Name: "foo", Labels: map[string]string{"a": "1", "c": "3"}},
+		},
+	)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(changes, []fieldChange{
+		{Field: "Labels.b", Old: "2"},
+		{Field: "Labels.c", New: "3"},
+	}))
+}
@@ -0,0 +1,427 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package stack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/internal/jsonstream"
+	"github.com/moby/moby/api/types/swarm"
+	"github.com/moby/moby/client"
+	"github.com/moby/moby/client/pkg/progress"
+	"github.com/moby/moby/client/pkg/streamformatter"
+)
+
+// Final states of a service after waiting for the stack to converge.
+const (
+	serviceStateConverged  = "converged"
+	serviceStateRolledBack = "rolled back"
+	serviceStatePaused     = "paused"
+	serviceStateFailed     = "failed"
+	serviceStateTimedOut   = "timed out"
+)
+
+const (
+	// taskFailureLimit is the number of failed tasks, per desired task, after
+	// which a service is considered to have failed.
+	taskFailureLimit = 3
+
+	// defaultMonitor is the time tasks must be stable before a service is
+	// considered converged, if the service does not set a monitor period in
+	// its update config.
+	defaultMonitor = 5 * time.Second
+)
+
+// pollInterval is the interval at which the state of the stack's services
+// is polled.
+var pollInterval = 200 * time.Millisecond
+
+// waitOptions holds the options for waiting on a stack to converge.
+type waitOptions struct {
+	quiet   bool
+	timeout time.Duration
+	report  string
+
+	// rollback indicates that the services are rolled back, instead of
+	// updated, in which case a completed rollback means the service
+	// converged.
+	rollback bool
+}
+
+// stackReport describes the state of a stack's services after waiting for
+// the stack to converge.
+type stackReport struct {
+	Stack     string          `json:"stack"`
+	Converged bool            `json:"converged"`
+	Services  []serviceReport `json:"services"`
+}
+
+// serviceReport describes the state of a single service after waiting for
+// the stack to converge.
+type serviceReport struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	State        string `json:"state"`
+	RunningTasks uint64 `json:"runningTasks"`
+	DesiredTasks uint64 `json:"desiredTasks"`
+	FailedTasks  int    `json:"failedTasks"`
+	Message      string `json:"message,omitempty"`
+}
+
+// serviceWaiter tracks the convergence of a single service.
+type serviceWaiter struct {
+	report      serviceReport
+	rollback    bool
+	status      string
+	convergedAt time.Time
+	done        bool
+}
+
+// waitOnStack waits for the given services of a stack to converge, showing
+// the progress of all services in a single view. It returns an error if any
+// of the services did not converge; for example, because its update was
+// rolled back, its tasks kept failing, or the timeout expired.
+func waitOnStack(ctx context.Context, dockerCLI command.Cli, namespace string, serviceIDs []string, opts waitOptions) error {
+	waitCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if opts.timeout > 0 {
+		waitCtx, cancel = context.WithTimeout(waitCtx, opts.timeout)
+		defer cancel()
+	}
+
+	var report stackReport
+	errChan := make(chan error, 1)
+	pipeReader, pipeWriter := io.Pipe()
+	go func() {
+		var err error
+		report, err = stackProgress(waitCtx, dockerCLI.Client(), namespace, serviceIDs, opts.rollback, pipeWriter)
+		errChan <- err
+	}()
+
+	var err error
+	if opts.quiet {
+		go io.Copy(io.Discard, pipeReader)
+		err = <-errChan
+	} else {
+		err = jsonstream.Display(ctx, pipeReader, dockerCLI.Out())
+		if err == nil {
+			err = <-errChan
+		} else {
+			// Stop polling, and unblock the progress writer, which is no
+			// longer read from.
+			cancel()
+			_ = pipeReader.CloseWithError(err)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if opts.report != "" {
+		if err := writeStackReport(opts.report, report); err != nil {
+			return err
+		}
+	}
+	if report.Converged {
+		return nil
+	}
+
+	writeStackSummary(dockerCLI.Err(), report)
+	var failed int
+	for _, s := range report.Services {
+		if s.State != serviceStateConverged {
+			failed++
+		}
+	}
+	return fmt.Errorf("stack %s did not converge: %d out of %d services failed", namespace, failed, len(report.Services))
+}
+
+// stackProgress outputs progress information for the convergence of a
+// stack's services, and returns their final state. Services that did not
+// reach a final state before the context's deadline are reported as timed
+// out.
+func stackProgress(ctx context.Context, apiClient client.APIClient, namespace string, serviceIDs []string, rollback bool, progressWriter io.WriteCloser) (stackReport, error) {
+	defer progressWriter.Close()
+
+	progressOut := streamformatter.NewJSONProgressOutput(progressWriter, false)
+
+	waiters := make(map[string]*serviceWaiter, len(serviceIDs))
+	for _, id := range serviceIDs {
+		waiters[id] = &serviceWaiter{report: serviceReport{ID: id, Name: id}, rollback: rollback}
+	}
+
+	var (
+		// knownFailures holds the tasks that already failed when starting
+		// to wait, which are not counted as failures of the deploy.
+		knownFailures map[string]struct{}
+		overall       string
+	)
+	for {
+		services, err := apiClient.ServiceList(ctx, client.ServiceListOptions{
+			Filters: make(client.Filters).Add("id", serviceIDs...),
+			Status:  true,
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			return stackReport{}, err
+		}
+		tasks, err := apiClient.TaskList(ctx, client.TaskListOptions{
+			Filters: make(client.Filters).Add("service", serviceIDs...).Add("_up-to-date", "true"),
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			return stackReport{}, err
+		}
+
+		if knownFailures == nil {
+			knownFailures = make(map[string]struct{})
+			for _, t := range tasks.Items {
+				if isFailedTask(t) {
+					knownFailures[t.ID] = struct{}{}
+				}
+			}
+		}
+
+		tasksByService := make(map[string][]swarm.Task)
+		for _, t := range tasks.Items {
+			tasksByService[t.ServiceID] = append(tasksByService[t.ServiceID], t)
+		}
+
+		found := make(map[string]bool, len(services.Items))
+		for _, svc := range services.Items {
+			w, ok := waiters[svc.ID]
+			if !ok {
+				continue
+			}
+			found[svc.ID] = true
+			if !w.done {
+				w.update(svc, tasksByService[svc.ID], knownFailures)
+			}
+		}
+
+		var converged, done int
+		for _, id := range serviceIDs {
+			w := waiters[id]
+			if !found[id] && !w.done {
+				w.finish(serviceStateFailed, "service was removed")
+			}
+			if w.done {
+				done++
+				if w.report.State == serviceStateConverged {
+					converged++
+				}
+			}
+			if status := w.progress(); status != w.status {
+				w.status = status
+				_ = progressOut.WriteProgress(progress.Progress{ID: w.report.Name, Action: status})
+			}
+		}
+		if status := fmt.Sprintf("%d out of %d services converged", converged, len(serviceIDs)); status != overall {
+			overall = status
+			_ = progressOut.WriteProgress(progress.Progress{ID: "overall progress", Action: status})
+		}
+		if done == len(serviceIDs) {
+			break
+		}
+
+		select {
+		case <-time.After(pollInterval):
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	if err := ctx.Err(); err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		return stackReport{}, err
+	}
+
+	report := stackReport{Stack: namespace, Converged: true}
+	for _, id := range serviceIDs {
+		w := waiters[id]
+		if !w.done {
+			w.finish(serviceStateTimedOut, "timed out waiting for the service to converge")
+			_ = progressOut.WriteProgress(progress.Progress{ID: w.report.Name, Action: w.progress()})
+		}
+		if w.report.State != serviceStateConverged {
+			report.Converged = false
+		}
+		report.Services = append(report.Services, w.report)
+	}
+	slices.SortFunc(report.Services, func(a, b serviceReport) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return report, nil
+}
+
+// update updates the state of the service from its current state and tasks.
+func (w *serviceWaiter) update(svc swarm.Service, tasks []swarm.Task, knownFailures map[string]struct{}) {
+	w.report.Name = svc.Spec.Name
+	switch {
+	case svc.ServiceStatus != nil:
+		w.report.DesiredTasks = svc.ServiceStatus.DesiredTasks
+	case svc.Spec.Mode.Replicated != nil && svc.Spec.Mode.Replicated.Replicas != nil:
+		w.report.DesiredTasks = *svc.Spec.Mode.Replicated.Replicas
+	}
+
+	var (
+		running, completed uint64
+		pending            bool
+		lastFailure        swarm.Task
+	)
+	w.report.FailedTasks = 0
+	for _, t := range tasks {
+		switch {
+		case t.Status.State == swarm.TaskStateRunning && t.DesiredState == swarm.TaskStateRunning:
+			running++
+		case t.Status.State == swarm.TaskStateComplete:
+			completed++
+		case isFailedTask(t):
+			if _, ok := knownFailures[t.ID]; !ok {
+				w.report.FailedTasks++
+				if t.Status.Timestamp.After(lastFailure.Status.Timestamp) {
+					lastFailure = t
+				}
+			}
+		default:
+			pending = true
+		}
+	}
+	w.report.RunningTasks = running
+
+	var updateState swarm.UpdateState
+	if svc.UpdateStatus != nil {
+		updateState = svc.UpdateStatus.State
+		w.report.Message = svc.UpdateStatus.Message
+	}
+	if w.rollback && updateState == swarm.UpdateStateRollbackCompleted {
+		// The rollback was requested, so it completes like an update.
+		updateState = swarm.UpdateStateCompleted
+	}
+	switch updateState {
+	case swarm.UpdateStateRollbackCompleted:
+		w.finish(serviceStateRolledBack, w.report.Message)
+		return
+	case swarm.UpdateStatePaused, swarm.UpdateStateRollbackPaused:
+		w.finish(serviceStatePaused, w.report.Message)
+		return
+	}
+
+	// A service that is rolling back is left to complete its rollback,
+	// even if its tasks failed.
+	if updateState != swarm.UpdateStateRollbackStarted && w.report.FailedTasks >= taskFailureLimit*int(max(w.report.DesiredTasks, 1)) {
+		message := lastFailure.Status.Err
+		if message == "" {
+			message = lastFailure.Status.Message
+		}
+		w.finish(serviceStateFailed, message)
+		return
+	}
+
+	var converged bool
+	switch {
+	case updateState == swarm.UpdateStateUpdating || updateState == swarm.UpdateStateRollbackStarted:
+		converged = false
+	case svc.Spec.Mode.ReplicatedJob != nil:
+		total := uint64(1)
+		if job := svc.Spec.Mode.ReplicatedJob; job.TotalCompletions != nil {
+			total = *job.TotalCompletions
+		} else if job.MaxConcurrent != nil {
+			total = *job.MaxConcurrent
+		}
+		if !pending && running == 0 && completed >= total {
+			// Jobs stay done once they're done, so don't need to be verified.
+			w.finish(serviceStateConverged, "")
+			return
+		}
+	case svc.Spec.Mode.GlobalJob != nil:
+		if !pending && running == 0 && completed > 0 {
+			w.finish(serviceStateConverged, "")
+			return
+		}
+	default:
+		converged = running == w.report.DesiredTasks
+	}
+
+	if !converged {
+		w.convergedAt = time.Time{}
+		return
+	}
+	monitor := defaultMonitor
+	if svc.Spec.UpdateConfig != nil && svc.Spec.UpdateConfig.Monitor != 0 {
+		monitor = svc.Spec.UpdateConfig.Monitor
+	}
+	if w.convergedAt.IsZero() {
+		w.convergedAt = time.Now()
+	}
+	if time.Since(w.convergedAt) >= monitor {
+		w.finish(serviceStateConverged, "")
+	}
+}
+
+func (w *serviceWaiter) finish(state, message string) {
+	w.done = true
+	w.report.State = state
+	w.report.Message = message
+}
+
+// progress returns the progress line for the service.
+func (w *serviceWaiter) progress() string {
+	if w.done {
+		if w.report.Message != "" {
+			return w.report.State + ": " + w.report.Message
+		}
+		return w.report.State
+	}
+	status := fmt.Sprintf("%d/%d tasks running", w.report.RunningTasks, w.report.DesiredTasks)
+	if w.report.FailedTasks > 0 {
+		status += fmt.Sprintf(", %d failed", w.report.FailedTasks)
+	}
+	if !w.convergedAt.IsZero() {
+		status = "verifying: " + status
+	}
+	return status
+}
+
+func isFailedTask(t swarm.Task) bool {
+	return t.Status.State == swarm.TaskStateFailed || t.Status.State == swarm.TaskStateRejected
+}
+
+// writeStackSummary writes the final state of each service in the stack.
+func writeStackSummary(out io.Writer, report stackReport) {
+	tw := tabwriter.NewWriter(out, 10, 1, 3, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "SERVICE\tSTATE\tTASKS\tFAILED\tMESSAGE")
+	for _, s := range report.Services {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%d/%d\t%d\t%s\n", s.Name, s.State, s.RunningTasks, s.DesiredTasks, s.FailedTasks, s.Message)
+	}
+	_ = tw.Flush()
+}
+
+// writeStackReport writes the final state of the stack to the given file
+// as JSON.
+func writeStackReport(filename string, report stackReport) error {
+	b, err := json.MarshalIndent(report, "", "    ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filename, append(b, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
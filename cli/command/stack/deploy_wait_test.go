@@ -0,0 +1,206 @@
+package stack
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/moby/moby/api/types/swarm"
+	"github.com/moby/moby/client"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
+)
+
+func waitTestService(id, name string, running, desired uint64, updateStatus *swarm.UpdateStatus) swarm.Service {
+	return swarm.Service{
+		ID: id,
+		Spec: swarm.ServiceSpec{
+			Annotations: swarm.Annotations{Name: name},
+			Mode:        swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &desired}},
+			UpdateConfig: &swarm.UpdateConfig{
+				Monitor: time.Nanosecond,
+			},
+		},
+		ServiceStatus: &swarm.ServiceStatus{RunningTasks: running, DesiredTasks: desired},
+		UpdateStatus:  updateStatus,
+	}
+}
+
+func runningTasks(serviceID string, n int) []swarm.Task {
+	tasks := make([]swarm.Task, 0, n)
+	for range n {
+		tasks = append(tasks, swarm.Task{
+			ServiceID:    serviceID,
+			DesiredState: swarm.TaskStateRunning,
+			Status:       swarm.TaskStatus{State: swarm.TaskStateRunning},
+		})
+	}
+	return tasks
+}
+
+func TestWaitOnStackConverged(t *testing.T) {
+	fakeCli := test.NewFakeCli(&fakeClient{
+		serviceListFunc: func(options client.ServiceListOptions) (client.ServiceListResult, error) {
+			assert.Check(t, options.Status)
+			return client.ServiceListResult{Items: []swarm.Service{
+				waitTestService("id-web", "mystack_web", 2, 2, nil),
+				waitTestService("id-db", "mystack_db", 1, 1, &swarm.UpdateStatus{State: swarm.UpdateStateCompleted}),
+			}}, nil
+		},
+		taskListFunc: func(client.TaskListOptions) (client.TaskListResult, error) {
+			return client.TaskListResult{Items: append(runningTasks("id-web", 2), runningTasks("id-db", 1)...)}, nil
+		},
+	})
+
+	dir := fs.NewDir(t, t.Name())
+	reportFile := dir.Join("report.json")
+	err := waitOnStack(context.Background(), fakeCli, "mystack", []string{"id-web", "id-db"}, waitOptions{quiet: true, report: reportFile})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(fakeCli.ErrBuffer().String(), ""))
+
+	report, err := os.ReadFile(reportFile)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(report), `{
+    "stack": "mystack",
+    "converged": true,
+    "services": [
+        {
+            "id": "id-db",
+            "name": "mystack_db",
+            "state": "converged",
+            "runningTasks": 1,
+            "desiredTasks": 1,
+            "failedTasks": 0
+        },
+        {
+            "id": "id-web",
+            "name": "mystack_web",
+            "state": "converged",
+            "runningTasks": 2,
+            "desiredTasks": 2,
+            "failedTasks": 0
+        }
+    ]
+}
+`))
+}
+
+func TestWaitOnStackRolledBack(t *testing.T) {
+	fakeCli := test.NewFakeCli(&fakeClient{
+		serviceListFunc: func(client.ServiceListOptions) (client.ServiceListResult, error) {
+			return client.ServiceListResult{Items: []swarm.Service{
+				waitTestService("id-web", "mystack_web", 2, 2, nil),
+				waitTestService("id-db", "mystack_db", 1, 1, &swarm.UpdateStatus{
+					State:   swarm.UpdateStateRollbackCompleted,
+					Message: "rollback completed",
+				}),
+			}}, nil
+		},
+		taskListFunc: func(client.TaskListOptions) (client.TaskListResult, error) {
+			return client.TaskListResult{Items: append(runningTasks("id-web", 2), runningTasks("id-db", 1)...)}, nil
+		},
+	})
+
+	err := waitOnStack(context.Background(), fakeCli, "mystack", []string{"id-web", "id-db"}, waitOptions{quiet: true})
+	assert.Check(t, is.Error(err, "stack mystack did not converge: 1 out of 2 services failed"))
+	assert.Check(t, is.Equal(fakeCli.ErrBuffer().String(), `SERVICE       STATE         TASKS     FAILED    MESSAGE
+mystack_db    rolled back   1/1       0         rollback completed
+mystack_web   converged     2/2       0         
+`))
+}
+
+func TestWaitOnStackFailingTasks(t *testing.T) {
+	var polls int
+	fakeCli := test.NewFakeCli(&fakeClient{
+		serviceListFunc: func(client.ServiceListOptions) (client.ServiceListResult, error) {
+			return client.ServiceListResult{Items: []swarm.Service{
+				waitTestService("id-web", "mystack_web", 0, 1, nil),
+			}}, nil
+		},
+		taskListFunc: func(client.TaskListOptions) (client.TaskListResult, error) {
+			// A task that failed before the deploy is not counted.
+			tasks := []swarm.Task{{
+				ID:        "task-old",
+				ServiceID: "id-web",
+				Status:    swarm.TaskStatus{State: swarm.TaskStateFailed, Err: "old failure"},
+			}}
+			polls++
+			for i := range min(polls, 4) {
+				tasks = append(tasks, swarm.Task{
+					ID:        "task-" + string(rune('a'+i)),
+					ServiceID: "id-web",
+					Status: swarm.TaskStatus{
+						Timestamp: time.Unix(int64(i), 0),
+						State:     swarm.TaskStateFailed,
+						Err:       "task: non-zero exit (1)",
+					},
+				})
+			}
+			if polls == 1 {
+				// Only the old task had failed when starting to wait.
+				tasks = tasks[:1]
+			}
+			return client.TaskListResult{Items: tasks}, nil
+		},
+	})
+	pollInterval = time.Millisecond
+	defer func() { pollInterval = 200 * time.Millisecond }()
+
+	err := waitOnStack(context.Background(), fakeCli, "mystack", []string{"id-web"}, waitOptions{quiet: true})
+	assert.Check(t, is.Error(err, "stack mystack did not converge: 1 out of 1 services failed"))
+	assert.Check(t, is.Equal(fakeCli.ErrBuffer().String(), `SERVICE       STATE     TASKS     FAILED    MESSAGE
+mystack_web   failed    0/1       3         task: non-zero exit (1)
+`))
+}
+
+func TestWaitOnStackTimeout(t *testing.T) {
+	fakeCli := test.NewFakeCli(&fakeClient{
+		serviceListFunc: func(client.ServiceListOptions) (client.ServiceListResult, error) {
+			return client.ServiceListResult{Items: []swarm.Service{
+				waitTestService("id-web", "mystack_web", 0, 1, &swarm.UpdateStatus{State: swarm.UpdateStateUpdating}),
+			}}, nil
+		},
+	})
+
+	dir := fs.NewDir(t, t.Name())
+	reportFile := dir.Join("report.json")
+	err := waitOnStack(context.Background(), fakeCli, "mystack", []string{"id-web"}, waitOptions{
+		timeout: 10 * time.Millisecond,
+		report:  reportFile,
+	})
+	assert.Check(t, is.Error(err, "stack mystack did not converge: 1 out of 1 services failed"))
+	assert.Check(t, is.Contains(fakeCli.OutBuffer().String(), "mystack_web: 0/1 tasks running"))
+	assert.Check(t, is.Contains(fakeCli.OutBuffer().String(), "mystack_web: timed out: timed out waiting for the service to converge"))
+	assert.Check(t, is.Contains(fakeCli.ErrBuffer().String(), "mystack_web   timed out   0/1 "))
+
+	report, err := os.ReadFile(reportFile)
+	assert.NilError(t, err)
+	assert.Check(t, is.Contains(string(report), `"state": "timed out"`))
+}
+
+func TestWaitOnStackCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	fakeCli := test.NewFakeCli(&fakeClient{
+		serviceListFunc: func(client.ServiceListOptions) (client.ServiceListResult, error) {
+			cancel()
+			return client.ServiceListResult{Items: []swarm.Service{
+				waitTestService("id-web", "mystack_web", 0, 1, &swarm.UpdateStatus{State: swarm.UpdateStateUpdating}),
+			}}, nil
+		},
+	})
+
+	err := waitOnStack(ctx, fakeCli, "mystack", []string{"id-web"}, waitOptions{})
+	assert.Check(t, is.ErrorIs(err, context.Canceled))
+}
+
+func TestDeployTimeoutRequiresNoDetach(t *testing.T) {
+	err := runDeploy(context.Background(), test.NewFakeCli(&fakeClient{}), newDeployCommand(nil).Flags(), &deployOptions{
+		resolveImage: resolveImageAlways,
+		detach:       true,
+		waitOptions:  waitOptions{timeout: time.Minute},
+	}, nil)
+	assert.Check(t, is.Error(err, "--timeout and --report can only be used with --detach=false"))
+}
@@ -27,7 +27,10 @@ func loadComposeFile(streams command.Streams, opts deployOptions) (*composetypes
 		return nil, err
 	}
 
-	config, err := loader.Load(configDetails)
+	profiles := getProfiles(opts.profiles, configDetails.Environment)
+	config, err := loader.Load(configDetails, func(options *loader.Options) {
+		options.Profiles = profiles
+	})
 	if err != nil {
 		var fpe *loader.ForbiddenPropertiesError
 		if errors.As(err, &fpe) {
@@ -64,6 +67,22 @@ func loadComposeFile(streams command.Streams, opts deployOptions) (*composetypes
 	return config, nil
 }
 
+// getProfiles returns the profiles to enable. Profiles set through the
+// "--profile" flag take precedence over the COMPOSE_PROFILES environment
+// variable, which holds a comma-separated list of profiles.
+func getProfiles(profiles []string, env map[string]string) []string {
+	if len(profiles) > 0 {
+		return profiles
+	}
+	var result []string
+	for p := range strings.SplitSeq(env["COMPOSE_PROFILES"], ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
 func getDictsFrom(configFiles []composetypes.ConfigFile) []map[string]any {
 	dicts := make([]map[string]any, 0, len(configFiles))
 	for _, configFile := range configFiles {
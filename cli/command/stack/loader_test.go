@@ -77,3 +77,37 @@ func TestBuildEnvironment(t *testing.T) {
 	assert.Check(t, is.Equal("LEGIT_VALUE", env["LEGIT_VAR"]))
 	assert.Check(t, is.Equal("", env["EMPTY_VARIABLE"]))
 }
+
+func TestGetProfiles(t *testing.T) {
+	tests := []struct {
+		doc      string
+		profiles []string
+		env      map[string]string
+		expected []string
+	}{
+		{
+			doc: "no profiles",
+		},
+		{
+			doc:      "flag",
+			profiles: []string{"debug"},
+			expected: []string{"debug"},
+		},
+		{
+			doc:      "environment variable",
+			env:      map[string]string{"COMPOSE_PROFILES": "debug, admin,,"},
+			expected: []string{"debug", "admin"},
+		},
+		{
+			doc:      "flag takes precedence",
+			profiles: []string{"debug"},
+			env:      map[string]string{"COMPOSE_PROFILES": "admin"},
+			expected: []string{"debug"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			assert.Check(t, is.DeepEqual(tc.expected, getProfiles(tc.profiles, tc.env)))
+		})
+	}
+}
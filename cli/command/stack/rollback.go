@@ -0,0 +1,136 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package stack
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/compose/convert"
+	"github.com/moby/moby/api/types/swarm"
+	"github.com/moby/moby/client"
+	"github.com/spf13/cobra"
+)
+
+// rollbackOptions holds docker stack rollback options
+type rollbackOptions struct {
+	namespace string
+	services  []string
+	detach    bool
+	quiet     bool
+}
+
+func newRollbackCommand(dockerCLI command.Cli) *cobra.Command {
+	var opts rollbackOptions
+
+	cmd := &cobra.Command{
+		Use:   "rollback [OPTIONS] STACK",
+		Short: "Revert the services of a stack to their previous configuration",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.namespace = args[0]
+			if err := validateStackName(opts.namespace); err != nil {
+				return err
+			}
+			return runRollback(cmd.Context(), dockerCLI, opts)
+		},
+		Annotations:           map[string]string{"version": "1.31"},
+		ValidArgsFunction:     completeNames(dockerCLI),
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.StringSliceVar(&opts.services, "service", nil, "Only roll back the specified service")
+	flags.BoolVarP(&opts.detach, "detach", "d", false, "Exit immediately instead of waiting for the stack services to converge")
+	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Suppress progress output")
+	return cmd
+}
+
+// runRollback is the swarm implementation of docker stack rollback.
+func runRollback(ctx context.Context, dockerCLI command.Cli, opts rollbackOptions) error {
+	apiClient := dockerCLI.Client()
+
+	services, err := getStackServices(ctx, apiClient, opts.namespace)
+	if err != nil {
+		return err
+	}
+	if len(services.Items) == 0 {
+		return fmt.Errorf("nothing found in stack: %s", opts.namespace)
+	}
+
+	toRollback, err := selectRollbackServices(dockerCLI, convert.NewNamespace(opts.namespace), services.Items, opts.services)
+	if err != nil {
+		return err
+	}
+	if len(toRollback) == 0 {
+		return fmt.Errorf("no services to roll back in stack: %s", opts.namespace)
+	}
+
+	serviceIDs := make([]string, 0, len(toRollback))
+	for _, svc := range toRollback {
+		_, _ = fmt.Fprintln(dockerCLI.Out(), "Rolling back service", svc.Spec.Name)
+		response, err := apiClient.ServiceUpdate(ctx, svc.ID, client.ServiceUpdateOptions{
+			Version:  svc.Version,
+			Spec:     svc.Spec,
+			Rollback: "previous",
+		})
+		if err != nil {
+			return fmt.Errorf("failed to roll back service %s: %w", svc.Spec.Name, err)
+		}
+		for _, warning := range response.Warnings {
+			_, _ = fmt.Fprintln(dockerCLI.Err(), warning)
+		}
+		serviceIDs = append(serviceIDs, svc.ID)
+	}
+
+	if opts.detach {
+		return nil
+	}
+
+	return waitOnStack(ctx, dockerCLI, opts.namespace, serviceIDs, waitOptions{quiet: opts.quiet, rollback: true})
+}
+
+// selectRollbackServices returns the services to roll back, sorted by name.
+// If no service names are given, all services that have a previous spec are
+// selected, and services without a previous spec are skipped. Service names
+// can be given with or without the stack's namespace.
+func selectRollbackServices(dockerCLI command.Cli, namespace convert.Namespace, services []swarm.Service, names []string) ([]swarm.Service, error) {
+	slices.SortFunc(services, func(a, b swarm.Service) int {
+		return strings.Compare(a.Spec.Name, b.Spec.Name)
+	})
+
+	if len(names) == 0 {
+		selected := make([]swarm.Service, 0, len(services))
+		for _, svc := range services {
+			if svc.PreviousSpec == nil {
+				_, _ = fmt.Fprintln(dockerCLI.Err(), "Skipping service", svc.Spec.Name+": no previous configuration to roll back to")
+				continue
+			}
+			selected = append(selected, svc)
+		}
+		return selected, nil
+	}
+
+	selected := make([]swarm.Service, 0, len(names))
+	for _, name := range names {
+		idx := slices.IndexFunc(services, func(svc swarm.Service) bool {
+			return svc.Spec.Name == name || namespace.Descope(svc.Spec.Name) == name
+		})
+		if idx < 0 {
+			return nil, fmt.Errorf("service %s not found in stack: %s", name, namespace.Name())
+		}
+		svc := services[idx]
+		if svc.PreviousSpec == nil {
+			return nil, fmt.Errorf("service %s has no previous configuration to roll back to", svc.Spec.Name)
+		}
+		if !slices.ContainsFunc(selected, func(s swarm.Service) bool { return s.ID == svc.ID }) {
+			selected = append(selected, svc)
+		}
+	}
+	return selected, nil
+}
@@ -0,0 +1,131 @@
+package stack
+
+import (
+	"io"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/moby/moby/api/types/swarm"
+	"github.com/moby/moby/client"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func fakeClientForRollbackTest(t *testing.T, rolledBack *[]string) *fakeClient {
+	t.Helper()
+	return &fakeClient{
+		serviceListFunc: func(options client.ServiceListOptions) (client.ServiceListResult, error) {
+			assert.Check(t, is.Equal(namespaceFromFilters(options.Filters), "foo"))
+			web := serviceFromName(objectName("foo", "web"))
+			web.PreviousSpec = &swarm.ServiceSpec{Annotations: web.Spec.Annotations}
+			db := serviceFromName(objectName("foo", "db"))
+			db.PreviousSpec = &swarm.ServiceSpec{Annotations: db.Spec.Annotations}
+			// A service that was never updated has no previous spec.
+			cache := serviceFromName(objectName("foo", "cache"))
+			return client.ServiceListResult{Items: []swarm.Service{web, db, cache}}, nil
+		},
+		serviceUpdateFunc: func(serviceID string, options client.ServiceUpdateOptions) (client.ServiceUpdateResult, error) {
+			assert.Check(t, is.Equal(options.Rollback, "previous"))
+			*rolledBack = append(*rolledBack, serviceID)
+			return client.ServiceUpdateResult{}, nil
+		},
+	}
+}
+
+func TestRollbackWithEmptyName(t *testing.T) {
+	cmd := newRollbackCommand(test.NewFakeCli(&fakeClient{}))
+	cmd.SetArgs([]string{"'   '"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+
+	assert.ErrorContains(t, cmd.Execute(), `invalid stack name: "'   '"`)
+}
+
+func TestRollbackAllServices(t *testing.T) {
+	var rolledBack []string
+	cli := test.NewFakeCli(fakeClientForRollbackTest(t, &rolledBack))
+	cmd := newRollbackCommand(cli)
+	cmd.SetArgs([]string{"--detach", "foo"})
+
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.DeepEqual(rolledBack, []string{objectID("foo_db"), objectID("foo_web")}))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "Rolling back service foo_db\nRolling back service foo_web\n"))
+	assert.Check(t, is.Equal(cli.ErrBuffer().String(), "Skipping service foo_cache: no previous configuration to roll back to\n"))
+}
+
+func TestRollbackSelectedServices(t *testing.T) {
+	var rolledBack []string
+	cmd := newRollbackCommand(test.NewFakeCli(fakeClientForRollbackTest(t, &rolledBack)))
+	cmd.SetArgs([]string{"--detach", "--service", "web", "--service", "foo_web", "foo"})
+
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.DeepEqual(rolledBack, []string{objectID("foo_web")}))
+}
+
+func TestRollbackWaitsOnStack(t *testing.T) {
+	var rolledBack []string
+	apiClient := fakeClientForRollbackTest(t, &rolledBack)
+	listStack := apiClient.serviceListFunc
+	apiClient.serviceListFunc = func(options client.ServiceListOptions) (client.ServiceListResult, error) {
+		if !options.Status {
+			return listStack(options)
+		}
+		// A completed rollback means the service converged.
+		return client.ServiceListResult{Items: []swarm.Service{
+			waitTestService(objectID("foo_web"), "foo_web", 1, 1, &swarm.UpdateStatus{
+				State:   swarm.UpdateStateRollbackCompleted,
+				Message: "rollback completed",
+			}),
+		}}, nil
+	}
+	apiClient.taskListFunc = func(client.TaskListOptions) (client.TaskListResult, error) {
+		return client.TaskListResult{Items: runningTasks(objectID("foo_web"), 1)}, nil
+	}
+	cli := test.NewFakeCli(apiClient)
+	cmd := newRollbackCommand(cli)
+	cmd.SetArgs([]string{"--quiet", "--service", "web", "foo"})
+
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.DeepEqual(rolledBack, []string{objectID("foo_web")}))
+	assert.Check(t, is.Equal(cli.ErrBuffer().String(), ""))
+}
+
+func TestRollbackErrors(t *testing.T) {
+	tests := []struct {
+		doc         string
+		args        []string
+		expectedErr string
+	}{
+		{
+			doc:         "empty stack",
+			args:        []string{"bar"},
+			expectedErr: "nothing found in stack: bar",
+		},
+		{
+			doc:         "unknown service",
+			args:        []string{"--service", "nosuchservice", "foo"},
+			expectedErr: "service nosuchservice not found in stack: foo",
+		},
+		{
+			doc:         "no previous spec",
+			args:        []string{"--service", "cache", "foo"},
+			expectedErr: "service foo_cache has no previous configuration to roll back to",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			var rolledBack []string
+			apiClient := fakeClientForRollbackTest(t, &rolledBack)
+			if tc.args[len(tc.args)-1] != "foo" {
+				apiClient.serviceListFunc = nil
+			}
+			cmd := newRollbackCommand(test.NewFakeCli(apiClient))
+			cmd.SetArgs(tc.args)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+
+			assert.Check(t, is.Error(cmd.Execute(), tc.expectedErr))
+			assert.Check(t, is.Len(rolledBack, 0))
+		})
+	}
+}
@@ -1,7 +1,13 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
 package system
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"sort"
@@ -21,12 +27,23 @@
 )
 
 type eventsOptions struct {
-	since  string
-	until  string
-	filter opts.FilterOpt
-	format string
+	since          string
+	until          string
+	filter         opts.FilterOpt
+	format         string
+	autoReconnect  bool
+	output         string
+	outputMaxSize  opts.MemBytes
+	outputMaxFiles int
 }
 
+const maxReconnectDelay = 30 * time.Second
+
+// reconnectDelay is the delay before reconnecting after the event stream
+// is interrupted. It is doubled for each consecutive failure, up to
+// maxReconnectDelay.
+var reconnectDelay = time.Second
+
 // newEventsCommand creates a new cobra.Command for `docker events`
 func newEventsCommand(dockerCLI command.Cli) *cobra.Command {
 	options := eventsOptions{filter: opts.NewFilterOpt()}
@@ -50,6 +67,10 @@ func newEventsCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.StringVar(&options.until, "until", "", "Stream events until this timestamp")
 	flags.VarP(&options.filter, "filter", "f", "Filter output based on conditions provided")
 	flags.StringVar(&options.format, "format", "", flagsHelper.InspectFormatHelp) // using the same flag description as "inspect" commands for now.
+	flags.BoolVar(&options.autoReconnect, "auto-reconnect", false, "Reconnect when the event stream is interrupted, resuming from the last received event")
+	flags.StringVar(&options.output, "output", "", "Write events as newline-delimited JSON to a file")
+	flags.Var(&options.outputMaxSize, "output-max-size", "Rotate the output file when it reaches the given size")
+	flags.IntVar(&options.outputMaxFiles, "output-max-files", 5, "Number of rotated output files to keep")
 
 	_ = cmd.RegisterFlagCompletionFunc("filter", completeEventFilters(dockerCLI))
 
@@ -57,6 +78,9 @@ func newEventsCommand(dockerCLI command.Cli) *cobra.Command {
 }
 
 func runEvents(ctx context.Context, dockerCLI command.Cli, options *eventsOptions) error {
+	if options.output != "" && options.format != "" {
+		return errors.New("--format cannot be used with --output")
+	}
 	tmpl, err := makeTemplate(options.format)
 	if err != nil {
 		return cli.StatusError{
@@ -64,21 +88,52 @@ func runEvents(ctx context.Context, dockerCLI command.Cli, options *eventsOption
 			Status:     "Error parsing format: " + err.Error(),
 		}
 	}
-	ctx, cancel := context.WithCancel(ctx)
-	eventRes := dockerCLI.Client().Events(ctx, client.EventsListOptions{
+
+	handle := func(event events.Message) error {
+		return handleEvent(dockerCLI.Out(), event, tmpl)
+	}
+	if options.output != "" {
+		f, err := newRotatingFile(options.output, options.outputMaxSize.Value(), options.outputMaxFiles)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		tmpl, _ = makeTemplate(formatter.JSONFormatKey)
+		handle = func(event events.Message) error {
+			// Write each event in a single write, so that events are not
+			// split when rotating the file.
+			var buf bytes.Buffer
+			if err := handleEvent(&buf, event, tmpl); err != nil {
+				return err
+			}
+			_, err := f.Write(buf.Bytes())
+			return err
+		}
+	}
+
+	listOptions := client.EventsListOptions{
 		Since:   options.since,
 		Until:   options.until,
 		Filters: options.filter.Value(),
-	})
-	defer cancel()
+	}
+	if !options.autoReconnect {
+		return streamEvents(ctx, dockerCLI.Client(), listOptions, handle)
+	}
+	return streamEventsWithReconnect(ctx, dockerCLI, listOptions, handle)
+}
 
-	out := dockerCLI.Out()
+// streamEvents handles events until the event stream ends. It returns nil
+// if the stream ended normally.
+func streamEvents(ctx context.Context, apiClient client.SystemAPIClient, listOptions client.EventsListOptions, handle func(events.Message) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	eventRes := apiClient.Events(ctx, listOptions)
+	defer cancel()
 
 	for {
 		select {
 		case event := <-eventRes.Messages:
-			if err := handleEvent(out, event, tmpl); err != nil {
-				return err
+			if err := handle(event); err != nil {
+				return handlerError{err}
 			}
 		case err := <-eventRes.Err:
 			if err == io.EOF {
@@ -89,6 +144,109 @@ func runEvents(ctx context.Context, dockerCLI command.Cli, options *eventsOption
 	}
 }
 
+// handlerError is returned by streamEvents if an event could not be handled,
+// as opposed to errors from the event stream itself.
+type handlerError struct{ error }
+
+func (e handlerError) Unwrap() error { return e.error }
+
+// streamEventsWithReconnect handles events, and reconnects when the event
+// stream is interrupted. Events are resumed from the most recent event that
+// was handled, and events that were already handled are skipped.
+func streamEventsWithReconnect(ctx context.Context, dockerCLI command.Cli, listOptions client.EventsListOptions, handle func(events.Message) error) error {
+	var (
+		tracker eventTracker
+		delay   = reconnectDelay
+	)
+	if listOptions.Since == "" {
+		// Resume from the moment we started listening if the stream is
+		// interrupted before receiving any event.
+		listOptions.Since = formatEventTime(time.Now().UnixNano())
+	}
+
+	for {
+		err := streamEvents(ctx, dockerCLI.Client(), listOptions, func(event events.Message) error {
+			delay = reconnectDelay
+			if !tracker.add(event) {
+				return nil
+			}
+			return handle(event)
+		})
+		var hErr handlerError
+		switch {
+		case errors.As(err, &hErr):
+			return hErr.error
+		case ctx.Err() != nil:
+			return err
+		case err == nil && listOptions.Until != "":
+			return nil
+		}
+
+		if tracker.lastTime != 0 {
+			listOptions.Since = formatEventTime(tracker.lastTime)
+		}
+		if err == nil {
+			err = errors.New("event stream closed")
+		}
+		_, _ = fmt.Fprintf(dockerCLI.Err(), "Event stream interrupted: %v; reconnecting in %s\n", err, delay)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		delay = min(delay*2, maxReconnectDelay)
+	}
+}
+
+// eventTracker tracks the most recent events that were handled, so that
+// events are not handled twice when resuming the event stream. The daemon
+// returns events that occurred at, or after the "since" timestamp, so events
+// at the timestamp of the most recent event may be returned again.
+type eventTracker struct {
+	// lastTime is the time of the most recent event, in nanoseconds.
+	lastTime int64
+	// seen holds the events that were handled at lastTime.
+	seen map[string]struct{}
+}
+
+// add records the event, and returns false if it was already handled.
+func (t *eventTracker) add(event events.Message) bool {
+	ts := eventTime(event)
+	key := eventKey(event)
+	switch {
+	case ts > t.lastTime:
+		t.lastTime = ts
+		t.seen = map[string]struct{}{key: {}}
+	case ts == t.lastTime:
+		if _, ok := t.seen[key]; ok {
+			return false
+		}
+		if t.seen == nil {
+			t.seen = make(map[string]struct{})
+		}
+		t.seen[key] = struct{}{}
+	}
+	return true
+}
+
+func eventTime(event events.Message) int64 {
+	if event.TimeNano != 0 {
+		return event.TimeNano
+	}
+	return event.Time * int64(time.Second)
+}
+
+func eventKey(event events.Message) string {
+	b, _ := json.Marshal(event)
+	return string(b)
+}
+
+// formatEventTime formats a time in nanoseconds in the format accepted by
+// the "since" and "until" options.
+func formatEventTime(ts int64) string {
+	return fmt.Sprintf("%d.%09d", ts/int64(time.Second), ts%int64(time.Second))
+}
+
 func handleEvent(out io.Writer, event events.Message, tmpl *template.Template) error {
 	if tmpl == nil {
 		return prettyPrintEvent(out, event)
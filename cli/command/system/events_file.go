@@ -0,0 +1,97 @@
+package system
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// rotatingFile is a file that is rotated when it reaches a maximum size.
+// Rotated files are renamed with a numeric suffix (".1" being the most
+// recent), and only the given number of rotated files are kept.
+//
+// The file is only rotated between writes, so each write is expected to
+// consist of whole lines to prevent lines from being split across files.
+type rotatingFile struct {
+	path     string
+	maxSize  int64
+	maxFiles int
+
+	f    *os.File
+	size int64
+}
+
+// newRotatingFile opens the file at path for appending. If maxSize is 0, the
+// file is never rotated.
+func newRotatingFile(path string, maxSize int64, maxFiles int) (*rotatingFile, error) {
+	if maxSize < 0 {
+		return nil, errors.New("maximum size of the output file must not be negative")
+	}
+	if maxFiles < 0 {
+		return nil, errors.New("number of rotated output files must not be negative")
+	}
+	r := &rotatingFile{path: path, maxSize: maxSize, maxFiles: maxFiles}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open output file: %w", err)
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to open output file: %w", err)
+	}
+	r.f, r.size = f, fi.Size()
+	return nil
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	if r.maxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate closes the current file, shifts the rotated files, and opens a new
+// file.
+func (r *rotatingFile) rotate() error {
+	if err := r.f.Close(); err != nil {
+		return err
+	}
+	if r.maxFiles == 0 {
+		if err := os.Remove(r.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate output file: %w", err)
+		}
+		return r.open()
+	}
+	if err := os.Remove(rotatedName(r.path, r.maxFiles)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to rotate output file: %w", err)
+	}
+	for i := r.maxFiles - 1; i > 0; i-- {
+		if err := os.Rename(rotatedName(r.path, i), rotatedName(r.path, i+1)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate output file: %w", err)
+		}
+	}
+	if err := os.Rename(r.path, rotatedName(r.path, 1)); err != nil {
+		return fmt.Errorf("failed to rotate output file: %w", err)
+	}
+	return r.open()
+}
+
+func (r *rotatingFile) Close() error {
+	return r.f.Close()
+}
+
+func rotatedName(path string, n int) string {
+	return fmt.Sprintf("%s.%d", path, n)
+}
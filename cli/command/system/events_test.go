@@ -2,8 +2,11 @@
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"os"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -12,6 +15,8 @@
 	"github.com/moby/moby/api/types/events"
 	"github.com/moby/moby/client"
 	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
 	"gotest.tools/v3/golden"
 )
 
@@ -79,3 +84,105 @@ func TestEventsFormat(t *testing.T) {
 		})
 	}
 }
+
+func TestEventsAutoReconnect(t *testing.T) {
+	t.Setenv("TZ", "UTC")
+	defer func(d time.Duration) { reconnectDelay = d }(reconnectDelay)
+	reconnectDelay = time.Millisecond
+
+	newEvent := func(action events.Action, sec int64) events.Message {
+		return events.Message{
+			Type:     events.ContainerEventType,
+			Action:   action,
+			Actor:    events.Actor{ID: "abc123"},
+			Time:     sec,
+			TimeNano: sec * int64(time.Second),
+		}
+	}
+
+	var calls []client.EventsListOptions
+	fakeCLI := test.NewFakeCli(&fakeClient{eventsFn: func(_ context.Context, options client.EventsListOptions) (<-chan events.Message, <-chan error) {
+		calls = append(calls, options)
+		var msgs []events.Message
+		var err error
+		switch len(calls) {
+		case 1:
+			msgs = []events.Message{newEvent(events.ActionCreate, 1), newEvent(events.ActionStart, 2)}
+			err = errors.New("connection reset by peer")
+		default:
+			// Events at the "since" timestamp are returned again.
+			msgs = []events.Message{newEvent(events.ActionStart, 2), newEvent(events.ActionAttach, 2), newEvent(events.ActionDie, 3)}
+			err = io.EOF
+		}
+		messages := make(chan events.Message)
+		errs := make(chan error, 1)
+		go func() {
+			for _, msg := range msgs {
+				messages <- msg
+			}
+			errs <- err
+		}()
+		return messages, errs
+	}})
+	cmd := newEventsCommand(fakeCLI)
+	cmd.SetArgs([]string{"--auto-reconnect", "--since", "0", "--until", "4", "--format", "{{ .Action }} {{ .Time }}"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.NilError(t, cmd.Execute())
+
+	assert.Assert(t, is.Len(calls, 2))
+	assert.Check(t, is.Equal(calls[0].Since, "0"))
+	assert.Check(t, is.Equal(calls[1].Since, "2.000000000"))
+	assert.Check(t, is.Equal(calls[1].Until, "4"))
+	assert.Check(t, is.Equal(fakeCLI.OutBuffer().String(), "create 1\nstart 2\nattach 2\ndie 3\n"))
+	assert.Check(t, is.Equal(fakeCLI.ErrBuffer().String(), "Event stream interrupted: connection reset by peer; reconnecting in 1ms\n"))
+}
+
+func TestEventsOutputFile(t *testing.T) {
+	dir := fs.NewDir(t, t.Name())
+	output := dir.Join("events.json")
+
+	fakeCLI := test.NewFakeCli(&fakeClient{eventsFn: func(context.Context, client.EventsListOptions) (<-chan events.Message, <-chan error) {
+		messages := make(chan events.Message)
+		errs := make(chan error, 1)
+		go func() {
+			for i := range 5 {
+				messages <- events.Message{
+					Type:     events.ContainerEventType,
+					Action:   events.ActionStart,
+					Actor:    events.Actor{ID: strconv.Itoa(i)},
+					TimeNano: int64(i),
+				}
+			}
+			errs <- io.EOF
+		}()
+		return messages, errs
+	}})
+	cmd := newEventsCommand(fakeCLI)
+	// Each event is 88 bytes, so two events fit in a file.
+	cmd.SetArgs([]string{"--output", output, "--output-max-size", "200b", "--output-max-files", "1"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(fakeCLI.OutBuffer().String(), ""))
+
+	current, err := os.ReadFile(output)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(current), `{"Type":"container","Action":"start","Actor":{"ID":"4","Attributes":null},"timeNano":4}`+"\n"))
+
+	rotated, err := os.ReadFile(output + ".1")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(strings.Count(string(rotated), "\n"), 2))
+	assert.Check(t, is.Contains(string(rotated), `"ID":"2"`))
+
+	_, err = os.Stat(output + ".2")
+	assert.Check(t, os.IsNotExist(err))
+}
+
+func TestEventsOutputWithFormat(t *testing.T) {
+	cmd := newEventsCommand(test.NewFakeCli(&fakeClient{}))
+	cmd.SetArgs([]string{"--output", "events.json", "--format", "json"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Check(t, is.Error(cmd.Execute(), "--format cannot be used with --output"))
+}
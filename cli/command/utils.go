@@ -14,6 +14,20 @@
 	"github.com/moby/moby/client"
 )
 
+// DefaultPlatform returns the platform to use for commands that accept a
+// "--platform" flag. The platform set through the flag or the
+// DOCKER_DEFAULT_PLATFORM environment variable takes precedence over the
+// "defaultPlatform" setting in config.json.
+func DefaultPlatform(dockerCLI config.Provider, platform string) string {
+	if platform != "" {
+		return platform
+	}
+	if cfg := dockerCLI.ConfigFile(); cfg != nil {
+		return cfg.DefaultPlatform
+	}
+	return ""
+}
+
 // PruneFilters merges prune filters specified in config.json with those specified
 // as command-line flags. It returns a deep copy of filters to prevent mutating
 // the original.
@@ -0,0 +1,247 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package loader
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/docker/cli/cli/compose/schema"
+	"github.com/docker/cli/cli/compose/types"
+)
+
+// extendsConfig is the "extends" section of a service.
+type extendsConfig struct {
+	// Service is the name of the service to extend.
+	Service string
+	// File is the (optional) file in which the service to extend is
+	// defined. If empty, the service is looked up in the same file.
+	File string
+}
+
+// extractExtends returns a copy of configDict with the "extends" section
+// of each service removed, and a map of the removed extends sections by
+// service name. configDict itself is not modified.
+func extractExtends(configDict map[string]any) (map[string]any, map[string]extendsConfig, error) {
+	services, ok := configDict["services"].(map[string]any)
+	if !ok {
+		return configDict, nil, nil
+	}
+
+	var (
+		extends     map[string]extendsConfig
+		newServices map[string]any
+	)
+	for name, service := range services {
+		serviceDict, ok := service.(map[string]any)
+		if !ok {
+			continue
+		}
+		value, ok := serviceDict["extends"]
+		if !ok {
+			continue
+		}
+		ext, err := parseExtends(name, value)
+		if err != nil {
+			return nil, nil, err
+		}
+		if extends == nil {
+			extends = make(map[string]extendsConfig)
+			newServices = maps.Clone(services)
+		}
+		extends[name] = ext
+
+		serviceDict = maps.Clone(serviceDict)
+		delete(serviceDict, "extends")
+		newServices[name] = serviceDict
+	}
+	if extends == nil {
+		return configDict, nil, nil
+	}
+
+	configDict = maps.Clone(configDict)
+	configDict["services"] = newServices
+	return configDict, extends, nil
+}
+
+// parseExtends parses the "extends" section of a service, which can either
+// be a string (the name of a service in the same file), or a mapping with
+// a "service" and optional "file" property.
+func parseExtends(name string, value any) (extendsConfig, error) {
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			return extendsConfig{}, fmt.Errorf("services.%s.extends: service name must not be empty", name)
+		}
+		return extendsConfig{Service: v}, nil
+	case map[string]any:
+		var ext extendsConfig
+		for key, val := range v {
+			s, ok := val.(string)
+			if !ok {
+				return extendsConfig{}, fmt.Errorf("services.%s.extends.%s must be a string", name, key)
+			}
+			switch key {
+			case "service":
+				ext.Service = s
+			case "file":
+				ext.File = s
+			default:
+				return extendsConfig{}, fmt.Errorf("services.%s.extends: additional property %s is not allowed", name, key)
+			}
+		}
+		if ext.Service == "" {
+			return extendsConfig{}, fmt.Errorf("services.%s.extends: service is required", name)
+		}
+		return ext, nil
+	default:
+		return extendsConfig{}, fmt.Errorf("services.%s.extends must be a string or a mapping", name)
+	}
+}
+
+// extendsFile holds the service definitions of a single compose file for
+// resolving "extends".
+type extendsFile struct {
+	// name is the name of the file, used in error messages. It is empty
+	// for the files passed to [Load].
+	name string
+	// workingDir is used to resolve relative paths in the file's services.
+	workingDir string
+	services   map[string]any
+	extends    map[string]extendsConfig
+}
+
+// extendsResolver resolves services' "extends" sections, loading other
+// compose files as needed.
+type extendsResolver struct {
+	lookupEnv func(string) (string, bool)
+	options   *Options
+	// files holds the compose files loaded through "extends.file", keyed
+	// by their absolute path.
+	files map[string]*extendsFile
+}
+
+// resolveExtends returns services with their "extends" sections resolved.
+// Services are extended in the same file, or from other files referenced
+// through "extends.file". Paths of other files are resolved relative to
+// the directory of the file declaring the "extends", and relative paths
+// in the extended services are resolved relative to the directory of the
+// file they are defined in.
+func resolveExtends(services []types.ServiceConfig, servicesDict map[string]any, extends map[string]extendsConfig, configDetails types.ConfigDetails, options *Options) ([]types.ServiceConfig, error) {
+	r := &extendsResolver{
+		lookupEnv: configDetails.LookupEnv,
+		options:   options,
+		files:     make(map[string]*extendsFile),
+	}
+	f := &extendsFile{
+		workingDir: configDetails.WorkingDir,
+		services:   servicesDict,
+		extends:    extends,
+	}
+
+	resolved := make([]types.ServiceConfig, 0, len(services))
+	for _, s := range services {
+		if _, ok := extends[s.Name]; !ok {
+			resolved = append(resolved, s)
+			continue
+		}
+		svc, err := r.resolve(f, s.Name, nil)
+		if err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, *svc)
+	}
+	return resolved, nil
+}
+
+// resolve loads the service with the given name from f, and recursively
+// merges it on top of the service it extends. Services are loaded anew for
+// each use, as merging modifies the base service in-place.
+func (r *extendsResolver) resolve(f *extendsFile, name string, chain []string) (*types.ServiceConfig, error) {
+	serviceDict, ok := f.services[name].(map[string]any)
+	if !ok {
+		if f.name == "" {
+			return nil, fmt.Errorf("cannot extend service %q: service not found", name)
+		}
+		return nil, fmt.Errorf("cannot extend service %q: service not found in %s", name, f.name)
+	}
+	svc, err := LoadService(name, serviceDict, f.workingDir, r.lookupEnv)
+	if err != nil {
+		return nil, err
+	}
+	if f.name != "" {
+		// env_file paths are kept in the service, so must be made absolute
+		// for the service to be usable from other files.
+		for i, envFile := range svc.EnvFile {
+			svc.EnvFile[i] = absPath(f.workingDir, envFile)
+		}
+	}
+
+	ext, ok := f.extends[name]
+	if !ok {
+		return svc, nil
+	}
+
+	ref := name
+	if f.name != "" {
+		ref = f.name + ":" + name
+	}
+	if slices.Contains(chain, ref) {
+		return nil, fmt.Errorf("circular reference with extends: %s", strings.Join(append(chain, ref), " -> "))
+	}
+
+	baseFile := f
+	if ext.File != "" {
+		baseFile, err = r.loadFile(absPath(f.workingDir, ext.File))
+		if err != nil {
+			return nil, fmt.Errorf("cannot extend service %q: %w", name, err)
+		}
+	}
+	base, err := r.resolve(baseFile, ext.Service, append(chain, ref))
+	if err != nil {
+		return nil, err
+	}
+
+	// Merge the service on top of the service it extends, using the same
+	// rules as for merging multiple compose files.
+	base.Name = name
+	merged, err := mergeServices([]types.ServiceConfig{*base}, []types.ServiceConfig{*svc})
+	if err != nil {
+		return nil, err
+	}
+	return &merged[0], nil
+}
+
+// loadFile loads the compose file at the given (absolute) path, applying
+// the same validation and interpolation as for the files passed to [Load].
+func (r *extendsResolver) loadFile(filename string) (*extendsFile, error) {
+	if f, ok := r.files[filename]; ok {
+		return f, nil
+	}
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	configDict, err := ParseYAML(b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filename, err)
+	}
+	configDict, extends, err := prepareConfigDict(configDict, schema.Version(configDict), r.options)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &extendsFile{
+		name:       filename,
+		workingDir: filepath.Dir(filename),
+		services:   getSection(configDict, "services"),
+		extends:    extends,
+	}
+	r.files[filename] = f
+	return f, nil
+}
@@ -0,0 +1,261 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package loader
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/cli/cli/compose/types"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
+)
+
+func TestLoadExtendsSameFile(t *testing.T) {
+	config, err := loadYAML(`
+version: "3.8"
+services:
+  base:
+    image: busybox
+    command: top
+    environment:
+      FOO: foo
+      BAR: bar
+    labels:
+      com.example.base: "true"
+    ports:
+      - "8080:80"
+  web:
+    extends: base
+    environment:
+      BAR: web-bar
+    ports:
+      - "8443:443"
+  worker:
+    extends:
+      service: web
+    image: alpine
+    command: ["sh", "-c", "work"]
+`)
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(config.Services, 3))
+
+	services := serviceSort(config.Services)
+	base, web, worker := services[0], services[1], services[2]
+	assert.Check(t, is.Equal(base.Name, "base"))
+	assert.Check(t, is.DeepEqual(base.Environment, types.MappingWithEquals{"FOO": strPtr("foo"), "BAR": strPtr("bar")}))
+	assert.Check(t, is.Len(base.Ports, 1))
+
+	assert.Check(t, is.Equal(web.Name, "web"))
+	assert.Check(t, is.Equal(web.Image, "busybox"))
+	assert.Check(t, is.DeepEqual(web.Command, types.ShellCommand{"top"}))
+	assert.Check(t, is.DeepEqual(web.Environment, types.MappingWithEquals{"FOO": strPtr("foo"), "BAR": strPtr("web-bar")}))
+	assert.Check(t, is.DeepEqual(web.Labels, types.Labels{"com.example.base": "true"}))
+	assert.Check(t, is.DeepEqual(web.Ports, []types.ServicePortConfig{
+		{Mode: "ingress", Target: 80, Published: 8080, Protocol: "tcp"},
+		{Mode: "ingress", Target: 443, Published: 8443, Protocol: "tcp"},
+	}))
+
+	assert.Check(t, is.Equal(worker.Name, "worker"))
+	assert.Check(t, is.Equal(worker.Image, "alpine"))
+	assert.Check(t, is.DeepEqual(worker.Command, types.ShellCommand{"sh", "-c", "work"}))
+	assert.Check(t, is.DeepEqual(worker.Environment, types.MappingWithEquals{"FOO": strPtr("foo"), "BAR": strPtr("web-bar")}))
+	assert.Check(t, is.Len(worker.Ports, 2))
+}
+
+func TestLoadExtendsOtherFile(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithDir("common",
+			fs.WithFile("common.yml", `
+version: "3.8"
+services:
+  app:
+    extends: base
+    volumes:
+      - ./data:/data
+  base:
+    image: busybox
+    env_file: common.env
+`),
+			fs.WithFile("common.env", "FROM_ENV_FILE=yes\n"),
+		),
+	)
+
+	dict, err := ParseYAML([]byte(`
+version: "3.8"
+services:
+  web:
+    extends:
+      file: ./common/common.yml
+      service: app
+    environment:
+      LOCAL: "1"
+`))
+	assert.NilError(t, err)
+
+	config, err := Load(types.ConfigDetails{
+		WorkingDir:  dir.Path(),
+		ConfigFiles: []types.ConfigFile{{Filename: "filename.yml", Config: dict}},
+	})
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(config.Services, 1))
+
+	web := config.Services[0]
+	assert.Check(t, is.Equal(web.Name, "web"))
+	assert.Check(t, is.Equal(web.Image, "busybox"))
+	assert.Check(t, is.DeepEqual(web.EnvFile, types.StringList{filepath.Join(dir.Path(), "common", "common.env")}))
+	assert.Check(t, is.DeepEqual(web.Environment, types.MappingWithEquals{"FROM_ENV_FILE": strPtr("yes"), "LOCAL": strPtr("1")}))
+	assert.Check(t, is.DeepEqual(web.Volumes, []types.ServiceVolumeConfig{
+		{Type: "bind", Source: filepath.Join(dir.Path(), "common", "data"), Target: "/data"},
+	}))
+}
+
+func TestLoadExtendsCircularReference(t *testing.T) {
+	_, err := loadYAML(`
+version: "3.8"
+services:
+  foo:
+    image: busybox
+    extends: bar
+  bar:
+    extends: baz
+  baz:
+    extends: foo
+`)
+	assert.Check(t, is.ErrorContains(err, "circular reference with extends: "))
+}
+
+func TestLoadExtendsCircularReferenceOtherFile(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithFile("a.yml", `
+version: "3.8"
+services:
+  a:
+    extends:
+      file: b.yml
+      service: b
+`),
+		fs.WithFile("b.yml", `
+version: "3.8"
+services:
+  b:
+    extends:
+      file: a.yml
+      service: a
+`),
+	)
+
+	dict, err := ParseYAML([]byte(`
+version: "3.8"
+services:
+  web:
+    extends:
+      file: a.yml
+      service: a
+`))
+	assert.NilError(t, err)
+
+	_, err = Load(types.ConfigDetails{
+		WorkingDir:  dir.Path(),
+		ConfigFiles: []types.ConfigFile{{Filename: "filename.yml", Config: dict}},
+	})
+	expected := "circular reference with extends: web -> " + dir.Join("a.yml") + ":a -> " + dir.Join("b.yml") + ":b -> " + dir.Join("a.yml") + ":a"
+	assert.Check(t, is.Error(err, expected))
+}
+
+func TestLoadExtendsErrors(t *testing.T) {
+	tests := []struct {
+		doc         string
+		yaml        string
+		expectedErr string
+	}{
+		{
+			doc: "service not found",
+			yaml: `
+version: "3.8"
+services:
+  foo:
+    image: busybox
+    extends: nosuchservice
+`,
+			expectedErr: `cannot extend service "nosuchservice": service not found`,
+		},
+		{
+			doc: "file not found",
+			yaml: `
+version: "3.8"
+services:
+  foo:
+    image: busybox
+    extends:
+      service: bar
+      file: /no/such/file.yml
+`,
+			expectedErr: `cannot extend service "foo": open /no/such/file.yml: no such file or directory`,
+		},
+		{
+			doc: "missing service",
+			yaml: `
+version: "3.8"
+services:
+  foo:
+    image: busybox
+    extends:
+      file: other.yml
+`,
+			expectedErr: `services.foo.extends: service is required`,
+		},
+		{
+			doc: "unknown property",
+			yaml: `
+version: "3.8"
+services:
+  foo:
+    image: busybox
+    extends:
+      service: bar
+      unknown: true
+`,
+			expectedErr: `services.foo.extends.unknown must be a string`,
+		},
+		{
+			doc: "invalid type",
+			yaml: `
+version: "3.8"
+services:
+  foo:
+    image: busybox
+    extends: [bar]
+`,
+			expectedErr: `services.foo.extends must be a string or a mapping`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			_, err := loadYAML(tc.yaml)
+			assert.Check(t, is.Error(err, tc.expectedErr))
+		})
+	}
+}
+
+func TestLoadExtendsDoesNotModifyConfigDict(t *testing.T) {
+	dict, err := ParseYAML([]byte(`
+version: "3.8"
+services:
+  foo:
+    image: busybox
+  bar:
+    extends: foo
+`))
+	assert.NilError(t, err)
+
+	_, err = Load(buildConfigDetails(dict, nil), func(options *Options) {
+		options.SkipInterpolation = true
+	})
+	assert.NilError(t, err)
+
+	bar := dict["services"].(map[string]any)["bar"].(map[string]any)
+	assert.Check(t, is.Equal(bar["extends"], "foo"))
+}
@@ -11,7 +11,7 @@
 
 func fullExampleConfig(workingDir, homeDir string) *types.Config {
 	return &types.Config{
-		Version:  "3.13",
+		Version:  "3.14",
 		Services: services(workingDir, homeDir),
 		Networks: networks(),
 		Volumes:  volumes(),
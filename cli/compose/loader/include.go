@@ -0,0 +1,229 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/docker/cli/opts"
+)
+
+// includeConfig is an entry in the top-level "include" section.
+type includeConfig struct {
+	// Path is the compose file to include. If multiple paths are set, the
+	// files are merged in order, as with multiple files passed to [Load].
+	Path types.StringList
+	// ProjectDirectory is the directory relative to which paths in the
+	// included files are resolved. It defaults to the directory of the
+	// (first) included file.
+	ProjectDirectory string `mapstructure:"project_directory"`
+	// EnvFile is the list of files from which to read environment variables
+	// used for interpolation. It defaults to the ".env" file in the project
+	// directory, if present.
+	EnvFile types.StringList `mapstructure:"env_file"`
+}
+
+// parseIncludes parses the top-level "include" section, which is a list of
+// paths, or mappings in the form of [includeConfig].
+func parseIncludes(source any) ([]includeConfig, error) {
+	entries, ok := source.([]any)
+	if !ok {
+		return nil, errors.New("include must be a list")
+	}
+	includes := make([]includeConfig, 0, len(entries))
+	for i, entry := range entries {
+		var include includeConfig
+		switch v := entry.(type) {
+		case string:
+			include.Path = types.StringList{v}
+		case map[string]any:
+			if err := Transform(v, &include); err != nil {
+				return nil, fmt.Errorf("include[%d]: %w", i, err)
+			}
+		default:
+			return nil, fmt.Errorf("include[%d] must be a string or a mapping", i)
+		}
+		if len(include.Path) == 0 {
+			return nil, fmt.Errorf("include[%d]: path is required", i)
+		}
+		includes = append(includes, include)
+	}
+	return includes, nil
+}
+
+// loadIncludes loads the files from the "include" section of a compose file,
+// and adds their resources to cfg. Relative paths in the "include" section
+// are resolved relative to baseDir, which is the directory of the including
+// file. Resources in included files must not conflict with resources that
+// are already defined in cfg, unless they are identical.
+func loadIncludes(cfg *types.Config, source any, baseDir string, configDetails types.ConfigDetails, options *Options, includeChain []string) error {
+	includes, err := parseIncludes(source)
+	if err != nil {
+		return err
+	}
+	for _, include := range includes {
+		included, err := loadInclude(include, baseDir, configDetails, options, includeChain)
+		if err != nil {
+			return err
+		}
+		if err := mergeIncluded(cfg, included); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func loadInclude(include includeConfig, baseDir string, configDetails types.ConfigDetails, options *Options, includeChain []string) (*types.Config, error) {
+	paths := make([]string, 0, len(include.Path))
+	for _, p := range include.Path {
+		p = absPath(baseDir, p)
+		if slices.Contains(includeChain, p) {
+			return nil, fmt.Errorf("include cycle detected: %s", strings.Join(append(includeChain, p), " -> "))
+		}
+		paths = append(paths, p)
+	}
+
+	projectDir := filepath.Dir(paths[0])
+	if include.ProjectDirectory != "" {
+		projectDir = absPath(baseDir, include.ProjectDirectory)
+	}
+
+	env, err := includeEnvironment(include, baseDir, configDetails, projectDir)
+	if err != nil {
+		return nil, err
+	}
+
+	configFiles := make([]types.ConfigFile, 0, len(paths))
+	for _, p := range paths {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to include compose file: %w", err)
+		}
+		configDict, err := ParseYAML(b)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", p, err)
+		}
+		configFiles = append(configFiles, types.ConfigFile{Filename: p, Config: configDict})
+	}
+
+	includeDetails := types.ConfigDetails{
+		WorkingDir:  projectDir,
+		ConfigFiles: configFiles,
+		Environment: env,
+	}
+
+	// Included files are interpolated using their own environment.
+	includeOptions := *options
+	if options.Interpolate != nil {
+		interpolate := *options.Interpolate
+		interpolate.LookupValue = includeDetails.LookupEnv
+		includeOptions.Interpolate = &interpolate
+	}
+
+	included, err := load(includeDetails, &includeOptions, append(includeChain, paths...))
+	if err != nil {
+		return nil, err
+	}
+	included.Filename = paths[0]
+	return included, nil
+}
+
+// includeFilename returns the absolute path of a compose file that may
+// include other files. Included files are loaded by their absolute path;
+// other files are resolved relative to the working directory.
+func includeFilename(configDetails types.ConfigDetails, file types.ConfigFile) string {
+	return absPath(configDetails.WorkingDir, file.Filename)
+}
+
+// includeBaseDir returns the directory relative to which the includes of a
+// compose file are resolved; this is the directory of included files, and
+// the working directory for the files that are loaded by [Load].
+func includeBaseDir(configDetails types.ConfigDetails, file types.ConfigFile) string {
+	if filepath.IsAbs(file.Filename) {
+		return filepath.Dir(file.Filename)
+	}
+	return configDetails.WorkingDir
+}
+
+// includeEnvironment returns the environment to use for an included file.
+// Variables from the including environment take precedence over variables
+// read from the include's env-files.
+func includeEnvironment(include includeConfig, baseDir string, configDetails types.ConfigDetails, projectDir string) (map[string]string, error) {
+	envFiles := make([]string, 0, len(include.EnvFile))
+	for _, f := range include.EnvFile {
+		envFiles = append(envFiles, absPath(baseDir, f))
+	}
+	if len(envFiles) == 0 {
+		defaultEnvFile := filepath.Join(projectDir, ".env")
+		if _, err := os.Stat(defaultEnvFile); err == nil {
+			envFiles = append(envFiles, defaultEnvFile)
+		}
+	}
+
+	env := make(map[string]string)
+	for _, f := range envFiles {
+		fileVars, err := parseEnvFile(f)
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(env, opts.ConvertKVStringsToMap(fileVars))
+	}
+	maps.Copy(env, configDetails.Environment)
+	return env, nil
+}
+
+// mergeIncluded adds the resources of an included file to cfg.
+func mergeIncluded(cfg, included *types.Config) error {
+	for _, s := range included.Services {
+		idx := slices.IndexFunc(cfg.Services, func(existing types.ServiceConfig) bool {
+			return existing.Name == s.Name
+		})
+		if idx < 0 {
+			cfg.Services = append(cfg.Services, s)
+			continue
+		}
+		if !reflect.DeepEqual(cfg.Services[idx], s) {
+			return fmt.Errorf("imported compose file %s defines conflicting service %s", included.Filename, s.Name)
+		}
+	}
+
+	var err error
+	if cfg.Networks, err = mergeIncludedResources(cfg.Networks, included.Networks, "network", included.Filename); err != nil {
+		return err
+	}
+	if cfg.Volumes, err = mergeIncludedResources(cfg.Volumes, included.Volumes, "volume", included.Filename); err != nil {
+		return err
+	}
+	if cfg.Secrets, err = mergeIncludedResources(cfg.Secrets, included.Secrets, "secret", included.Filename); err != nil {
+		return err
+	}
+	if cfg.Configs, err = mergeIncludedResources(cfg.Configs, included.Configs, "config", included.Filename); err != nil {
+		return err
+	}
+	return nil
+}
+
+func mergeIncludedResources[T any](dst, src map[string]T, kind, filename string) (map[string]T, error) {
+	for name, resource := range src {
+		if existing, ok := dst[name]; ok {
+			if !reflect.DeepEqual(existing, resource) {
+				return nil, fmt.Errorf("imported compose file %s defines conflicting %s %s", filename, kind, name)
+			}
+			continue
+		}
+		if dst == nil {
+			dst = make(map[string]T, len(src))
+		}
+		dst[name] = resource
+	}
+	return dst, nil
+}
@@ -0,0 +1,275 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package loader
+
+import (
+	"testing"
+
+	"github.com/docker/cli/cli/compose/types"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
+)
+
+func loadWithIncludes(t *testing.T, dir *fs.Dir, yaml string, env map[string]string) (*types.Config, error) {
+	t.Helper()
+	dict, err := ParseYAML([]byte(yaml))
+	assert.NilError(t, err)
+	return Load(types.ConfigDetails{
+		WorkingDir:  dir.Path(),
+		ConfigFiles: []types.ConfigFile{{Filename: "filename.yml", Config: dict}},
+		Environment: env,
+	})
+}
+
+func TestLoadInclude(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithDir("db",
+			fs.WithFile("compose.yml", `
+version: "3.14"
+services:
+  db:
+    image: postgres:${PG_VERSION}
+    env_file: db.env
+    volumes:
+      - ./init:/docker-entrypoint-initdb.d
+networks:
+  backend: {}
+`),
+			fs.WithFile("db.env", "POSTGRES_DB=app\n"),
+			fs.WithFile(".env", "PG_VERSION=16\n"),
+		),
+		fs.WithDir("cache",
+			fs.WithFile("compose.yml", `
+version: "3.14"
+services:
+  cache:
+    image: redis:${REDIS_VERSION}
+networks:
+  backend: {}
+`),
+			fs.WithFile("cache.env", "REDIS_VERSION=7\n"),
+		),
+	)
+
+	config, err := loadWithIncludes(t, dir, `
+version: "3.14"
+include:
+  - db/compose.yml
+  - path: cache/compose.yml
+    env_file: cache/cache.env
+services:
+  web:
+    image: nginx
+`, nil)
+	assert.NilError(t, err)
+
+	services := serviceSort(config.Services)
+	assert.Assert(t, is.Len(services, 3))
+	assert.Check(t, is.Equal(services[0].Name, "cache"))
+	assert.Check(t, is.Equal(services[0].Image, "redis:7"))
+	assert.Check(t, is.Equal(services[1].Name, "db"))
+	assert.Check(t, is.Equal(services[1].Image, "postgres:16"))
+	assert.Check(t, is.DeepEqual(services[1].Environment, types.MappingWithEquals{"POSTGRES_DB": strPtr("app")}))
+	assert.Check(t, is.DeepEqual(services[1].Volumes, []types.ServiceVolumeConfig{
+		{Type: "bind", Source: dir.Join("db", "init"), Target: "/docker-entrypoint-initdb.d"},
+	}))
+	assert.Check(t, is.Equal(services[2].Name, "web"))
+	assert.Check(t, is.DeepEqual(config.Networks, map[string]types.NetworkConfig{"backend": {}}))
+}
+
+func TestLoadIncludeProjectDirectory(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithDir("fragments",
+			fs.WithFile("app.yml", `
+version: "3.14"
+services:
+  app:
+    image: app:${TAG}
+    volumes:
+      - ./data:/data
+`),
+		),
+		fs.WithFile(".env", "TAG=from-env-file\n"),
+	)
+
+	config, err := loadWithIncludes(t, dir, `
+version: "3.14"
+include:
+  - path: fragments/app.yml
+    project_directory: .
+`, map[string]string{"TAG": "from-environment"})
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(config.Services, 1))
+
+	// Variables in the including environment take precedence.
+	assert.Check(t, is.Equal(config.Services[0].Image, "app:from-environment"))
+	assert.Check(t, is.Equal(config.Services[0].Volumes[0].Source, dir.Join("data")))
+}
+
+func TestLoadIncludeMultiplePaths(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithFile("base.yml", `
+version: "3.14"
+services:
+  app:
+    image: app:1.0
+    command: serve
+`),
+		fs.WithFile("override.yml", `
+version: "3.14"
+services:
+  app:
+    image: app:2.0
+`),
+	)
+
+	config, err := loadWithIncludes(t, dir, `
+version: "3.14"
+include:
+  - path: [base.yml, override.yml]
+`, nil)
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(config.Services, 1))
+	assert.Check(t, is.Equal(config.Services[0].Image, "app:2.0"))
+	assert.Check(t, is.DeepEqual(config.Services[0].Command, types.ShellCommand{"serve"}))
+}
+
+func TestLoadIncludeConflict(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithFile("other.yml", `
+version: "3.14"
+services:
+  web:
+    image: httpd
+networks:
+  frontend:
+    driver: overlay
+`),
+	)
+
+	_, err := loadWithIncludes(t, dir, `
+version: "3.14"
+include:
+  - other.yml
+services:
+  web:
+    image: nginx
+`, nil)
+	assert.Check(t, is.Error(err, "imported compose file "+dir.Join("other.yml")+" defines conflicting service web"))
+
+	_, err = loadWithIncludes(t, dir, `
+version: "3.14"
+include:
+  - other.yml
+networks:
+  frontend:
+    driver: bridge
+`, nil)
+	assert.Check(t, is.Error(err, "imported compose file "+dir.Join("other.yml")+" defines conflicting network frontend"))
+}
+
+func TestLoadIncludeCycle(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithFile("a.yml", `
+version: "3.14"
+include:
+  - b.yml
+services:
+  a:
+    image: busybox
+`),
+		fs.WithFile("b.yml", `
+version: "3.14"
+include:
+  - a.yml
+services:
+  b:
+    image: busybox
+`),
+	)
+
+	_, err := loadWithIncludes(t, dir, `
+version: "3.14"
+include:
+  - a.yml
+`, nil)
+	assert.Check(t, is.Error(err, "include cycle detected: "+dir.Join("filename.yml")+" -> "+dir.Join("a.yml")+" -> "+dir.Join("b.yml")+" -> "+dir.Join("a.yml")))
+}
+
+func TestLoadIncludeCycleWithRootFile(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithFile("a.yml", `
+version: "3.14"
+include:
+  - filename.yml
+services:
+  a:
+    image: busybox
+`),
+	)
+
+	_, err := loadWithIncludes(t, dir, `
+version: "3.14"
+include:
+  - a.yml
+`, nil)
+	assert.Check(t, is.Error(err, "include cycle detected: "+dir.Join("filename.yml")+" -> "+dir.Join("a.yml")+" -> "+dir.Join("filename.yml")))
+}
+
+func TestLoadIncludeNested(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithDir("app",
+			fs.WithFile("compose.yml", `
+version: "3.14"
+include:
+  - path: db/compose.yml
+    env_file: db/db.env
+services:
+  app:
+    image: app
+`),
+			fs.WithDir("db",
+				fs.WithFile("compose.yml", `
+version: "3.14"
+services:
+  db:
+    image: postgres:${PG_VERSION}
+`),
+				fs.WithFile("db.env", "PG_VERSION=16\n"),
+			),
+		),
+	)
+
+	// Paths in the "include" section of an included file are relative to
+	// the directory of that file, not to its project directory.
+	config, err := loadWithIncludes(t, dir, `
+version: "3.14"
+include:
+  - path: app/compose.yml
+    project_directory: .
+`, nil)
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(config.Services, 2))
+	assert.Check(t, is.Equal(config.Services[0].Image, "app"))
+	assert.Check(t, is.Equal(config.Services[1].Image, "postgres:16"))
+}
+
+func TestLoadIncludeErrors(t *testing.T) {
+	dir := fs.NewDir(t, t.Name())
+
+	_, err := loadWithIncludes(t, dir, `
+version: "3.14"
+include:
+  - nosuchfile.yml
+`, nil)
+	assert.Check(t, is.ErrorContains(err, "failed to include compose file: open "+dir.Join("nosuchfile.yml")))
+
+	_, err = loadWithIncludes(t, dir, `
+version: "3.13"
+include:
+  - other.yml
+`, nil)
+	assert.Check(t, is.ErrorContains(err, "Additional property include is not allowed"))
+}
@@ -10,6 +10,7 @@
 	"path"
 	"path/filepath"
 	"reflect"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -40,6 +41,10 @@ type Options struct {
 	SkipInterpolation bool
 	// Interpolation options
 	Interpolate *interp.Options
+	// Profiles to enable. Services that specify profiles are only included
+	// if at least one of their profiles is enabled. Use "*" to enable all
+	// profiles.
+	Profiles []string
 	// Discard 'env_file' entries after resolving to 'environment' section
 	discardEnvFiles bool
 }
@@ -93,8 +98,24 @@ func Load(configDetails types.ConfigDetails, opt ...func(*Options)) (*types.Conf
 		op(options)
 	}
 
+	// The files that are loaded are part of the include chain, so that an
+	// included file including them is detected as a cycle.
+	includeChain := make([]string, 0, len(configDetails.ConfigFiles))
+	for _, file := range configDetails.ConfigFiles {
+		includeChain = append(includeChain, includeFilename(configDetails, file))
+	}
+	cfg, err := load(configDetails, options, includeChain)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Services = filterByProfiles(cfg.Services, options.Profiles)
+	return cfg, nil
+}
+
+// load loads and merges the files in configDetails. includeChain holds
+// the files that are being included, and is used to detect include cycles.
+func load(configDetails types.ConfigDetails, options *Options, includeChain []string) (*types.Config, error) {
 	configs := []*types.Config{}
-	var err error
 
 	for _, file := range configDetails.ConfigFiles {
 		configDict := file.Config
@@ -106,33 +127,32 @@ func Load(configDetails types.ConfigDetails, opt ...func(*Options)) (*types.Conf
 			return nil, fmt.Errorf("version mismatched between two composefiles : %v and %v", configDetails.Version, version)
 		}
 
-		if err := validateForbidden(configDict); err != nil {
+		configDict, extends, err := prepareConfigDict(configDict, configDetails.Version, options)
+		if err != nil {
 			return nil, err
 		}
 
-		if !options.SkipInterpolation {
-			configDict, err = interpolateConfig(configDict, *options.Interpolate)
-			if err != nil {
-				return nil, err
-			}
-		}
-
-		if !options.SkipValidation {
-			if err := schema.Validate(configDict, configDetails.Version); err != nil {
-				return nil, err
-			}
-		}
-
 		cfg, err := loadSections(configDict, configDetails)
 		if err != nil {
 			return nil, err
 		}
 		cfg.Filename = file.Filename
+		if len(extends) > 0 {
+			cfg.Services, err = resolveExtends(cfg.Services, getSection(configDict, "services"), extends, configDetails, options)
+			if err != nil {
+				return nil, err
+			}
+		}
 		if options.discardEnvFiles {
 			for i := range cfg.Services {
 				cfg.Services[i].EnvFile = nil
 			}
 		}
+		if include, ok := configDict["include"]; ok {
+			if err := loadIncludes(cfg, include, includeBaseDir(configDetails, file), configDetails, options, includeChain); err != nil {
+				return nil, err
+			}
+		}
 
 		configs = append(configs, cfg)
 	}
@@ -140,6 +160,52 @@ func Load(configDetails types.ConfigDetails, opt ...func(*Options)) (*types.Conf
 	return merge(configs)
 }
 
+// filterByProfiles returns the services that are enabled by the given
+// profiles. Services without profiles are always enabled.
+func filterByProfiles(services []types.ServiceConfig, profiles []string) []types.ServiceConfig {
+	if slices.Contains(profiles, "*") {
+		return services
+	}
+	enabled := make([]types.ServiceConfig, 0, len(services))
+	for _, s := range services {
+		if len(s.Profiles) == 0 || slices.ContainsFunc(s.Profiles, func(p string) bool {
+			return slices.Contains(profiles, p)
+		}) {
+			enabled = append(enabled, s)
+		}
+	}
+	return enabled
+}
+
+// prepareConfigDict validates, interpolates and schema-validates a single
+// compose file. The services' "extends" sections are removed from the returned
+// dict, and returned separately, as they are not part of the schema.
+func prepareConfigDict(configDict map[string]any, version string, options *Options) (map[string]any, map[string]extendsConfig, error) {
+	if err := validateForbidden(configDict); err != nil {
+		return nil, nil, err
+	}
+
+	if !options.SkipInterpolation {
+		var err error
+		configDict, err = interpolateConfig(configDict, *options.Interpolate)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	configDict, extends, err := extractExtends(configDict)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !options.SkipValidation {
+		if err := schema.Validate(configDict, version); err != nil {
+			return nil, nil, err
+		}
+	}
+	return configDict, extends, nil
+}
+
 func validateForbidden(configDict map[string]any) error {
 	servicesDict, ok := configDict["services"].(map[string]any)
 	if !ok {
@@ -184,7 +184,7 @@ func strPtr(val string) *string {
 }
 
 var sampleConfig = types.Config{
-	Version: "3.13",
+	Version: "3.14",
 	Services: []types.ServiceConfig{
 		{
 			Name:        "foo",
@@ -876,8 +876,8 @@ func TestForbiddenProperties(t *testing.T) {
       - /data
     volume_driver: some-driver
   bar:
-    extends:
-      service: foo
+    image: busybox
+    cpu_quota: 50000
 `)
 
 	assert.ErrorType(t, err, &ForbiddenPropertiesError{})
@@ -885,7 +885,7 @@ func TestForbiddenProperties(t *testing.T) {
 	props := err.(*ForbiddenPropertiesError).Properties
 	assert.Check(t, is.Len(props, 2))
 	assert.Check(t, is.Contains(props, "volume_driver"))
-	assert.Check(t, is.Contains(props, "extends"))
+	assert.Check(t, is.Contains(props, "cpu_quota"))
 }
 
 func TestInvalidResource(t *testing.T) {
@@ -1827,3 +1827,76 @@ func TestLoadSecretDriver(t *testing.T) {
 	}
 	assert.Check(t, is.DeepEqual(expected, config, cmpopts.EquateEmpty()))
 }
+
+func TestLoadProfiles(t *testing.T) {
+	dict, err := ParseYAML([]byte(`
+version: "3.14"
+services:
+  web:
+    image: nginx
+  debug:
+    image: busybox
+    profiles: ["debug"]
+  admin:
+    image: adminer
+    profiles: ["admin", "debug"]
+`))
+	assert.NilError(t, err)
+
+	tests := []struct {
+		doc      string
+		profiles []string
+		expected []string
+	}{
+		{
+			doc:      "no profiles",
+			expected: []string{"web"},
+		},
+		{
+			doc:      "single profile",
+			profiles: []string{"admin"},
+			expected: []string{"admin", "web"},
+		},
+		{
+			doc:      "profile enables multiple services",
+			profiles: []string{"debug"},
+			expected: []string{"admin", "debug", "web"},
+		},
+		{
+			doc:      "unknown profile",
+			profiles: []string{"nosuchprofile"},
+			expected: []string{"web"},
+		},
+		{
+			doc:      "all profiles",
+			profiles: []string{"*"},
+			expected: []string{"admin", "debug", "web"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			config, err := Load(buildConfigDetails(dict, nil), func(options *Options) {
+				options.Profiles = tc.profiles
+			})
+			assert.NilError(t, err)
+
+			var names []string
+			for _, s := range config.Services {
+				names = append(names, s.Name)
+			}
+			sort.Strings(names)
+			assert.Check(t, is.DeepEqual(tc.expected, names))
+		})
+	}
+}
+
+func TestLoadProfilesUnsupportedVersion(t *testing.T) {
+	_, err := loadYAML(`
+version: "3.13"
+services:
+  debug:
+    image: busybox
+    profiles: ["debug"]
+`)
+	assert.Check(t, is.ErrorContains(err, "Additional property profiles is not allowed"))
+}
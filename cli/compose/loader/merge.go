@@ -81,6 +81,9 @@ func mergeServices(base, override []types.ServiceConfig) ([]types.ServiceConfig,
 			if err := mergo.Merge(&baseService, &overrideService, mergeOpts...); err != nil {
 				return nil, fmt.Errorf("cannot merge service %s: %w", overrideService.Name, err)
 			}
+			// Profiles are appended by the merge; a profile that is set in
+			// both files must only be listed once.
+			baseService.Profiles = uniqueStrings(baseService.Profiles)
 			baseServices[overrideService.Name] = baseService
 			continue
 		}
@@ -99,6 +102,21 @@ func mergeServices(base, override []types.ServiceConfig) ([]types.ServiceConfig,
 	return services, nil
 }
 
+// uniqueStrings returns the strings in values, without duplicates, in the
+// order in which they first appear.
+func uniqueStrings(values []string) []string {
+	if len(values) == 0 {
+		return values
+	}
+	unique := make([]string, 0, len(values))
+	for _, v := range values {
+		if !slices.Contains(unique, v) {
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
+
 func toServiceSecretConfigsMap(s any) (map[any]any, error) {
 	secrets, ok := s.([]types.ServiceSecretConfig)
 	if !ok {
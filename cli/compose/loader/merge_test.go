@@ -1075,6 +1075,38 @@ func TestLoadMultipleServiceCommands(t *testing.T) {
 	}, config)
 }
 
+func TestLoadMultipleServiceProfiles(t *testing.T) {
+	base := map[string]any{
+		"version": "3.14",
+		"services": map[string]any{
+			"foo": map[string]any{
+				"image":    "baz",
+				"profiles": []any{"debug", "test"},
+			},
+		},
+	}
+	override := map[string]any{
+		"version": "3.14",
+		"services": map[string]any{
+			"foo": map[string]any{
+				"profiles": []any{"test", "frontend"},
+			},
+		},
+	}
+	configDetails := types.ConfigDetails{
+		ConfigFiles: []types.ConfigFile{
+			{Filename: "base.yml", Config: base},
+			{Filename: "override.yml", Config: override},
+		},
+	}
+	config, err := Load(configDetails, func(options *Options) {
+		options.Profiles = []string{"*"}
+	})
+	assert.NilError(t, err)
+	assert.Assert(t, len(config.Services) == 1)
+	assert.DeepEqual(t, config.Services[0].Profiles, []string{"debug", "test", "frontend"})
+}
+
 func TestLoadMultipleServiceVolumes(t *testing.T) {
 	base := map[string]any{
 		"version": "3.7",
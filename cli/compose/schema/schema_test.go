@@ -211,6 +211,7 @@ func TestValidateCredentialSpecs(t *testing.T) {
 		{version: "3.11"},
 		{version: "3.12"},
 		{version: "3.13"},
+		{version: "3.14"},
 		{version: "3"},
 		{version: ""},
 	}
@@ -75,6 +75,9 @@ type ConfigFile struct {
 	Plugins              map[string]map[string]string `json:"plugins,omitempty"`
 	Aliases              map[string]string            `json:"aliases,omitempty"`
 	Features             map[string]string            `json:"features,omitempty"`
+	ConnectionHelpers    map[string]ConnectionHelper  `json:"connectionHelpers,omitempty"`
+	DefaultPlatform      string                       `json:"defaultPlatform,omitempty"`
+	Overridden           map[string]json.RawMessage   `json:"-"` // Note: for internal use only
 }
 
 type configEnvAuth struct {
@@ -111,6 +114,15 @@ type ProxyConfig struct {
 	AllProxy   string `json:"allProxy,omitempty"`
 }
 
+// ConnectionHelper contains the command used to connect to daemon hosts
+// that use a custom URL scheme. The daemon host is appended as the last
+// argument of the command, which is expected to connect its stdin and stdout
+// to the daemon's API.
+type ConnectionHelper struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args,omitempty"`
+}
+
 // New initializes an empty configuration file for the given filename 'fn'
 func New(fn string) *ConfigFile {
 	return &ConfigFile{
@@ -162,6 +174,16 @@ func (c *ConfigFile) GetAuthConfigs() map[string]types.AuthConfig {
 // SaveToWriter encodes and writes out all the authorization information to
 // the given writer
 func (c *ConfigFile) SaveToWriter(writer io.Writer) error {
+	if len(c.Overridden) > 0 {
+		// Settings that are overridden, for example by the current context,
+		// are not saved.
+		cfg, err := c.withoutOverrides()
+		if err != nil {
+			return err
+		}
+		c = cfg
+	}
+
 	// Encode sensitive data into a new/temp struct
 	tmpAuthConfigs := make(map[string]types.AuthConfig, len(c.AuthConfigs))
 	for k, authConfig := range c.AuthConfigs {
@@ -321,7 +343,11 @@ func decodeAuth(authStr string) (string, string, error) {
 func (c *ConfigFile) GetCredentialsStore(registryHostname string) credentials.Store {
 	store := credentials.NewFileStore(c)
 
-	if helper := getConfiguredCredentialStore(c, getAuthConfigKey(registryHostname)); helper != "" {
+	switch helper := getConfiguredCredentialStore(c, getAuthConfigKey(registryHostname)); helper {
+	case "":
+	case credentials.EncryptedFileStoreName:
+		store = credentials.NewEncryptedFileStore(c)
+	default:
 		store = newNativeStore(c, helper)
 	}
 
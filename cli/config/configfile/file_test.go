@@ -5,6 +5,7 @@
 	"encoding/json"
 	"errors"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/docker/cli/cli/config/credentials"
@@ -239,6 +240,24 @@ func TestGetAllCredentialsCredsStore(t *testing.T) {
 	assert.Check(t, is.Equal(1, testCredsStore.(*mockNativeStore).GetAllCallCount))
 }
 
+func TestGetCredentialsStoreEncryptedFile(t *testing.T) {
+	t.Setenv(credentials.EnvPassphrase, "passphrase")
+	configFile := New(filepath.Join(t.TempDir(), "config.json"))
+	configFile.CredentialsStore = credentials.EncryptedFileStoreName
+	expectedAuth := types.AuthConfig{
+		Username:      "user",
+		Password:      "pass",
+		ServerAddress: "example.com",
+	}
+
+	assert.NilError(t, configFile.GetCredentialsStore("example.com").Store(expectedAuth))
+	assert.Check(t, is.DeepEqual(configFile.AuthConfigs["example.com"], types.AuthConfig{ServerAddress: "example.com"}))
+
+	authConfig, err := configFile.GetAuthConfig("example.com")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(authConfig, expectedAuth))
+}
+
 func TestGetAllCredentialsCredStoreErrorHandling(t *testing.T) {
 	const (
 		workingHelperRegistryHostname = "working-helper.example.com"
@@ -0,0 +1,99 @@
+package configfile
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// overridableSettings are the settings that can be overridden with
+// [ConfigFile.SetOverrides], by their name in the configuration file. Each
+// function resets the setting to its default value.
+//
+// Settings that configure credentials, plugins, or commands to execute can
+// not be overridden, as overrides may come from an untrusted source, such
+// as an imported context.
+var overridableSettings = map[string]func(c *ConfigFile){
+	"HttpHeaders":          func(c *ConfigFile) { c.HTTPHeaders = nil },
+	"psFormat":             func(c *ConfigFile) { c.PsFormat = "" },
+	"imagesFormat":         func(c *ConfigFile) { c.ImagesFormat = "" },
+	"networksFormat":       func(c *ConfigFile) { c.NetworksFormat = "" },
+	"pluginsFormat":        func(c *ConfigFile) { c.PluginsFormat = "" },
+	"volumesFormat":        func(c *ConfigFile) { c.VolumesFormat = "" },
+	"statsFormat":          func(c *ConfigFile) { c.StatsFormat = "" },
+	"detachKeys":           func(c *ConfigFile) { c.DetachKeys = "" },
+	"serviceInspectFormat": func(c *ConfigFile) { c.ServiceInspectFormat = "" },
+	"servicesFormat":       func(c *ConfigFile) { c.ServicesFormat = "" },
+	"tasksFormat":          func(c *ConfigFile) { c.TasksFormat = "" },
+	"secretFormat":         func(c *ConfigFile) { c.SecretFormat = "" },
+	"configFormat":         func(c *ConfigFile) { c.ConfigFormat = "" },
+	"nodesFormat":          func(c *ConfigFile) { c.NodesFormat = "" },
+	"pruneFilters":         func(c *ConfigFile) { c.PruneFilters = nil },
+	"proxies":              func(c *ConfigFile) { c.Proxies = nil },
+	"defaultPlatform":      func(c *ConfigFile) { c.DefaultPlatform = "" },
+}
+
+// SetOverrides layers the given settings over the settings that are loaded
+// from the configuration file. Settings are replaced as a whole; for example,
+// overriding "proxies" replaces the proxy configuration for all hosts.
+//
+// Overridden settings are not written when saving the configuration file;
+// the values from the configuration file are preserved instead. An error is
+// returned, and no settings are changed, if a setting can not be overridden.
+func (c *ConfigFile) SetOverrides(overrides map[string]json.RawMessage) error {
+	for name, value := range overrides {
+		if _, ok := overridableSettings[name]; !ok {
+			return fmt.Errorf("setting %q cannot be overridden", name)
+		}
+		if err := json.Unmarshal(overrideJSON(name, value), &ConfigFile{}); err != nil {
+			return fmt.Errorf("invalid value for setting %q: %w", name, err)
+		}
+	}
+	if len(overrides) == 0 {
+		return nil
+	}
+
+	current, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	var original map[string]json.RawMessage
+	if err := json.Unmarshal(current, &original); err != nil {
+		return err
+	}
+	if c.Overridden == nil {
+		c.Overridden = make(map[string]json.RawMessage, len(overrides))
+	}
+	for name, value := range overrides {
+		if _, ok := c.Overridden[name]; !ok {
+			c.Overridden[name] = original[name]
+		}
+		overridableSettings[name](c)
+		if err := json.Unmarshal(overrideJSON(name, value), c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withoutOverrides returns a shallow copy of the configuration file with the
+// overridden settings restored to their values in the configuration file.
+func (c *ConfigFile) withoutOverrides() (*ConfigFile, error) {
+	cfg := *c
+	cfg.Overridden = nil
+	for name, value := range c.Overridden {
+		overridableSettings[name](&cfg)
+		if value == nil {
+			continue
+		}
+		if err := json.Unmarshal(overrideJSON(name, value), &cfg); err != nil {
+			return nil, err
+		}
+	}
+	return &cfg, nil
+}
+
+// overrideJSON returns a JSON object that only contains the given setting.
+func overrideJSON(name string, value json.RawMessage) []byte {
+	key, _ := json.Marshal(name)
+	return []byte("{" + string(key) + ":" + string(value) + "}")
+}
@@ -0,0 +1,90 @@
+package configfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestSetOverrides(t *testing.T) {
+	configFile := New("config.json")
+	assert.NilError(t, configFile.LoadFromReader(strings.NewReader(`{
+		"psFormat": "table {{.ID}}",
+		"detachKeys": "ctrl-x",
+		"proxies": {"default": {"httpProxy": "http://proxy.example.com"}, "tcp://example.com": {"noProxy": "*.example.com"}}
+	}`)))
+
+	err := configFile.SetOverrides(map[string]json.RawMessage{
+		"psFormat":     json.RawMessage(`"table {{.Names}}"`),
+		"imagesFormat": json.RawMessage(`"{{.Repository}}"`),
+		"proxies":      json.RawMessage(`{"default": {"httpsProxy": "https://proxy.example.org"}}`),
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(configFile.PsFormat, "table {{.Names}}"))
+	assert.Check(t, is.Equal(configFile.ImagesFormat, "{{.Repository}}"))
+	assert.Check(t, is.Equal(configFile.DetachKeys, "ctrl-x"))
+	assert.Check(t, is.DeepEqual(configFile.Proxies, map[string]ProxyConfig{
+		"default": {HTTPSProxy: "https://proxy.example.org"},
+	}))
+
+	// Overridden settings are not saved.
+	configFile.CurrentContext = "production"
+	var buf bytes.Buffer
+	assert.NilError(t, configFile.SaveToWriter(&buf))
+	assert.Check(t, is.Equal(buf.String(), `{
+	"auths": {},
+	"psFormat": "table {{.ID}}",
+	"detachKeys": "ctrl-x",
+	"proxies": {
+		"default": {
+			"httpProxy": "http://proxy.example.com"
+		},
+		"tcp://example.com": {
+			"noProxy": "*.example.com"
+		}
+	},
+	"currentContext": "production"
+}`))
+	assert.Check(t, is.Equal(configFile.PsFormat, "table {{.Names}}"))
+}
+
+func TestSetOverridesErrors(t *testing.T) {
+	tests := []struct {
+		doc         string
+		overrides   map[string]json.RawMessage
+		expectedErr string
+	}{
+		{
+			doc:         "not overridable",
+			overrides:   map[string]json.RawMessage{"credsStore": json.RawMessage(`"secretservice"`)},
+			expectedErr: `setting "credsStore" cannot be overridden`,
+		},
+		{
+			doc:         "unknown setting",
+			overrides:   map[string]json.RawMessage{"noSuchSetting": json.RawMessage(`true`)},
+			expectedErr: `setting "noSuchSetting" cannot be overridden`,
+		},
+		{
+			doc:         "invalid type",
+			overrides:   map[string]json.RawMessage{"pruneFilters": json.RawMessage(`"label=foo"`)},
+			expectedErr: `invalid value for setting "pruneFilters"`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			configFile := New("config.json")
+			configFile.PsFormat = "table {{.ID}}"
+			overrides := map[string]json.RawMessage{"psFormat": json.RawMessage(`"{{.Names}}"`)}
+			maps.Copy(overrides, tc.overrides)
+			err := configFile.SetOverrides(overrides)
+			assert.Check(t, is.ErrorContains(err, tc.expectedErr))
+			assert.Check(t, is.Equal(configFile.PsFormat, "table {{.ID}}"))
+			assert.Check(t, is.Nil(configFile.Overridden))
+		})
+	}
+}
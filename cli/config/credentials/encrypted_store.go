@@ -0,0 +1,359 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package credentials
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/docker/cli/cli/config/types"
+)
+
+// EncryptedFileStoreName is the name of the built-in credentials store that
+// keeps credentials encrypted in a file in the configuration directory. It
+// is used by setting "credsStore", or the "credHelpers" of a registry, to
+// this name. It can be used on systems that have no credential helper, such
+// as headless servers, instead of storing credentials unencrypted in the
+// configuration file.
+const EncryptedFileStoreName = "encrypted-file"
+
+const (
+	// EnvPassphrase is the name of the environment variable that holds the
+	// passphrase to encrypt the credentials of the encrypted file store with.
+	EnvPassphrase = "DOCKER_CREDENTIALS_PASSPHRASE" //nolint:gosec // ignore G101: Potential hardcoded credentials
+
+	// EnvKeyFile is the name of the environment variable that holds the path
+	// of a file that contains the passphrase to encrypt the credentials of the
+	// encrypted file store with. It is ignored if [EnvPassphrase] is set.
+	EnvKeyFile = "DOCKER_CREDENTIALS_KEY_FILE"
+
+	// encryptedFileName is the name of the file in the configuration
+	// directory that holds the encrypted credentials.
+	encryptedFileName = "credentials.enc"
+
+	// keyFileName is the name of the key file in the configuration directory
+	// that is used if no passphrase or key file is set. The key file is
+	// created with a random passphrase when credentials are first stored.
+	keyFileName = "credentials.key"
+
+	encryptedFileVersion = 1
+	pbkdf2Iterations     = 600_000
+	saltSize             = 16
+)
+
+// keyFileWarning warns the user that the key file that is created if no
+// passphrase is configured does not protect the credentials from anyone who
+// can read the configuration directory.
+const keyFileWarning = `
+WARNING! Your credentials are encrypted with a key that is stored in '%s',
+next to the credentials. This does not protect them from anyone who can read
+that directory. Set ` + EnvPassphrase + ` or ` + EnvKeyFile + `
+to encrypt them with a passphrase that is not stored with them.
+`
+
+// encryptedFile is the format of the file that holds the encrypted
+// credentials. Data is the JSON-encoded map of credentials, encrypted with
+// AES-256-GCM, using a key that is derived from the passphrase and salt with
+// PBKDF2-SHA256.
+type encryptedFile struct {
+	Version int    `json:"version"`
+	Salt    []byte `json:"salt"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"`
+}
+
+// encryptedStore implements a credentials store that keeps credentials
+// encrypted in a file in the configuration directory. Like the native
+// store, it keeps an entry without credentials in the configuration file.
+type encryptedStore struct {
+	file      store
+	fileStore Store
+}
+
+// NewEncryptedFileStore creates a new credentials store that keeps the
+// credentials encrypted in a file next to the configuration file. The
+// passphrase to encrypt the credentials with is taken from [EnvPassphrase],
+// the file in [EnvKeyFile], or a key file in the configuration directory.
+func NewEncryptedFileStore(file store) Store {
+	return &encryptedStore{
+		file:      file,
+		fileStore: NewFileStore(file),
+	}
+}
+
+// Erase removes the given credentials from the encrypted file store.
+func (c *encryptedStore) Erase(serverAddress string) error {
+	auths, salt, err := c.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := auths[serverAddress]; ok {
+		delete(auths, serverAddress)
+		if err := c.save(auths, salt); err != nil {
+			return err
+		}
+	}
+	return c.fileStore.Erase(serverAddress)
+}
+
+// Get retrieves credentials for a specific server from the encrypted file store.
+func (c *encryptedStore) Get(serverAddress string) (types.AuthConfig, error) {
+	auth, _ := c.fileStore.Get(serverAddress)
+
+	auths, _, err := c.load()
+	if err != nil {
+		return auth, err
+	}
+	creds, ok := auths[serverAddress]
+	if !ok {
+		return auth, nil
+	}
+	auth.Username = creds.Username
+	auth.Password = creds.Password
+	auth.IdentityToken = creds.IdentityToken
+	auth.RegistryToken = creds.RegistryToken
+	auth.ServerAddress = serverAddress
+	return auth, nil
+}
+
+// GetAll retrieves all the credentials from the encrypted file store.
+func (c *encryptedStore) GetAll() (map[string]types.AuthConfig, error) {
+	auths, _, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+	fileConfigs, _ := c.fileStore.GetAll()
+
+	authConfigs := make(map[string]types.AuthConfig, len(auths))
+	for registry, creds := range auths {
+		ac := fileConfigs[registry]
+		ac.Username = creds.Username
+		ac.Password = creds.Password
+		ac.IdentityToken = creds.IdentityToken
+		ac.RegistryToken = creds.RegistryToken
+		ac.ServerAddress = registry
+		authConfigs[registry] = ac
+	}
+	return authConfigs, nil
+}
+
+// Store saves the given credentials in the encrypted file store.
+func (c *encryptedStore) Store(authConfig types.AuthConfig) error {
+	auths, salt, err := c.load()
+	if err != nil {
+		return err
+	}
+	auths[authConfig.ServerAddress] = types.AuthConfig{
+		Username:      authConfig.Username,
+		Password:      authConfig.Password,
+		IdentityToken: authConfig.IdentityToken,
+		RegistryToken: authConfig.RegistryToken,
+	}
+	if err := c.save(auths, salt); err != nil {
+		return err
+	}
+
+	authConfig.Username = ""
+	authConfig.Password = ""
+	authConfig.IdentityToken = ""
+	authConfig.RegistryToken = ""
+	return c.fileStore.Store(authConfig)
+}
+
+// dir returns the directory that holds the encrypted file and key file.
+func (c *encryptedStore) dir() (string, error) {
+	fileName := c.file.GetFilename()
+	if fileName == "" {
+		return "", errors.New("the encrypted credentials store requires a configuration file")
+	}
+	return filepath.Dir(fileName), nil
+}
+
+// load decrypts the credentials from the encrypted file, and returns them
+// with the salt that was used to encrypt them. An empty map and nil salt are
+// returned if no credentials are stored yet.
+func (c *encryptedStore) load() (map[string]types.AuthConfig, []byte, error) {
+	dir, err := c.dir()
+	if err != nil {
+		return nil, nil, err
+	}
+	fileName := filepath.Join(dir, encryptedFileName)
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return make(map[string]types.AuthConfig), nil, nil
+		}
+		return nil, nil, err
+	}
+
+	var f encryptedFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, nil, fmt.Errorf("invalid encrypted credentials file (%s): %w", fileName, err)
+	}
+	if f.Version != encryptedFileVersion {
+		return nil, nil, fmt.Errorf("unsupported version of encrypted credentials file (%s): %d", fileName, f.Version)
+	}
+	gcm, err := newCipher(dir, f.Salt, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	plaintext, err := gcm.Open(nil, f.Nonce, f.Data, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decrypt credentials in %s: the passphrase may be incorrect", fileName)
+	}
+
+	auths := make(map[string]types.AuthConfig)
+	if err := json.Unmarshal(plaintext, &auths); err != nil {
+		return nil, nil, fmt.Errorf("invalid encrypted credentials file (%s): %w", fileName, err)
+	}
+	return auths, f.Salt, nil
+}
+
+// save encrypts the credentials, and replaces the encrypted file with them.
+func (c *encryptedStore) save(auths map[string]types.AuthConfig, salt []byte) (retErr error) {
+	dir, err := c.dir()
+	if err != nil {
+		return err
+	}
+	if salt == nil {
+		salt = make([]byte, saltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return err
+		}
+	}
+	gcm, err := newCipher(dir, salt, true)
+	if err != nil {
+		return err
+	}
+	plaintext, err := json.Marshal(auths)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(encryptedFile{
+		Version: encryptedFileVersion,
+		Salt:    salt,
+		Nonce:   nonce,
+		Data:    gcm.Seal(nil, nonce, plaintext, nil),
+	}, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	temp, err := os.CreateTemp(dir, encryptedFileName)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = temp.Close()
+		if retErr != nil {
+			_ = os.Remove(temp.Name())
+		}
+	}()
+	if _, err := temp.Write(data); err != nil {
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("error closing temp file: %w", err)
+	}
+	return os.Rename(temp.Name(), filepath.Join(dir, encryptedFileName))
+}
+
+// derivedKeys caches the keys derived from a passphrase and salt, as
+// deriving a key is slow by design, and the credentials may be read more
+// than once per CLI invocation.
+var derivedKeys sync.Map
+
+// newCipher returns the cipher to encrypt the credentials with, using a key
+// that is derived from the passphrase and salt. If create is set, a key file
+// is created in the directory if no passphrase is configured.
+func newCipher(dir string, salt []byte, create bool) (cipher.AEAD, error) {
+	passphrase, err := getPassphrase(dir, create)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKey := sha256.Sum256(append(append([]byte{}, salt...), passphrase...))
+	key, ok := derivedKeys.Load(cacheKey)
+	if !ok {
+		k, err := pbkdf2.Key(sha256.New, string(passphrase), salt, pbkdf2Iterations, 32)
+		if err != nil {
+			return nil, err
+		}
+		key, _ = derivedKeys.LoadOrStore(cacheKey, k)
+	}
+
+	block, err := aes.NewCipher(key.([]byte))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// getPassphrase returns the passphrase from [EnvPassphrase], the file in
+// [EnvKeyFile], or the key file in the directory. If create is set, and
+// none of these are set, the key file is created with a random passphrase.
+func getPassphrase(dir string, create bool) ([]byte, error) {
+	if p := os.Getenv(EnvPassphrase); p != "" {
+		return []byte(p), nil
+	}
+
+	keyFile := os.Getenv(EnvKeyFile)
+	isDefault := keyFile == ""
+	if isDefault {
+		keyFile = filepath.Join(dir, keyFileName)
+	}
+	p, err := os.ReadFile(keyFile)
+	switch {
+	case err == nil:
+		p = bytes.TrimRight(p, "\r\n")
+		if len(p) == 0 {
+			return nil, fmt.Errorf("key file for encrypted credentials is empty: %s", keyFile)
+		}
+		return p, nil
+	case !os.IsNotExist(err):
+		return nil, fmt.Errorf("failed to read key file for encrypted credentials: %w", err)
+	case !isDefault || !create:
+		return nil, fmt.Errorf("no passphrase to decrypt credentials: set %s or %s, or restore the key file (%s)", EnvPassphrase, EnvKeyFile, keyFile)
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	p = []byte(hex.EncodeToString(key))
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(keyFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key file for encrypted credentials: %w", err)
+	}
+	if _, err := f.Write(append(p, '\n')); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("failed to create key file for encrypted credentials: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("failed to create key file for encrypted credentials: %w", err)
+	}
+	_, _ = fmt.Fprintln(os.Stderr, fmt.Sprintf(keyFileWarning, keyFile))
+	return p, nil
+}
@@ -0,0 +1,157 @@
+package credentials
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/config/types"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// configFileInDir is a fakeStore with a configuration file in a directory,
+// next to which the encrypted credentials are stored.
+type configFileInDir struct {
+	fakeStore
+	filename string
+}
+
+func (f *configFileInDir) GetFilename() string {
+	return f.filename
+}
+
+func newConfigFileInDir(t *testing.T) *configFileInDir {
+	t.Helper()
+	return &configFileInDir{
+		fakeStore: fakeStore{configs: map[string]types.AuthConfig{}},
+		filename:  filepath.Join(t.TempDir(), "config.json"),
+	}
+}
+
+// captureStderr returns what is written to os.Stderr while running fn.
+func captureStderr(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	assert.NilError(t, err)
+	stderr := os.Stderr
+	os.Stderr = w
+	defer func() { os.Stderr = stderr }()
+
+	out := make(chan []byte)
+	go func() {
+		b, _ := io.ReadAll(r)
+		out <- b
+	}()
+	fn()
+	assert.NilError(t, w.Close())
+	return string(<-out)
+}
+
+func TestEncryptedStore(t *testing.T) {
+	t.Setenv(EnvPassphrase, "")
+	t.Setenv(EnvKeyFile, "")
+	f := newConfigFileInDir(t)
+	dir := filepath.Dir(f.filename)
+	s := NewEncryptedFileStore(f)
+
+	ac, err := s.Get("registry.example.com")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(ac, types.AuthConfig{}))
+
+	stderr := captureStderr(t, func() {
+		assert.NilError(t, s.Store(types.AuthConfig{
+			Username:      "user",
+			Password:      "secret-password",
+			ServerAddress: "registry.example.com",
+		}))
+		assert.NilError(t, s.Store(types.AuthConfig{
+			IdentityToken: "secret-token",
+			ServerAddress: "https://index.docker.io/v1/",
+		}))
+		assert.NilError(t, s.Store(types.AuthConfig{
+			RegistryToken: "secret-registry-token",
+			ServerAddress: "token.example.com",
+		}))
+	})
+	assert.Check(t, is.Equal(strings.Count(stderr, "WARNING!"), 1), "warning must be printed once, when creating the key file")
+	assert.Check(t, is.Contains(stderr, "WARNING! Your credentials are encrypted with a key that is stored in '"+filepath.Join(dir, keyFileName)+"'"))
+
+	t.Run("credentials are not stored in plain text", func(t *testing.T) {
+		assert.Check(t, is.DeepEqual(f.configs, map[string]types.AuthConfig{
+			"registry.example.com":        {ServerAddress: "registry.example.com"},
+			"https://index.docker.io/v1/": {ServerAddress: "https://index.docker.io/v1/"},
+			"token.example.com":           {ServerAddress: "token.example.com"},
+		}))
+		data, err := os.ReadFile(filepath.Join(dir, encryptedFileName))
+		assert.NilError(t, err)
+		assert.Check(t, !strings.Contains(string(data), "secret"))
+
+		st, err := os.Stat(filepath.Join(dir, keyFileName))
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(st.Mode().Perm(), os.FileMode(0o600)))
+	})
+
+	t.Run("get", func(t *testing.T) {
+		ac, err := NewEncryptedFileStore(f).Get("registry.example.com")
+		assert.NilError(t, err)
+		assert.Check(t, is.DeepEqual(ac, types.AuthConfig{
+			Username:      "user",
+			Password:      "secret-password",
+			ServerAddress: "registry.example.com",
+		}))
+	})
+
+	t.Run("get all", func(t *testing.T) {
+		all, err := NewEncryptedFileStore(f).GetAll()
+		assert.NilError(t, err)
+		assert.Check(t, is.DeepEqual(all, map[string]types.AuthConfig{
+			"registry.example.com":        {Username: "user", Password: "secret-password", ServerAddress: "registry.example.com"},
+			"https://index.docker.io/v1/": {IdentityToken: "secret-token", ServerAddress: "https://index.docker.io/v1/"},
+			"token.example.com":           {RegistryToken: "secret-registry-token", ServerAddress: "token.example.com"},
+		}))
+	})
+
+	t.Run("erase", func(t *testing.T) {
+		assert.NilError(t, NewEncryptedFileStore(f).Erase("registry.example.com"))
+		ac, err := NewEncryptedFileStore(f).Get("registry.example.com")
+		assert.NilError(t, err)
+		assert.Check(t, is.DeepEqual(ac, types.AuthConfig{}))
+		assert.Check(t, is.Len(f.configs, 2))
+	})
+
+	t.Run("missing key file", func(t *testing.T) {
+		assert.NilError(t, os.Remove(filepath.Join(dir, keyFileName)))
+		_, err := NewEncryptedFileStore(f).Get("https://index.docker.io/v1/")
+		assert.Check(t, is.ErrorContains(err, "no passphrase to decrypt credentials"))
+	})
+}
+
+func TestEncryptedStorePassphrase(t *testing.T) {
+	t.Setenv(EnvPassphrase, "correct horse battery staple")
+	f := newConfigFileInDir(t)
+	dir := filepath.Dir(f.filename)
+
+	assert.NilError(t, NewEncryptedFileStore(f).Store(types.AuthConfig{
+		Username:      "user",
+		Password:      "secret-password",
+		ServerAddress: "registry.example.com",
+	}))
+	_, err := os.Stat(filepath.Join(dir, keyFileName))
+	assert.Check(t, os.IsNotExist(err), "no key file must be created if a passphrase is set")
+
+	t.Setenv(EnvPassphrase, "wrong passphrase")
+	_, err = NewEncryptedFileStore(f).Get("registry.example.com")
+	assert.Check(t, is.ErrorContains(err, "the passphrase may be incorrect"))
+
+	// A key file holds the passphrase, which is used if no passphrase is set.
+	keyFile := filepath.Join(t.TempDir(), "key")
+	assert.NilError(t, os.WriteFile(keyFile, []byte("correct horse battery staple\n"), 0o600))
+	t.Setenv(EnvPassphrase, "")
+	t.Setenv(EnvKeyFile, keyFile)
+	ac, err := NewEncryptedFileStore(f).Get("registry.example.com")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(ac.Password, "secret-password"))
+}
@@ -67,7 +67,8 @@ func (c *fileStore) GetAll() (map[string]types.AuthConfig, error) {
 // logs and fix things.
 const unencryptedWarning = `
 WARNING! Your credentials are stored unencrypted in '%s'.
-Configure a credential helper to remove this warning. See
+Configure a credential helper to remove this warning, or set
+"credsStore" to "` + EncryptedFileStoreName + `" to store them encrypted. See
 https://docs.docker.com/go/credential-store/
 `
 
@@ -83,20 +84,18 @@ func (c *fileStore) Store(authConfig types.AuthConfig) error {
 		// Credentials didn't change, so skip updating the configuration file.
 		return nil
 	}
-	authConfigs[authConfig.ServerAddress] = authConfig
-	if err := c.file.Save(); err != nil {
-		return err
-	}
 
-	if !alreadyPrinted.Load() && authConfig.Password != "" {
-		// Display a warning if we're storing the users password (not a token).
+	if !alreadyPrinted.Load() && (authConfig.Password != "" || authConfig.IdentityToken != "") {
+		// Display a warning before storing the users password or token,
+		// so that the warning is printed even if saving the file fails.
 		//
 		// FIXME(thaJeztah): make output configurable instead of hardcoding to os.Stderr
 		_, _ = fmt.Fprintln(os.Stderr, fmt.Sprintf(unencryptedWarning, c.file.GetFilename()))
 		alreadyPrinted.Store(true)
 	}
 
-	return nil
+	authConfigs[authConfig.ServerAddress] = authConfig
+	return c.file.Save()
 }
 
 // ConvertToHostname normalizes a registry URL which has http|https prepended
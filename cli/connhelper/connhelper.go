@@ -12,6 +12,7 @@
 	"slices"
 	"strings"
 
+	"github.com/docker/cli/cli/config/configfile"
 	"github.com/docker/cli/cli/connhelper/commandconn"
 	"github.com/docker/cli/cli/connhelper/ssh"
 )
@@ -27,7 +28,20 @@ type ConnectionHelper struct {
 //
 // ssh://<user>@<host> URL requires Docker 18.09 or later on the remote host.
 func GetConnectionHelper(daemonURL string) (*ConnectionHelper, error) {
-	return getConnectionHelper(daemonURL, nil)
+	return getConnectionHelper(daemonURL, nil, nil)
+}
+
+// GetConnectionHelperWithConfig returns Docker-specific connection helper for
+// the given URL. In addition to the built-in helpers, it uses the connection
+// helpers configured in the "connectionHelpers" section of the config-file
+// for custom URL schemes. It returns nil without error when no helper is
+// registered for the scheme.
+func GetConnectionHelperWithConfig(daemonURL string, configFile *configfile.ConfigFile) (*ConnectionHelper, error) {
+	var helpers map[string]configfile.ConnectionHelper
+	if configFile != nil {
+		helpers = configFile.ConnectionHelpers
+	}
+	return getConnectionHelper(daemonURL, nil, helpers)
 }
 
 // GetConnectionHelperWithSSHOpts returns Docker-specific connection helper for
@@ -36,14 +50,21 @@ func GetConnectionHelper(daemonURL string) (*ConnectionHelper, error) {
 //
 // Requires Docker 18.09 or later on the remote host.
 func GetConnectionHelperWithSSHOpts(daemonURL string, sshFlags []string) (*ConnectionHelper, error) {
-	return getConnectionHelper(daemonURL, sshFlags)
+	return getConnectionHelper(daemonURL, sshFlags, nil)
 }
 
-func getConnectionHelper(daemonURL string, sshFlags []string) (*ConnectionHelper, error) {
+// builtinSchemes are the URL schemes that are handled by the CLI, and for
+// which no connection helper can be configured.
+var builtinSchemes = []string{"ssh", "tcp", "unix", "npipe", "fd", "http", "https"}
+
+func getConnectionHelper(daemonURL string, sshFlags []string, helpers map[string]configfile.ConnectionHelper) (*ConnectionHelper, error) {
 	u, err := url.Parse(daemonURL)
 	if err != nil {
 		return nil, err
 	}
+	if _, ok := helpers[u.Scheme]; ok && slices.Contains(builtinSchemes, u.Scheme) {
+		return nil, fmt.Errorf("invalid connection helper for %s://: scheme is handled by the docker CLI", u.Scheme)
+	}
 	if u.Scheme == "ssh" {
 		sp, err := ssh.NewSpec(u)
 		if err != nil {
@@ -68,8 +89,18 @@ func getConnectionHelper(daemonURL string, sshFlags []string) (*ConnectionHelper
 			Host: "http://docker.example.com",
 		}, nil
 	}
-	// Future version may support plugins via ~/.docker/config.json. e.g. "dind"
-	// See docker/cli#889 for the previous discussion.
+	if h, ok := helpers[u.Scheme]; ok {
+		if h.Command == "" {
+			return nil, fmt.Errorf("invalid connection helper for %s://: no command specified", u.Scheme)
+		}
+		args := append(slices.Clone(h.Args), daemonURL)
+		return &ConnectionHelper{
+			Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				return commandconn.New(ctx, h.Command, args...)
+			},
+			Host: "http://docker.example.com",
+		}, nil
+	}
 	return nil, err
 }
 
@@ -3,7 +3,9 @@
 import (
 	"testing"
 
+	"github.com/docker/cli/cli/config/configfile"
 	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
 )
 
 func TestSSHFlags(t *testing.T) {
@@ -61,3 +63,42 @@ func TestDisablePseudoTerminalAllocation(t *testing.T) {
 		})
 	}
 }
+
+func TestConnectionHelperFromConfig(t *testing.T) {
+	configFile := &configfile.ConfigFile{
+		ConnectionHelpers: map[string]configfile.ConnectionHelper{
+			"kube":  {Command: "kubectl-docker", Args: []string{"--namespace", "ci"}},
+			"empty": {},
+			"tcp":   {Command: "my-tcp-helper"},
+		},
+	}
+
+	t.Run("configured scheme", func(t *testing.T) {
+		helper, err := GetConnectionHelperWithConfig("kube://mypod", configFile)
+		assert.NilError(t, err)
+		assert.Assert(t, helper != nil)
+		assert.Check(t, is.Equal(helper.Host, "http://docker.example.com"))
+	})
+
+	t.Run("unknown scheme", func(t *testing.T) {
+		helper, err := GetConnectionHelperWithConfig("vm://myvm", configFile)
+		assert.NilError(t, err)
+		assert.Check(t, helper == nil)
+	})
+
+	t.Run("no config", func(t *testing.T) {
+		helper, err := GetConnectionHelperWithConfig("kube://mypod", nil)
+		assert.NilError(t, err)
+		assert.Check(t, helper == nil)
+	})
+
+	t.Run("no command", func(t *testing.T) {
+		_, err := GetConnectionHelperWithConfig("empty://foo", configFile)
+		assert.Check(t, is.Error(err, "invalid connection helper for empty://: no command specified"))
+	})
+
+	t.Run("built-in scheme", func(t *testing.T) {
+		_, err := GetConnectionHelperWithConfig("tcp://localhost:2375", configFile)
+		assert.Check(t, is.Error(err, "invalid connection helper for tcp://: scheme is handled by the docker CLI"))
+	})
+}
@@ -0,0 +1,31 @@
+//go:build !windows
+
+package connhelper
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestConnectionHelperFromConfigDial(t *testing.T) {
+	configFile := &configfile.ConfigFile{
+		ConnectionHelpers: map[string]configfile.ConnectionHelper{
+			"echo": {Command: "echo", Args: []string{"connecting to"}},
+		},
+	}
+	helper, err := GetConnectionHelperWithConfig("echo://some/host", configFile)
+	assert.NilError(t, err)
+
+	conn, err := helper.Dialer(context.Background(), "tcp", "docker.example.com:80")
+	assert.NilError(t, err)
+	defer conn.Close()
+
+	out, err := io.ReadAll(conn)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(out), "connecting to echo://some/host\n"))
+}
@@ -11,6 +11,7 @@
 	"strings"
 	"time"
 
+	"github.com/docker/cli/cli/config/configfile"
 	"github.com/docker/cli/cli/connhelper"
 	"github.com/docker/cli/cli/context"
 	"github.com/docker/cli/cli/context/store"
@@ -85,9 +86,16 @@ func (ep *Endpoint) tlsConfig() (*tls.Config, error) {
 
 // ClientOpts returns a slice of Client options to configure an API client with this endpoint
 func (ep *Endpoint) ClientOpts() ([]client.Opt, error) {
+	return ep.ClientOptsWithConfig(nil)
+}
+
+// ClientOptsWithConfig returns a slice of Client options to configure an API
+// client with this endpoint. The connection helpers configured in configFile
+// are used for hosts with a custom URL scheme.
+func (ep *Endpoint) ClientOptsWithConfig(configFile *configfile.ConfigFile) ([]client.Opt, error) {
 	var result []client.Opt
 	if ep.Host != "" {
-		helper, err := connhelper.GetConnectionHelper(ep.Host)
+		helper, err := connhelper.GetConnectionHelperWithConfig(ep.Host, configFile)
 		if err != nil {
 			return nil, err
 		}
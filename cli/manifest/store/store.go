@@ -21,8 +21,15 @@ type Store interface {
 	Get(listRef reference.Reference, manifest reference.Reference) (types.ImageManifest, error)
 	GetList(listRef reference.Reference) ([]types.ImageManifest, error)
 	Save(listRef reference.Reference, manifest reference.Reference, image types.ImageManifest) error
+	GetListMetadata(listRef reference.Reference) (types.ListMetadata, error)
+	SaveListMetadata(listRef reference.Reference, metadata types.ListMetadata) error
 }
 
+// listMetadataFile is the name of the file containing the metadata of a
+// manifest list. Its name can't conflict with the name of a manifest file,
+// as references can't start with a period.
+const listMetadataFile = ".metadata.json"
+
 // fsStore manages manifest files stored on the local filesystem
 type fsStore struct {
 	root string
@@ -121,6 +128,9 @@ func (s *fsStore) listManifests(transaction string) ([]string, error) {
 
 	filenames := make([]string, 0, len(fileInfos))
 	for _, info := range fileInfos {
+		if info.Name() == listMetadataFile {
+			continue
+		}
 		filenames = append(filenames, info.Name())
 	}
 	return filenames, nil
@@ -139,6 +149,37 @@ func (s *fsStore) Save(listRef reference.Reference, manifest reference.Reference
 	return os.WriteFile(filename, bytes, 0o644)
 }
 
+// GetListMetadata returns the metadata of a local manifest list. It returns
+// empty metadata if none was saved.
+func (s *fsStore) GetListMetadata(listRef reference.Reference) (types.ListMetadata, error) {
+	filename := filepath.Join(s.root, makeFilesafeName(listRef.String()), listMetadataFile)
+	bytes, err := os.ReadFile(filename)
+	switch {
+	case os.IsNotExist(err):
+		return types.ListMetadata{}, nil
+	case err != nil:
+		return types.ListMetadata{}, err
+	}
+	var metadata types.ListMetadata
+	if err := json.Unmarshal(bytes, &metadata); err != nil {
+		return types.ListMetadata{}, fmt.Errorf("invalid manifest list metadata file %v: %w", filename, err)
+	}
+	return metadata, nil
+}
+
+// SaveListMetadata saves the metadata of a local manifest list
+func (s *fsStore) SaveListMetadata(listRef reference.Reference, metadata types.ListMetadata) error {
+	if err := s.createManifestListDirectory(listRef.String()); err != nil {
+		return err
+	}
+	filename := filepath.Join(s.root, makeFilesafeName(listRef.String()), listMetadataFile)
+	bytes, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, bytes, 0o644)
+}
+
 func (s *fsStore) createManifestListDirectory(transaction string) error {
 	path := filepath.Join(s.root, makeFilesafeName(transaction))
 	return os.MkdirAll(path, 0o755)
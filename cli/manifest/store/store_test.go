@@ -120,3 +120,29 @@ func TestStoreGetListDoesNotExist(t *testing.T) {
 	assert.Error(t, err, "No such manifest: list")
 	assert.Check(t, errdefs.IsNotFound(err))
 }
+
+func TestStoreListMetadata(t *testing.T) {
+	store := NewStore(t.TempDir())
+	listRef := ref("list")
+
+	metadata, err := store.GetListMetadata(listRef)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(metadata, types.ListMetadata{}))
+
+	data := types.ImageManifest{Ref: sref(t, "abcdef")}
+	assert.NilError(t, store.Save(listRef, ref("manifest"), data))
+	expected := types.ListMetadata{
+		MediaType:   "application/vnd.oci.image.index.v1+json",
+		Annotations: map[string]string{"org.opencontainers.image.version": "1.0"},
+	}
+	assert.NilError(t, store.SaveListMetadata(listRef, expected))
+
+	metadata, err = store.GetListMetadata(listRef)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(metadata, expected))
+
+	// the metadata must not be returned as one of the manifests of the list
+	manifests, err := store.GetList(listRef)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(manifests, 1))
+}
@@ -13,6 +13,18 @@
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
+// Annotations used to attach attestation manifests to the image they describe
+// in an image index.
+const (
+	// AnnotationReferenceType is the type of the reference of the manifest.
+	AnnotationReferenceType = "vnd.docker.reference.type"
+	// AnnotationReferenceDigest is the digest of the image manifest that
+	// the manifest refers to.
+	AnnotationReferenceDigest = "vnd.docker.reference.digest"
+	// ReferenceTypeAttestation is the reference type of attestation manifests.
+	ReferenceTypeAttestation = "attestation-manifest"
+)
+
 // ImageManifest contains info to output for a manifest object.
 type ImageManifest struct {
 	Ref        *SerializableNamed
@@ -23,6 +35,52 @@ type ImageManifest struct {
 	SchemaV2Manifest *schema2.DeserializedManifest `json:",omitempty"`
 	// OCIManifest is used for inspection
 	OCIManifest *ocischema.DeserializedManifest `json:",omitempty"`
+	// Attestations are the attestation manifests attached to the image. It
+	// is used for inspection
+	Attestations []ImageManifest `json:",omitempty"`
+}
+
+// IsAttestation returns whether the manifest is an attestation manifest that
+// is attached to an image in an image index.
+func (i ImageManifest) IsAttestation() bool {
+	return i.Descriptor.Annotations[AnnotationReferenceType] == ReferenceTypeAttestation
+}
+
+// GroupAttestations returns the manifests of an image index with the
+// attestation manifests attached to the image manifest they describe.
+// Attestation manifests that don't refer to an image in the index are
+// returned as-is.
+func GroupAttestations(manifests []ImageManifest) []ImageManifest {
+	result := make([]ImageManifest, 0, len(manifests))
+	images := make(map[digest.Digest]int)
+	for _, m := range manifests {
+		if !m.IsAttestation() {
+			images[m.Descriptor.Digest] = len(result)
+			result = append(result, m)
+		}
+	}
+	for _, m := range manifests {
+		if !m.IsAttestation() {
+			continue
+		}
+		if idx, ok := images[digest.Digest(m.Descriptor.Annotations[AnnotationReferenceDigest])]; ok {
+			result[idx].Attestations = append(result[idx].Attestations, m)
+		} else {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+// ListMetadata contains the properties of a local manifest list that are not
+// specific to one of its manifests.
+type ListMetadata struct {
+	// MediaType is the media type of the manifest list when it is pushed. If
+	// empty, it is based on the media type of its manifests.
+	MediaType string `json:",omitempty"`
+	// Annotations are the annotations of the manifest list. They are only
+	// supported by OCI image indexes.
+	Annotations map[string]string `json:",omitempty"`
 }
 
 // OCIPlatform creates an OCI platform from a manifest list platform spec
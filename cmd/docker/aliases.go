@@ -1,12 +1,19 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
 package main
 
 import (
 	"fmt"
-	"os"
+	"maps"
+	"slices"
 	"strings"
+	"unicode"
 
+	"github.com/docker/cli/cli"
 	pluginmanager "github.com/docker/cli/cli-plugins/manager"
 	"github.com/docker/cli/cli/command"
+	"github.com/google/shlex"
 	"github.com/spf13/cobra"
 )
 
@@ -14,31 +21,95 @@
 	keyBuilderAlias = "builder"
 )
 
-var allowedAliases = map[string]struct{}{
-	keyBuilderAlias: {},
+// reservedCommands are the commands that are added by cobra when executing
+// the root command, and which can therefore not be found before that.
+var reservedCommands = []string{
+	"completion",
+	"help",
+	cobra.ShellCompRequestCmd,
+	cobra.ShellCompNoDescRequestCmd,
 }
 
 func processAliases(dockerCli command.Cli, cmd *cobra.Command, args, osArgs []string) ([]string, []string, []string, error) {
 	var err error
 	var envs []string
 	aliasMap := dockerCli.ConfigFile().Aliases
-	aliases := make([][2][]string, 0, len(aliasMap))
+	aliases := make([][2][]string, 0, 1)
+	userAliases := make(map[string][]string, len(aliasMap))
 
-	for k, v := range aliasMap {
-		if _, ok := allowedAliases[k]; !ok {
-			return args, osArgs, envs, fmt.Errorf("not allowed to alias %q (allowed: %#v)", k, allowedAliases)
+	// Invalid aliases are skipped with a warning, so that they don't prevent
+	// other commands from being used. An error is returned only if an invalid
+	// alias is invoked.
+	invalid := make(map[string]error)
+	for _, k := range slices.Sorted(maps.Keys(aliasMap)) {
+		v := aliasMap[k]
+		if k == keyBuilderAlias {
+			if c, _, err := cmd.Find(strings.Split(v, " ")); err == nil {
+				if !pluginmanager.IsPluginCommand(c) {
+					return args, osArgs, envs, fmt.Errorf("not allowed to alias with builtin %q as target", v)
+				}
+			}
+			aliases = append(aliases, [2][]string{{k}, {v}})
+			continue
+		}
+		if builtin, ok := builtinCommand(cmd, k); ok {
+			// The builtin command is executed when invoking the alias.
+			_, _ = fmt.Fprintf(dockerCli.Err(), "WARNING: ignoring alias %q, which shadows the builtin %q command\n", k, builtin)
+			continue
 		}
-		if c, _, err := cmd.Find(strings.Split(v, " ")); err == nil {
-			if !pluginmanager.IsPluginCommand(c) {
-				return args, osArgs, envs, fmt.Errorf("not allowed to alias with builtin %q as target", v)
+		expansion, err := parseAlias(k, v)
+		if err != nil {
+			invalid[k] = err
+			continue
+		}
+		userAliases[k] = expansion
+	}
+	// Aliases that expand to an invalid alias are invalid as well, so check
+	// the aliases until no more invalid aliases are found.
+	for found := true; found; {
+		found = false
+		for _, k := range slices.Sorted(maps.Keys(userAliases)) {
+			err := checkAliasTarget(dockerCli, cmd, userAliases, k, userAliases[k][0])
+			if err == nil {
+				_, err = expandAlias(userAliases, []string{k})
+			}
+			if err != nil {
+				invalid[k] = err
+				delete(userAliases, k)
+				found = true
 			}
 		}
-		aliases = append(aliases, [2][]string{{k}, {v}})
 	}
+	for _, k := range slices.Sorted(maps.Keys(invalid)) {
+		_, _ = fmt.Fprintf(dockerCli.Err(), "WARNING: ignoring alias %q: %v\n", k, invalid[k])
+	}
+	addAliasCommandStubs(cmd, aliasMap, userAliases)
 
-	args, osArgs, envs, err = processBuilder(dockerCli, cmd, args, os.Args)
+	// Aliases are expanded in the command to complete when completing, so
+	// that the arguments of the alias are completed.
+	idx := 0
+	if len(args) > 0 && (args[0] == cobra.ShellCompRequestCmd || args[0] == cobra.ShellCompNoDescRequestCmd) {
+		idx = 1
+	}
+	if len(args) > idx {
+		if err, ok := invalid[args[idx]]; ok {
+			return args, osArgs, envs, err
+		}
+		expanded, err := expandAlias(userAliases, args[idx:])
+		if err != nil {
+			return args, osArgs, envs, err
+		}
+		// args are the arguments that remain after parsing the global
+		// flags, which are at the end of osArgs.
+		if osIdx := len(osArgs) - len(args) + idx; osIdx >= 0 {
+			osArgs = append(slices.Clone(osArgs[:osIdx]), expanded...)
+		}
+		args = append(slices.Clone(args[:idx]), expanded...)
+	}
+
+	args, osArgs, envs, err = processBuilder(dockerCli, cmd, args, osArgs)
 	if err != nil {
-		return args, os.Args, envs, err
+		return args, osArgs, envs, err
 	}
 
 	for _, al := range aliases {
@@ -52,3 +123,84 @@ func processAliases(dockerCli command.Cli, cmd *cobra.Command, args, osArgs []st
 
 	return args, osArgs, envs, nil
 }
+
+// builtinCommand returns the path of the builtin command with the given name,
+// if any. User-defined aliases can not shadow builtin commands.
+func builtinCommand(cmd *cobra.Command, name string) (string, bool) {
+	if slices.Contains(reservedCommands, name) {
+		return cmd.Name() + " " + name, true
+	}
+	if c, _, err := cmd.Find([]string{name}); err == nil && c != cmd && !pluginmanager.IsPluginCommand(c) {
+		return c.CommandPath(), true
+	}
+	return "", false
+}
+
+// parseAlias validates the user-defined alias, and returns the command and
+// arguments it expands to. Aliases must start with a command, which is
+// checked by [checkAliasTarget].
+func parseAlias(name, value string) ([]string, error) {
+	if name == "" || strings.HasPrefix(name, "-") || strings.ContainsFunc(name, unicode.IsSpace) {
+		return nil, fmt.Errorf("invalid alias name %q", name)
+	}
+	expansion, err := shlex.Split(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid alias %q: %w", name, err)
+	}
+	if len(expansion) == 0 || strings.HasPrefix(expansion[0], "-") {
+		return nil, fmt.Errorf("invalid alias %q: must start with a command", name)
+	}
+	return expansion, nil
+}
+
+// checkAliasTarget checks that the command an alias expands to is a builtin
+// command, a plugin, or another alias. Plugins are not validated, as doing so
+// requires executing them.
+func checkAliasTarget(dockerCli command.Cli, cmd *cobra.Command, aliases map[string][]string, name, target string) error {
+	if _, ok := aliases[target]; ok || slices.Contains(reservedCommands, target) {
+		return nil
+	}
+	if c, _, err := cmd.Find([]string{target}); err == nil && c != cmd {
+		return nil
+	}
+	if pluginmanager.IsPluginCandidate(target, dockerCli) {
+		return nil
+	}
+	return fmt.Errorf("invalid alias %q: unknown command %q", name, target)
+}
+
+// expandAlias expands the alias at the start of args, if any, and returns the
+// expanded arguments. Aliases that expand to another alias are expanded
+// recursively, and an error is returned if an alias expands to itself.
+func expandAlias(aliases map[string][]string, args []string) ([]string, error) {
+	var expanded []string
+	for len(args) > 0 {
+		expansion, ok := aliases[args[0]]
+		if !ok {
+			break
+		}
+		if slices.Contains(expanded, args[0]) {
+			return nil, fmt.Errorf("alias %q is recursive: %s", expanded[0], strings.Join(append(expanded, args[0]), " -> "))
+		}
+		expanded = append(expanded, args[0])
+		args = append(slices.Clone(expansion), args[1:]...)
+	}
+	return args, nil
+}
+
+// addAliasCommandStubs adds a stub cobra.Command for each user-defined alias,
+// so that aliases are listed in the help output and in shell completion. The
+// stubs are not executed, as aliases are expanded before the command to run
+// is looked up.
+func addAliasCommandStubs(rootCmd *cobra.Command, aliasMap map[string]string, userAliases map[string][]string) {
+	for name := range userAliases {
+		value := aliasMap[name]
+		rootCmd.AddCommand(&cobra.Command{
+			Use:                name,
+			Short:              `Alias for "docker ` + value + `"`,
+			Annotations:        map[string]string{cli.CommandAnnotationAlias: value},
+			DisableFlagParsing: true,
+			Run:                func(*cobra.Command, []string) {},
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bytes"
+	"maps"
+	"testing"
+
+	dockercli "github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command/commands"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/internal/test"
+	"github.com/spf13/cobra"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
+)
+
+func newAliasTestCommand(t *testing.T, aliases map[string]string) (*test.FakeCli, *cobra.Command) {
+	t.Helper()
+	cli := test.NewFakeCli(nil)
+	cli.SetConfigFile(&configfile.ConfigFile{Aliases: aliases})
+	cmd := &cobra.Command{Use: "docker", TraverseChildren: true}
+	dockercli.SetupRootCommand(cmd)
+	commands.AddCommands(cmd, cli)
+	return cli, cmd
+}
+
+func TestProcessAliases(t *testing.T) {
+	aliases := map[string]string{
+		"lg":   "logs -f --tail 100",
+		"nuke": "system prune -af",
+		"lgs":  "lg --timestamps",
+		"hi":   `run --rm alpine echo "hello world"`,
+	}
+	tests := []struct {
+		doc            string
+		args           []string
+		osArgs         []string
+		expectedArgs   []string
+		expectedOSArgs []string
+	}{
+		{
+			doc:            "no alias",
+			args:           []string{"ps", "-a"},
+			osArgs:         []string{"docker", "ps", "-a"},
+			expectedArgs:   []string{"ps", "-a"},
+			expectedOSArgs: []string{"docker", "ps", "-a"},
+		},
+		{
+			doc:            "alias with arguments",
+			args:           []string{"lg", "mycontainer"},
+			osArgs:         []string{"docker", "--context", "lg", "lg", "mycontainer"},
+			expectedArgs:   []string{"logs", "-f", "--tail", "100", "mycontainer"},
+			expectedOSArgs: []string{"docker", "--context", "lg", "logs", "-f", "--tail", "100", "mycontainer"},
+		},
+		{
+			doc:            "nested alias",
+			args:           []string{"lgs", "mycontainer"},
+			osArgs:         []string{"docker", "lgs", "mycontainer"},
+			expectedArgs:   []string{"logs", "-f", "--tail", "100", "--timestamps", "mycontainer"},
+			expectedOSArgs: []string{"docker", "logs", "-f", "--tail", "100", "--timestamps", "mycontainer"},
+		},
+		{
+			doc:            "quoted arguments",
+			args:           []string{"hi"},
+			osArgs:         []string{"docker", "hi"},
+			expectedArgs:   []string{"run", "--rm", "alpine", "echo", "hello world"},
+			expectedOSArgs: []string{"docker", "run", "--rm", "alpine", "echo", "hello world"},
+		},
+		{
+			doc:            "alias not in command position",
+			args:           []string{"logs", "nuke"},
+			osArgs:         []string{"docker", "logs", "nuke"},
+			expectedArgs:   []string{"logs", "nuke"},
+			expectedOSArgs: []string{"docker", "logs", "nuke"},
+		},
+		{
+			doc:            "completion",
+			args:           []string{cobra.ShellCompRequestCmd, "lg", ""},
+			osArgs:         []string{"docker", cobra.ShellCompRequestCmd, "lg", ""},
+			expectedArgs:   []string{cobra.ShellCompRequestCmd, "logs", "-f", "--tail", "100", ""},
+			expectedOSArgs: []string{"docker", cobra.ShellCompRequestCmd, "logs", "-f", "--tail", "100", ""},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			cli, cmd := newAliasTestCommand(t, aliases)
+			args, osArgs, envs, err := processAliases(cli, cmd, tc.args, tc.osArgs)
+			assert.NilError(t, err)
+			assert.Check(t, is.DeepEqual(args, tc.expectedArgs))
+			assert.Check(t, is.DeepEqual(osArgs, tc.expectedOSArgs))
+			assert.Check(t, is.Len(envs, 0))
+		})
+	}
+}
+
+func TestProcessAliasesInvalid(t *testing.T) {
+	tests := []struct {
+		doc         string
+		aliases     map[string]string
+		invoke      string
+		expectedErr string
+	}{
+		{
+			doc:         "shadows builtin",
+			aliases:     map[string]string{"ps": "ps -a"},
+			expectedErr: `alias "ps", which shadows the builtin "docker ps" command`,
+		},
+		{
+			doc:         "shadows builtin alias",
+			aliases:     map[string]string{"rmi": "image rm -f"},
+			expectedErr: `alias "rmi", which shadows the builtin "docker rmi" command`,
+		},
+		{
+			doc:         "shadows help",
+			aliases:     map[string]string{"help": "version"},
+			expectedErr: `alias "help", which shadows the builtin "docker help" command`,
+		},
+		{
+			doc:         "invalid name",
+			aliases:     map[string]string{"my alias": "ps"},
+			expectedErr: `invalid alias name "my alias"`,
+		},
+		{
+			doc:         "empty",
+			aliases:     map[string]string{"empty": ""},
+			invoke:      "empty",
+			expectedErr: `invalid alias "empty": must start with a command`,
+		},
+		{
+			doc:         "flag",
+			aliases:     map[string]string{"debug": "--debug ps"},
+			invoke:      "debug",
+			expectedErr: `invalid alias "debug": must start with a command`,
+		},
+		{
+			doc:         "unknown command",
+			aliases:     map[string]string{"oops": "nosuchcommand -a"},
+			invoke:      "oops",
+			expectedErr: `invalid alias "oops": unknown command "nosuchcommand"`,
+		},
+		{
+			doc:         "alias of invalid alias",
+			aliases:     map[string]string{"oops": "nosuchcommand -a", "oops2": "oops -b"},
+			invoke:      "oops2",
+			expectedErr: `invalid alias "oops2": unknown command "oops"`,
+		},
+		{
+			doc:         "recursive",
+			aliases:     map[string]string{"a": "b -x", "b": "a -y"},
+			invoke:      "a",
+			expectedErr: `is recursive: `,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			aliases := map[string]string{"lg": "logs -f"}
+			maps.Copy(aliases, tc.aliases)
+
+			// Invalid aliases are ignored with a warning, and don't prevent
+			// other commands and aliases from being used.
+			cli, cmd := newAliasTestCommand(t, aliases)
+			args, _, _, err := processAliases(cli, cmd, []string{"lg", "web"}, []string{"docker", "lg", "web"})
+			assert.NilError(t, err)
+			assert.Check(t, is.DeepEqual(args, []string{"logs", "-f", "web"}))
+			assert.Check(t, is.Contains(cli.ErrBuffer().String(), "WARNING: ignoring "))
+			assert.Check(t, is.Contains(cli.ErrBuffer().String(), tc.expectedErr))
+
+			if tc.invoke != "" {
+				cli, cmd = newAliasTestCommand(t, aliases)
+				_, _, _, err = processAliases(cli, cmd, []string{tc.invoke}, []string{"docker", tc.invoke})
+				assert.Check(t, is.ErrorContains(err, tc.expectedErr))
+			}
+		})
+	}
+}
+
+func TestProcessAliasesShadowedBuiltin(t *testing.T) {
+	cli, cmd := newAliasTestCommand(t, map[string]string{"ps": "ps -a"})
+	args, _, _, err := processAliases(cli, cmd, []string{"ps"}, []string{"docker", "ps"})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(args, []string{"ps"}))
+}
+
+func TestProcessAliasesBuilderBuiltinTarget(t *testing.T) {
+	cli, cmd := newAliasTestCommand(t, map[string]string{"builder": "image"})
+	_, _, _, err := processAliases(cli, cmd, []string{"version"}, []string{"docker", "version"})
+	assert.Check(t, is.ErrorContains(err, `not allowed to alias with builtin "image" as target`))
+}
+
+func TestProcessAliasesPluginTarget(t *testing.T) {
+	pluginDir := fs.NewDir(t, "plugins", fs.WithFile("docker-myplugin", "", fs.WithMode(0o755)))
+	cli, cmd := newAliasTestCommand(t, map[string]string{"mp": "myplugin --verbose"})
+	cli.ConfigFile().CLIPluginsExtraDirs = []string{pluginDir.Path()}
+
+	args, _, _, err := processAliases(cli, cmd, []string{"mp"}, []string{"docker", "mp"})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(args, []string{"myplugin", "--verbose"}))
+}
+
+func TestAliasesHelp(t *testing.T) {
+	cli, cmd := newAliasTestCommand(t, map[string]string{
+		"lg":   "logs -f --tail 100",
+		"nuke": "system prune -af",
+	})
+	_, _, _, err := processAliases(cli, cmd, []string{}, []string{"docker"})
+	assert.NilError(t, err)
+
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	assert.NilError(t, cmd.Help())
+	assert.Check(t, is.Contains(out.String(), `
+Aliases:
+  lg          Alias for "docker logs -f --tail 100"
+  nuke        Alias for "docker system prune -af"
+`))
+
+	commandsSection, _, _ := bytes.Cut(out.Bytes(), []byte("Aliases:"))
+	assert.Check(t, !bytes.Contains(commandsSection, []byte("lg ")))
+}
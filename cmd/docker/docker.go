@@ -29,6 +29,7 @@
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type errCtxSignalTerminated struct {
@@ -512,15 +513,38 @@ func runDocker(ctx context.Context, dockerCli *command.DockerCli) error {
 		}
 	}
 
-	var subCommand *cobra.Command
+	runHooks, hookOpts := hookOptions(dockerCli)
+	if runHooks {
+		// Plugin hooks annotate the span of the command.
+		var span trace.Span
+		ctx, span = dockerCli.TracerProvider().Tracer("github.com/docker/cli").Start(ctx, "command")
+		defer span.End()
+	}
+
+	// Add the help command, so that "docker help" is found as a command,
+	// instead of being looked up as a plugin.
+	cmd.InitDefaultHelpCmd()
+
+	var (
+		subCommand *cobra.Command
+		subArgs    []string
+	)
 	if len(args) > 0 {
-		ccmd, _, err := cmd.Find(args)
-		subCommand = ccmd
+		ccmd, cmdArgs, err := cmd.Find(args)
+		subCommand, subArgs = ccmd, cmdArgs
+		if ccmd != nil && runHooks {
+			trace.SpanFromContext(ctx).SetAttributes(command.BaseCommandAttributes(ccmd, dockerCli)...)
+		}
 		if err != nil || pluginmanager.IsPluginCommand(ccmd) {
+			if ccmd != nil && runHooks && !hasHelpFlag(cmd, args) {
+				if err := pluginmanager.RunPrePluginHooks(ctx, dockerCli, cmd, ccmd, args, hookOpts...); err != nil {
+					return err
+				}
+			}
 			err := tryPluginRun(ctx, dockerCli, cmd, args[0], envs)
-			if ccmd != nil && dockerCli.Out().IsTerminal() && dockerCli.HooksEnabled() && !errdefs.IsNotFound(err) {
+			if ccmd != nil && runHooks && !errdefs.IsNotFound(err) {
 				errMessage := cmdErrorMessage(err)
-				pluginmanager.RunPluginHooks(ctx, dockerCli, cmd, ccmd, args, errMessage)
+				pluginmanager.RunPluginHooks(ctx, dockerCli, cmd, ccmd, args, errMessage, hookOpts...)
 			}
 			if err == nil {
 				return nil
@@ -541,12 +565,19 @@ func runDocker(ctx context.Context, dockerCli *command.DockerCli) error {
 	// We've parsed global args already, so reset args to those
 	// which remain.
 	cmd.SetArgs(args)
+
+	// If hooks are enabled, run the pre-command plugin hooks, which may deny
+	// the command from being executed.
+	if subCommand != nil && runHooks && !isHelpRequested(subCommand, subArgs) {
+		if err := pluginmanager.RunPreCommandHooks(ctx, dockerCli, cmd, subCommand, subArgs, hookOpts...); err != nil {
+			return err
+		}
+	}
 	err = cmd.ExecuteContext(ctx)
 
-	// If the command is being executed in an interactive terminal
-	// and hook are enabled, run the plugin hooks.
-	if subCommand != nil && dockerCli.Out().IsTerminal() && dockerCli.HooksEnabled() {
-		pluginmanager.RunCLICommandHooks(ctx, dockerCli, cmd, subCommand, cmdErrorMessage(err))
+	// If hooks are enabled, run the plugin hooks.
+	if subCommand != nil && runHooks {
+		pluginmanager.RunCLICommandHooks(ctx, dockerCli, cmd, subCommand, cmdErrorMessage(err), hookOpts...)
 	}
 
 	return err
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"os"
+
+	pluginmanager "github.com/docker/cli/cli-plugins/manager"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+)
+
+// hookOptions returns whether plugin hooks are run, and the options to run
+// them with. Hooks that are enabled are run if the output of the CLI is a
+// terminal, or if hooks are enabled for non-interactive sessions.
+func hookOptions(dockerCli *command.DockerCli) (bool, []pluginmanager.HookOption) {
+	if !dockerCli.HooksEnabled() {
+		return false, nil
+	}
+	interactive := dockerCli.Out().IsTerminal()
+	if !interactive && !dockerCli.HooksNonInteractive() {
+		return false, nil
+	}
+
+	opts := []pluginmanager.HookOption{pluginmanager.WithContextName(dockerCli.CurrentContext())}
+	if !interactive || !dockerCli.In().IsTerminal() {
+		opts = append(opts, pluginmanager.WithNonInteractive())
+	}
+	if path := dockerCli.HooksOutput(); path != "" {
+		opts = append(opts, pluginmanager.WithHookOutput(hookOutputFile(path)))
+	}
+	return true, opts
+}
+
+// isHelpRequested returns whether only the help of the command is printed,
+// in which case no pre-command hooks are run.
+func isHelpRequested(cmd *cobra.Command, args []string) bool {
+	// Commands with subcommands, such as "docker image" and the root command
+	// for "docker help", print help instead of executing a command.
+	if cmd.Name() == "help" || !cmd.Runnable() || cmd.HasSubCommands() {
+		return true
+	}
+	return hasHelpFlag(cmd, args)
+}
+
+// hasHelpFlag returns whether the --help flag is set in args.
+func hasHelpFlag(cmd *cobra.Command, args []string) bool {
+	for _, arg := range args {
+		switch arg {
+		case "--":
+			return false
+		case "--help":
+			return true
+		case "-h":
+			// "-h" is the shorthand for --help, unless the command uses
+			// it for another flag, such as "docker run -h <hostname>".
+			if f := cmd.Flags().ShorthandLookup("h"); f == nil || f.Name == "help" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hookOutputFile is the path of a file to which the output of plugin hooks
+// is appended. The file is created if it does not exist.
+type hookOutputFile string
+
+func (f hookOutputFile) Write(p []byte) (int, error) {
+	file, err := os.OpenFile(string(f), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return 0, err
+	}
+	n, err := file.Write(p)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return n, err
+}
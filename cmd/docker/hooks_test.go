@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/streams"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
+)
+
+func TestPreCommandHooksNotRunForHelp(t *testing.T) {
+	const plugin = `#!/bin/sh
+if [ "$2" = "docker-cli-plugin-hooks" ]; then
+	printf '%s' "$3" >> "$(dirname "$0")/requests"
+	echo '{"Type":1}'
+else
+	echo '{"SchemaVersion":"0.1.0"}'
+fi
+`
+	pluginDir := fs.NewDir(t, t.Name(), fs.WithFile("docker-audit", plugin, fs.WithMode(0o777)))
+	configDir := fs.NewDir(t, t.Name(), fs.WithFile("config.json", `{
+	"features": {"hooks": "true", "hooks-non-interactive": "true"},
+	"cliPluginsExtraDirs": ["`+pluginDir.Path()+`"],
+	"plugins": {"audit": {"pre-hooks": "stack,context,help"}}
+}`))
+	config.SetDir(configDir.Path())
+	t.Setenv("DOCKER_CLI_HOOKS", "")
+	t.Setenv("DOCKER_CLI_HINTS", "")
+	t.Setenv("DOCKER_CLI_HOOKS_NON_INTERACTIVE", "")
+
+	run := func(t *testing.T, args ...string) string {
+		t.Helper()
+		_ = os.Remove(pluginDir.Join("requests"))
+		oldArgs := os.Args
+		os.Args = append([]string{"docker"}, args...)
+		defer func() { os.Args = oldArgs }()
+
+		dockerCLI, err := command.NewDockerCli(
+			command.WithBaseContext(context.Background()),
+			command.WithInputStream(io.NopCloser(nil)),
+			command.WithOutputStream(streams.NewOut(io.Discard)),
+			command.WithErrorStream(io.Discard),
+		)
+		assert.NilError(t, err)
+		assert.NilError(t, runDocker(context.Background(), dockerCLI))
+
+		requests, err := os.ReadFile(pluginDir.Join("requests"))
+		if os.IsNotExist(err) {
+			return ""
+		}
+		assert.NilError(t, err)
+		return string(requests)
+	}
+
+	for _, args := range [][]string{
+		{"stack", "--help"},
+		{"stack", "ls", "--help"},
+		{"stack"},
+		{"help", "context"},
+		{"context", "ls", "-h"},
+	} {
+		assert.Check(t, is.Equal(run(t, args...), ""), "hooks should not run for %v", args)
+	}
+
+	// Hooks run for a command that is executed.
+	assert.Check(t, is.Contains(run(t, "context", "ls", "--quiet"), `"Flags":{"quiet":"true"}`))
+}
@@ -366,7 +366,7 @@ complete -c docker -A -f -n '__fish_seen_subcommand_from login' -s u -l username
 complete -c docker -f -n '__fish_docker_no_subcommand' -a logout -d 'Log out from a registry'
 
 # logs
-complete -c docker -f -n '__fish_docker_no_subcommand' -a logs -d 'Fetch the logs of a container'
+complete -c docker -f -n '__fish_docker_no_subcommand' -a logs -d 'Fetch the logs of one or more containers'
 complete -c docker -A -f -n '__fish_seen_subcommand_from logs' -s f -l follow -d 'Follow log output'
 complete -c docker -A -f -n '__fish_seen_subcommand_from logs' -l help -d 'Print usage'
 complete -c docker -A -f -n '__fish_seen_subcommand_from logs' -s t -l timestamps -d 'Show timestamps'
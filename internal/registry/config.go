@@ -256,7 +256,14 @@ func validateHostPort(s string) error {
 // repository-name, and detects whether the registry is considered
 // "secure" (non-localhost).
 func NewIndexInfo(reposName reference.Named) *registry.IndexInfo {
-	indexName := normalizeIndexName(reference.Domain(reposName))
+	return NewIndexInfoFromHost(reference.Domain(reposName))
+}
+
+// NewIndexInfoFromHost creates a new [registry.IndexInfo] for the given
+// registry hostname, and detects whether the registry is considered
+// "secure" (non-localhost).
+func NewIndexInfoFromHost(hostname string) *registry.IndexInfo {
+	indexName := normalizeIndexName(hostname)
 	if indexName == IndexName {
 		return &registry.IndexInfo{
 			Name:     IndexName,
@@ -1,15 +1,22 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
 package registryclient
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 
 	"github.com/distribution/reference"
 	manifesttypes "github.com/docker/cli/cli/manifest/types"
+	"github.com/docker/cli/internal/registry"
 	"github.com/docker/distribution"
 	distributionclient "github.com/docker/distribution/registry/client"
+	"github.com/docker/distribution/registry/client/auth"
 	registrytypes "github.com/moby/moby/api/types/registry"
 	"github.com/opencontainers/go-digest"
 	"github.com/sirupsen/logrus"
@@ -20,8 +27,14 @@
 type RegistryClient interface {
 	GetManifest(ctx context.Context, ref reference.Named) (manifesttypes.ImageManifest, error)
 	GetManifestList(ctx context.Context, ref reference.Named) ([]manifesttypes.ImageManifest, error)
+	GetRawManifest(ctx context.Context, ref reference.Named) (distribution.Manifest, error)
+	GetBlob(ctx context.Context, ref reference.Canonical) ([]byte, error)
+	ListTags(ctx context.Context, ref reference.Named) ([]string, error)
+	ListRepositories(ctx context.Context, hostname string) ([]string, error)
 	MountBlob(ctx context.Context, source reference.Canonical, target reference.Named) error
+	CopyBlob(ctx context.Context, source reference.Canonical, target reference.Named) error
 	PutManifest(ctx context.Context, ref reference.Named, manifest distribution.Manifest) (digest.Digest, error)
+	DeleteManifest(ctx context.Context, ref reference.Canonical) error
 }
 
 // NewRegistryClient returns a new RegistryClient with a resolver
@@ -89,6 +102,87 @@ func (c *client) MountBlob(ctx context.Context, sourceRef reference.Canonical, t
 	return ErrBlobCreated{From: sourceRef, Target: targetRef}
 }
 
+// CopyBlob copies a blob from the source repository to the target repository,
+// for registries that don't support mounting the blob.
+func (c *client) CopyBlob(ctx context.Context, sourceRef reference.Canonical, targetRef reference.Named) error {
+	sourceEndpoint, err := newDefaultRepositoryEndpoint(sourceRef, c.insecureRegistry)
+	if err != nil {
+		return err
+	}
+	sourceEndpoint.actions = []string{"pull"}
+	sourceRepo, err := c.getRepositoryForReference(ctx, sourceRef, sourceEndpoint)
+	if err != nil {
+		return err
+	}
+	sourceBlobs := sourceRepo.Blobs(ctx)
+	desc, err := sourceBlobs.Stat(ctx, sourceRef.Digest())
+	if err != nil {
+		return fmt.Errorf("failed to copy blob %s to %s: %w", sourceRef, targetRef, err)
+	}
+
+	targetEndpoint, err := newDefaultRepositoryEndpoint(targetRef, c.insecureRegistry)
+	if err != nil {
+		return err
+	}
+	targetEndpoint.actions = []string{"pull", "push"}
+	targetName, err := reference.WithName(targetEndpoint.repoName)
+	if err != nil {
+		return fmt.Errorf("failed to parse repo name from %s: %w", targetRef, err)
+	}
+	targetTransport, err := c.getTransportForRepoEndpoint(ctx, targetEndpoint)
+	if err != nil {
+		return err
+	}
+	targetRepo, err := distributionclient.NewRepository(targetName, targetEndpoint.BaseURL(), targetTransport)
+	if err != nil {
+		return err
+	}
+
+	// Skip blobs that were already uploaded, for example by a previous
+	// attempt that was interrupted.
+	if _, err := targetRepo.Blobs(ctx).Stat(ctx, sourceRef.Digest()); err == nil {
+		logrus.Debugf("blob %s already exists in %s", sourceRef.Digest(), targetRef)
+		return nil
+	}
+
+	rc, err := sourceBlobs.Open(ctx, sourceRef.Digest())
+	if err != nil {
+		return fmt.Errorf("failed to copy blob %s to %s: %w", sourceRef, targetRef, err)
+	}
+	defer rc.Close()
+
+	// Resume the upload of a previous attempt that was interrupted, if any,
+	// after the bytes that were already received by the registry.
+	upload, err := newBlobUpload(ctx, targetTransport, targetEndpoint.BaseURL(), targetName, desc.Digest)
+	if err != nil {
+		return fmt.Errorf("failed to copy blob %s to %s: %w", sourceRef, targetRef, err)
+	}
+	if upload.offset > 0 {
+		if _, err := rc.Seek(upload.offset, io.SeekStart); err != nil {
+			upload.cancel(ctx, desc.Digest)
+			return fmt.Errorf("failed to copy blob %s to %s: %w", sourceRef, targetRef, err)
+		}
+		logrus.Debugf("resuming upload of blob %s at offset %d", sourceRef, upload.offset)
+	}
+	if err := upload.write(ctx, rc, desc.Size); err != nil {
+		var interrupted interruptedError
+		if errors.As(err, &interrupted) {
+			if err := upload.remember(desc.Digest); err != nil {
+				logrus.WithError(err).Debug("failed to store upload location")
+			}
+		} else {
+			upload.cancel(ctx, desc.Digest)
+		}
+		return fmt.Errorf("failed to copy blob %s to %s: %w", sourceRef, targetRef, err)
+	}
+	if err := upload.commit(ctx, desc.Digest); err != nil {
+		upload.cancel(ctx, desc.Digest)
+		return fmt.Errorf("failed to copy blob %s to %s: %w", sourceRef, targetRef, err)
+	}
+	logrus.Debugf("copy of blob %s succeeded", sourceRef)
+	return nil
+}
+
 // PutManifest sends the manifest to a registry and returns the new digest
 func (c *client) PutManifest(ctx context.Context, ref reference.Named, manifest distribution.Manifest) (digest.Digest, error) {
 	repoEndpoint, err := newDefaultRepositoryEndpoint(ref, c.insecureRegistry)
@@ -119,11 +213,46 @@ func (c *client) PutManifest(ctx context.Context, ref reference.Named, manifest
 	return dgst, nil
 }
 
+// DeleteManifest deletes a manifest, and all tags that refer to it, from
+// the registry.
+func (c *client) DeleteManifest(ctx context.Context, ref reference.Canonical) error {
+	repoEndpoint, err := newDefaultRepositoryEndpoint(ref, c.insecureRegistry)
+	if err != nil {
+		return err
+	}
+
+	repoEndpoint.actions = []string{"pull", "delete"}
+	repo, err := c.getRepositoryForReference(ctx, ref, repoEndpoint)
+	if err != nil {
+		return err
+	}
+
+	manifestService, err := repo.Manifests(ctx)
+	if err != nil {
+		return err
+	}
+	if err := manifestService.Delete(ctx, ref.Digest()); err != nil {
+		return fmt.Errorf("failed to delete manifest %s: %w", ref, err)
+	}
+	return nil
+}
+
 func (c *client) getRepositoryForReference(ctx context.Context, ref reference.Named, repoEndpoint repositoryEndpoint) (distribution.Repository, error) {
 	repoName, err := reference.WithName(repoEndpoint.repoName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse repo name from %s: %w", ref, err)
 	}
+	httpTransport, err := c.getTransportForRepoEndpoint(ctx, repoEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	return distributionclient.NewRepository(repoName, repoEndpoint.BaseURL(), httpTransport)
+}
+
+// getTransportForRepoEndpoint returns the transport for the endpoint, falling
+// back to plain HTTP if --insecure is set and the registry does not support
+// HTTPS. The URL of the endpoint is updated if the fallback is used.
+func (c *client) getTransportForRepoEndpoint(ctx context.Context, repoEndpoint repositoryEndpoint) (http.RoundTripper, error) {
 	httpTransport, err := c.getHTTPTransportForRepoEndpoint(ctx, repoEndpoint)
 	if err != nil {
 		if !strings.Contains(err.Error(), "server gave HTTP response to HTTPS client") {
@@ -141,16 +270,19 @@ func (c *client) getRepositoryForReference(ctx context.Context, ref reference.Na
 			}
 		}
 	}
-	return distributionclient.NewRepository(repoName, repoEndpoint.BaseURL(), httpTransport)
+	return httpTransport, nil
 }
 
 func (c *client) getHTTPTransportForRepoEndpoint(ctx context.Context, repoEndpoint repositoryEndpoint) (http.RoundTripper, error) {
+	actions := repoEndpoint.actions
+	if len(actions) == 0 {
+		actions = []string{"pull"}
+	}
 	httpTransport, err := getHTTPTransport(
 		c.authConfigResolver(ctx, repoEndpoint.indexInfo.Name),
 		repoEndpoint.endpoint,
-		repoEndpoint.repoName,
+		auth.RepositoryScope{Repository: repoEndpoint.repoName, Actions: actions},
 		c.userAgent,
-		repoEndpoint.actions,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to configure transport: %w", err)
@@ -184,6 +316,117 @@ func (c *client) GetManifestList(ctx context.Context, ref reference.Named) ([]ma
 	return result, err
 }
 
+// GetRawManifest returns the manifest for the reference as it is stored in
+// the registry, which can be an image manifest or a manifest list.
+func (c *client) GetRawManifest(ctx context.Context, ref reference.Named) (distribution.Manifest, error) {
+	var result distribution.Manifest
+	fetch := func(ctx context.Context, repo distribution.Repository, ref reference.Named) (bool, error) {
+		var err error
+		result, err = getManifest(ctx, repo, ref)
+		return result != nil, err
+	}
+
+	err := c.iterateEndpoints(ctx, ref, fetch)
+	return result, err
+}
+
+// ResolveDigest returns the digest of the manifest, or manifest list, the
+// reference refers to. The digest of a canonical reference is returned as-is.
+func ResolveDigest(ctx context.Context, client RegistryClient, ref reference.Named) (digest.Digest, error) {
+	if canonical, ok := ref.(reference.Canonical); ok {
+		return canonical.Digest(), nil
+	}
+	mfst, err := client.GetRawManifest(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	_, payload, err := mfst.Payload()
+	if err != nil {
+		return "", err
+	}
+	return digest.FromBytes(payload), nil
+}
+
+// GetBlob returns the content of a blob, such as an image config, after
+// verifying its digest.
+func (c *client) GetBlob(ctx context.Context, ref reference.Canonical) ([]byte, error) {
+	var result []byte
+	fetch := func(ctx context.Context, repo distribution.Repository, ref reference.Named) (bool, error) {
+		var err error
+		result, err = pullManifestSchemaV2ImageConfig(ctx, ref.(reference.Canonical).Digest(), repo)
+		return result != nil, err
+	}
+
+	err := c.iterateEndpoints(ctx, ref, fetch)
+	return result, err
+}
+
+// ListTags returns the tags of a repository. The tags are fetched in pages
+// if the registry paginates the results.
+func (c *client) ListTags(ctx context.Context, ref reference.Named) ([]string, error) {
+	repoEndpoint, err := newDefaultRepositoryEndpoint(ref, c.insecureRegistry)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := c.getRepositoryForReference(ctx, ref, repoEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := repo.Tags(ctx).All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags of %s: %w", reference.TrimNamed(ref), err)
+	}
+	return tags, nil
+}
+
+// catalogPageSize is the number of repositories to request per page when
+// listing the catalog of a registry.
+const catalogPageSize = 100
+
+// ListRepositories returns the repositories in the catalog of a registry.
+func (c *client) ListRepositories(ctx context.Context, hostname string) ([]string, error) {
+	indexInfo := registry.NewIndexInfoFromHost(hostname)
+	endpoint, err := getDefaultEndpoint(indexInfo.Name, !indexInfo.Secure)
+	if err != nil {
+		return nil, err
+	}
+	if c.insecureRegistry {
+		endpoint.TLSConfig.InsecureSkipVerify = true
+	}
+	httpTransport, err := getHTTPTransport(
+		c.authConfigResolver(ctx, indexInfo.Name),
+		endpoint,
+		auth.RegistryScope{Name: "catalog", Actions: []string{"*"}},
+		c.userAgent,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure transport: %w", err)
+	}
+	reg, err := distributionclient.NewRegistry(endpoint.URL.String(), httpTransport)
+	if err != nil {
+		return nil, err
+	}
+
+	var repos []string
+	entries := make([]string, catalogPageSize)
+	last := ""
+	for {
+		n, err := reg.Repositories(ctx, entries, last)
+		n = min(n, len(entries))
+		repos = append(repos, entries[:n]...)
+		if errors.Is(err, io.EOF) {
+			return repos, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list repositories of %s: %w", indexInfo.Name, err)
+		}
+		if n == 0 {
+			return repos, nil
+		}
+		last = entries[n-1]
+	}
+}
+
 func getManifestOptionsFromReference(ref reference.Named) (digest.Digest, []distribution.ManifestServiceOption, error) {
 	if tagged, isTagged := ref.(reference.NamedTagged); isTagged {
 		tag := tagged.Tag()
@@ -0,0 +1,433 @@
+package registryclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli/config"
+	registrytypes "github.com/moby/moby/api/types/registry"
+	"github.com/opencontainers/go-digest"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// newTestRegistry returns a registry that paginates the tags and catalog
+// results, as a registry:2 server does when the "n" parameter is set.
+func newTestRegistry(t *testing.T, repos map[string][]string) *url.URL {
+	t.Helper()
+	paginate := func(w http.ResponseWriter, r *http.Request, all []string, key string) {
+		last := r.URL.Query().Get("last")
+		n, _ := strconv.Atoi(r.URL.Query().Get("n"))
+		if n == 0 || n > 2 {
+			n = 2
+		}
+		start := 0
+		if last != "" {
+			start = slices.Index(all, last) + 1
+		}
+		end := min(start+n, len(all))
+		if end < len(all) {
+			next := url.URL{Path: r.URL.Path, RawQuery: url.Values{"n": {strconv.Itoa(n)}, "last": {all[end-1]}}.Encode()}
+			w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, next.String()))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{key: all[start:end]})
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Docker-Distribution-API-Version", "registry/2.0")
+		if r.URL.Path == "/v2/" {
+			return
+		}
+		if r.URL.Path == "/v2/_catalog" {
+			var names []string
+			for name := range repos {
+				names = append(names, name)
+			}
+			slices.Sort(names)
+			paginate(w, r, names, "repositories")
+			return
+		}
+		for name, tags := range repos {
+			if r.URL.Path == "/v2/"+name+"/tags/list" {
+				paginate(w, r, tags, "tags")
+				return
+			}
+			if r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/v2/"+name+"/manifests/") {
+				w.WriteHeader(http.StatusAccepted)
+				return
+			}
+		}
+		http.NotFound(w, r)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	u, err := url.Parse(srv.URL)
+	assert.NilError(t, err)
+	return u
+}
+
+func noAuth(context.Context, string) registrytypes.AuthConfig {
+	return registrytypes.AuthConfig{}
+}
+
+func TestListTags(t *testing.T) {
+	u := newTestRegistry(t, map[string][]string{
+		"library/alpine": {"3.18", "3.19", "3.20", "latest", "edge"},
+	})
+	c := NewRegistryClient(noAuth, "test", false)
+
+	ref, err := reference.ParseNormalizedNamed(u.Host + "/library/alpine")
+	assert.NilError(t, err)
+	tags, err := c.ListTags(context.Background(), ref)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(tags, []string{"3.18", "3.19", "3.20", "latest", "edge"}))
+
+	ref, err = reference.ParseNormalizedNamed(u.Host + "/library/missing")
+	assert.NilError(t, err)
+	_, err = c.ListTags(context.Background(), ref)
+	assert.Check(t, is.ErrorContains(err, "failed to list tags of "+u.Host+"/library/missing"))
+}
+
+func TestListRepositories(t *testing.T) {
+	u := newTestRegistry(t, map[string][]string{
+		"library/alpine": nil,
+		"library/nginx":  nil,
+		"team/app":       nil,
+	})
+	c := NewRegistryClient(noAuth, "test", false)
+
+	repos, err := c.ListRepositories(context.Background(), u.Host)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(repos, []string{"library/alpine", "library/nginx", "team/app"}))
+}
+
+func TestDeleteManifest(t *testing.T) {
+	u := newTestRegistry(t, map[string][]string{
+		"team/app": {"latest"},
+	})
+	c := NewRegistryClient(noAuth, "test", false)
+
+	ref, err := reference.ParseNormalizedNamed(u.Host + "/team/app@" + digest.FromString("app").String())
+	assert.NilError(t, err)
+	err = c.DeleteManifest(context.Background(), ref.(reference.Canonical))
+	assert.NilError(t, err)
+
+	ref, err = reference.ParseNormalizedNamed(u.Host + "/team/missing@" + digest.FromString("app").String())
+	assert.NilError(t, err)
+	err = c.DeleteManifest(context.Background(), ref.(reference.Canonical))
+	assert.Check(t, is.ErrorContains(err, "failed to delete manifest "+u.Host+"/team/missing@"))
+}
+
+func TestCopyBlob(t *testing.T) {
+	blob := []byte("layer")
+	dgst := digest.FromBytes(blob)
+
+	var (
+		uploaded bool
+		canceled bool
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Docker-Distribution-API-Version", "registry/2.0")
+		switch {
+		case r.URL.Path == "/v2/":
+		case r.URL.Path == "/v2/src/app/blobs/"+dgst.String():
+			w.Header().Set("Content-Length", strconv.Itoa(len(blob)))
+			w.Header().Set("Docker-Content-Digest", dgst.String())
+			if r.Method == http.MethodGet {
+				_, _ = w.Write(blob)
+			}
+		case r.URL.Path == "/v2/dst/app/blobs/"+dgst.String() && uploaded:
+			w.Header().Set("Content-Length", strconv.Itoa(len(blob)))
+			w.Header().Set("Docker-Content-Digest", dgst.String())
+		case r.URL.Path == "/v2/dst/app/blobs/uploads/" && r.Method == http.MethodPost:
+			w.Header().Set("Location", "/v2/dst/app/blobs/uploads/upload-1")
+			w.Header().Set("Range", "0-0")
+			w.WriteHeader(http.StatusAccepted)
+		case r.URL.Path == "/v2/dst/app/blobs/uploads/upload-1":
+			switch r.Method {
+			case http.MethodPatch:
+				w.Header().Set("Location", r.URL.Path)
+				w.Header().Set("Range", "0-"+strconv.Itoa(len(blob)-1))
+				w.WriteHeader(http.StatusAccepted)
+			case http.MethodPut:
+				uploaded = true
+				w.Header().Set("Docker-Content-Digest", dgst.String())
+				w.WriteHeader(http.StatusCreated)
+			case http.MethodDelete:
+				canceled = true
+				w.WriteHeader(http.StatusNoContent)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	assert.NilError(t, err)
+
+	source, err := reference.ParseNormalizedNamed(u.Host + "/src/app@" + dgst.String())
+	assert.NilError(t, err)
+	target, err := reference.ParseNormalizedNamed(u.Host + "/dst/app")
+	assert.NilError(t, err)
+
+	c := NewRegistryClient(noAuth, "test", false)
+	err = c.CopyBlob(context.Background(), source.(reference.Canonical), target)
+	assert.NilError(t, err)
+	assert.Check(t, uploaded)
+	assert.Check(t, !canceled, "completed upload should not be canceled")
+}
+
+func TestCopyBlobResumeUpload(t *testing.T) {
+	config.SetDir(t.TempDir())
+
+	blob := []byte("layer-content")
+	dgst := digest.FromBytes(blob)
+
+	var (
+		received    []byte
+		interrupted bool
+		uploaded    bool
+		resumedFrom string
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Docker-Distribution-API-Version", "registry/2.0")
+		switch {
+		case r.URL.Path == "/v2/":
+		case r.URL.Path == "/v2/src/app/blobs/"+dgst.String():
+			w.Header().Set("Content-Length", strconv.Itoa(len(blob)))
+			w.Header().Set("Docker-Content-Digest", dgst.String())
+			if r.Method == http.MethodGet {
+				http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(blob))
+			}
+		case r.URL.Path == "/v2/dst/app/blobs/"+dgst.String() && uploaded:
+			w.Header().Set("Content-Length", strconv.Itoa(len(blob)))
+			w.Header().Set("Docker-Content-Digest", dgst.String())
+		case r.URL.Path == "/v2/dst/app/blobs/uploads/" && r.Method == http.MethodPost:
+			assert.Check(t, !interrupted, "interrupted upload should be resumed")
+			w.Header().Set("Location", "/v2/dst/app/blobs/uploads/upload-1")
+			w.Header().Set("Range", "0-0")
+			w.WriteHeader(http.StatusAccepted)
+		case r.URL.Path == "/v2/dst/app/blobs/uploads/upload-1":
+			switch r.Method {
+			case http.MethodGet:
+				w.Header().Set("Location", r.URL.Path)
+				w.Header().Set("Range", "0-"+strconv.Itoa(len(received)-1))
+				w.WriteHeader(http.StatusNoContent)
+			case http.MethodPatch:
+				if !interrupted {
+					// Receive half of the blob, and drop the connection.
+					interrupted = true
+					buf := make([]byte, len(blob)/2)
+					_, err := io.ReadFull(r.Body, buf)
+					assert.Check(t, err)
+					received = append(received, buf...)
+					conn, _, err := w.(http.Hijacker).Hijack()
+					assert.Check(t, err)
+					_ = conn.Close()
+					return
+				}
+				resumedFrom = r.Header.Get("Content-Range")
+				body, err := io.ReadAll(r.Body)
+				assert.Check(t, err)
+				received = append(received, body...)
+				w.Header().Set("Location", r.URL.Path)
+				w.Header().Set("Range", "0-"+strconv.Itoa(len(received)-1))
+				w.WriteHeader(http.StatusAccepted)
+			case http.MethodPut:
+				assert.Check(t, is.Equal(r.URL.Query().Get("digest"), dgst.String()))
+				uploaded = digest.FromBytes(received) == dgst
+				w.Header().Set("Docker-Content-Digest", dgst.String())
+				w.WriteHeader(http.StatusCreated)
+			case http.MethodDelete:
+				t.Error("interrupted upload should not be canceled")
+				w.WriteHeader(http.StatusNoContent)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	assert.NilError(t, err)
+
+	source, err := reference.ParseNormalizedNamed(u.Host + "/src/app@" + dgst.String())
+	assert.NilError(t, err)
+	target, err := reference.ParseNormalizedNamed(u.Host + "/dst/app")
+	assert.NilError(t, err)
+
+	c := NewRegistryClient(noAuth, "test", false)
+	err = c.CopyBlob(context.Background(), source.(reference.Canonical), target)
+	assert.Check(t, is.ErrorContains(err, "failed to copy blob"))
+	assert.Check(t, !uploaded)
+	targetName, err := reference.WithName("dst/app")
+	assert.NilError(t, err)
+	_, err = os.Stat(uploadSessionFile(targetName, dgst))
+	assert.NilError(t, err, "location of the interrupted upload should be stored")
+
+	err = c.CopyBlob(context.Background(), source.(reference.Canonical), target)
+	assert.NilError(t, err)
+	assert.Check(t, uploaded)
+	assert.Check(t, is.Equal(resumedFrom, fmt.Sprintf("%d-%d", len(blob)/2, len(blob)-1)))
+	_, err = os.Stat(uploadSessionFile(targetName, dgst))
+	assert.Check(t, os.IsNotExist(err), "location of the completed upload should be removed")
+}
+
+func TestDefaultRepositoryEndpoint(t *testing.T) {
+	tests := []struct {
+		ref                string
+		insecure           bool
+		expectedURL        string
+		expectedSkipVerify bool
+	}{
+		{
+			ref:         "registry.example.com/team/app",
+			expectedURL: "https://registry.example.com",
+		},
+		{
+			ref:                "registry.example.com/team/app",
+			insecure:           true,
+			expectedURL:        "https://registry.example.com",
+			expectedSkipVerify: true,
+		},
+		{
+			ref:                "127.0.0.1:5000/team/app",
+			expectedURL:        "http://127.0.0.1:5000",
+			expectedSkipVerify: true,
+		},
+		{
+			ref:                "localhost:5000/team/app",
+			expectedURL:        "http://localhost:5000",
+			expectedSkipVerify: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.ref, func(t *testing.T) {
+			ref, err := reference.ParseNormalizedNamed(tc.ref)
+			assert.NilError(t, err)
+			ep, err := newDefaultRepositoryEndpoint(ref, tc.insecure)
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(ep.BaseURL(), tc.expectedURL))
+			assert.Check(t, is.Equal(ep.endpoint.TLSConfig.InsecureSkipVerify, tc.expectedSkipVerify))
+		})
+	}
+}
+
+// newManifestRegistry returns a plain HTTP registry that serves a single
+// image manifest, and accepts manifests that are pushed to it.
+func newManifestRegistry(t *testing.T) (*url.URL, *int) {
+	t.Helper()
+	config := []byte(`{"architecture":"amd64","os":"linux","rootfs":{"type":"layers","diff_ids":[]}}`)
+	configDigest := digest.FromBytes(config)
+	mfst := []byte(`{"schemaVersion":2,"mediaType":"application/vnd.docker.distribution.manifest.v2+json","config":{"mediaType":"application/vnd.docker.container.image.v1+json","size":` + strconv.Itoa(len(config)) + `,"digest":"` + configDigest.String() + `"},"layers":[]}`)
+	mfstDigest := digest.FromBytes(mfst)
+
+	var pushed int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Docker-Distribution-API-Version", "registry/2.0")
+		switch {
+		case r.URL.Path == "/v2/":
+		case r.URL.Path == "/v2/team/app/manifests/latest" && r.Method == http.MethodPut:
+			pushed++
+			w.Header().Set("Docker-Content-Digest", mfstDigest.String())
+			w.WriteHeader(http.StatusCreated)
+		case r.URL.Path == "/v2/team/app/manifests/latest" || r.URL.Path == "/v2/team/app/manifests/"+mfstDigest.String():
+			w.Header().Set("Content-Type", "application/vnd.docker.distribution.manifest.v2+json")
+			w.Header().Set("Content-Length", strconv.Itoa(len(mfst)))
+			w.Header().Set("Docker-Content-Digest", mfstDigest.String())
+			if r.Method == http.MethodGet {
+				_, _ = w.Write(mfst)
+			}
+		case r.URL.Path == "/v2/team/app/blobs/"+configDigest.String():
+			w.Header().Set("Content-Length", strconv.Itoa(len(config)))
+			w.Header().Set("Docker-Content-Digest", configDigest.String())
+			if r.Method == http.MethodGet {
+				_, _ = w.Write(config)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	u, err := url.Parse(srv.URL)
+	assert.NilError(t, err)
+	return u, &pushed
+}
+
+func TestGetManifestInsecureRegistry(t *testing.T) {
+	u, _ := newManifestRegistry(t)
+	ref, err := reference.ParseNormalizedNamed(u.Host + "/team/app:latest")
+	assert.NilError(t, err)
+
+	// The plain HTTP endpoint of a local registry is only used to fetch
+	// manifests with --insecure.
+	_, err = NewRegistryClient(noAuth, "test", false).GetManifest(context.Background(), ref)
+	assert.Check(t, is.ErrorContains(err, "no such manifest"))
+
+	m, err := NewRegistryClient(noAuth, "test", true).GetManifest(context.Background(), ref)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(m.Descriptor.Platform.Architecture, "amd64"))
+}
+
+func TestPutManifestInsecureRegistry(t *testing.T) {
+	u, pushed := newManifestRegistry(t)
+	ref, err := reference.ParseNormalizedNamed(u.Host + "/team/app:latest")
+	assert.NilError(t, err)
+
+	c := NewRegistryClient(noAuth, "test", true)
+	mfst, err := c.GetRawManifest(context.Background(), ref)
+	assert.NilError(t, err)
+
+	// Manifests are pushed to the plain HTTP endpoint of a local registry,
+	// with or without --insecure.
+	for _, insecure := range []bool{false, true} {
+		_, err := NewRegistryClient(noAuth, "test", insecure).PutManifest(context.Background(), ref, mfst)
+		assert.NilError(t, err)
+	}
+	assert.Check(t, is.Equal(*pushed, 2))
+}
+
+func TestResolveDigest(t *testing.T) {
+	u, _ := newManifestRegistry(t)
+	ref, err := reference.ParseNormalizedNamed(u.Host + "/team/app:latest")
+	assert.NilError(t, err)
+	c := NewRegistryClient(noAuth, "test", true)
+
+	dgst, err := ResolveDigest(context.Background(), c, ref)
+	assert.NilError(t, err)
+	canonical, err := reference.WithDigest(reference.TrimNamed(ref), dgst)
+	assert.NilError(t, err)
+	mfst, err := c.GetRawManifest(context.Background(), canonical)
+	assert.NilError(t, err)
+	_, payload, err := mfst.Payload()
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(digest.FromBytes(payload), dgst))
+
+	// The digest of a canonical reference is returned without fetching
+	// the manifest.
+	other, err := reference.WithDigest(reference.TrimNamed(ref), digest.FromString("other"))
+	assert.NilError(t, err)
+	dgst, err = ResolveDigest(context.Background(), c, other)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(dgst, digest.FromString("other")))
+}
@@ -27,9 +27,13 @@ func (r repositoryEndpoint) BaseURL() string {
 	return r.endpoint.URL.String()
 }
 
+// newDefaultRepositoryEndpoint returns the endpoint of the repository of the
+// reference. If insecure is set, the TLS certificate of the registry is not
+// verified, but a plain HTTP endpoint is only used for registries that are
+// insecure by default (see [getDefaultEndpoint]).
 func newDefaultRepositoryEndpoint(ref reference.Named, insecure bool) (repositoryEndpoint, error) {
 	indexInfo := registry.NewIndexInfo(ref)
-	endpoint, err := getDefaultEndpoint(ref, !indexInfo.Secure)
+	endpoint, err := getDefaultEndpoint(reference.Domain(ref), !indexInfo.Secure)
 	if err != nil {
 		return repositoryEndpoint{}, err
 	}
@@ -43,12 +47,22 @@ func newDefaultRepositoryEndpoint(ref reference.Named, insecure bool) (repositor
 	}, nil
 }
 
-func getDefaultEndpoint(repoName reference.Named, insecure bool) (registry.APIEndpoint, error) {
-	registryService, err := registry.NewService(registry.ServiceOptions{})
+// getDefaultEndpoint returns the endpoint of the registry with the given
+// hostname. For registries that are insecure by default, which are the
+// registries on a loopback address, such as a local registry:2 server, the
+// plain HTTP endpoint is returned.
+func getDefaultEndpoint(hostname string, insecure bool) (registry.APIEndpoint, error) {
+	// The registry must be configured as insecure to get its plain HTTP
+	// endpoint.
+	var serviceOpts registry.ServiceOptions
+	if insecure {
+		serviceOpts.InsecureRegistries = []string{hostname}
+	}
+	registryService, err := registry.NewService(serviceOpts)
 	if err != nil {
 		return registry.APIEndpoint{}, err
 	}
-	endpoints, err := registryService.Endpoints(context.TODO(), reference.Domain(repoName))
+	endpoints, err := registryService.Endpoints(context.TODO(), hostname)
 	if err != nil {
 		return registry.APIEndpoint{}, err
 	}
@@ -65,7 +79,7 @@ func getDefaultEndpoint(repoName reference.Named, insecure bool) (registry.APIEn
 }
 
 // getHTTPTransport builds a transport for use in communicating with a registry
-func getHTTPTransport(authConfig registrytypes.AuthConfig, endpoint registry.APIEndpoint, repoName, userAgent string, actions []string) (http.RoundTripper, error) {
+func getHTTPTransport(authConfig registrytypes.AuthConfig, endpoint registry.APIEndpoint, scope auth.Scope, userAgent string) (http.RoundTripper, error) {
 	// get the http transport, this will be used in a client to upload manifest
 	base := &http.Transport{
 		Proxy: http.ProxyFromEnvironment,
@@ -88,11 +102,12 @@ func getHTTPTransport(authConfig registrytypes.AuthConfig, endpoint registry.API
 		passThruTokenHandler := &existingTokenHandler{token: authConfig.RegistryToken}
 		modifiers = append(modifiers, auth.NewAuthorizer(challengeManager, passThruTokenHandler))
 	} else {
-		if len(actions) == 0 {
-			actions = []string{"pull"}
-		}
 		creds := &staticCredentialStore{authConfig: &authConfig}
-		tokenHandler := auth.NewTokenHandler(authTransport, creds, repoName, actions...)
+		tokenHandler := auth.NewTokenHandlerWithOptions(auth.TokenHandlerOptions{
+			Transport:   authTransport,
+			Credentials: creds,
+			Scopes:      []auth.Scope{scope},
+		})
 		basicHandler := auth.NewBasicHandler(creds)
 		modifiers = append(modifiers, auth.NewAuthorizer(challengeManager, tokenHandler, basicHandler))
 	}
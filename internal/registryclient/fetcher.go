@@ -95,6 +95,23 @@ func pullManifestOCISchema(ctx context.Context, ref reference.Named, repo distri
 	if err != nil {
 		return types.ImageManifest{}, err
 	}
+
+	// The artifactType field is not known to the ocischema package.
+	_, raw, err := mfst.Payload()
+	if err != nil {
+		return types.ImageManifest{}, err
+	}
+	var ociManifest ocispec.Manifest
+	if err := json.Unmarshal(raw, &ociManifest); err != nil {
+		return types.ImageManifest{}, err
+	}
+	manifestDesc.ArtifactType = ociManifest.ArtifactType
+
+	// Artifacts don't have an image config to get the platform from.
+	if configType := mfst.Target().MediaType; configType != ocispec.MediaTypeImageConfig && configType != schema2.MediaTypeImageConfig {
+		return types.NewOCIImageManifest(ref, manifestDesc, &mfst), nil
+	}
+
 	configJSON, err := pullManifestSchemaV2ImageConfig(ctx, mfst.Target().Digest, repo)
 	if err != nil {
 		return types.ImageManifest{}, err
@@ -157,8 +174,19 @@ func pullManifestList(ctx context.Context, ref reference.Named, repo distributio
 		return nil, err
 	}
 
+	// The manifestlist package does not preserve the artifactType field of
+	// the descriptors of an OCI image index.
+	_, raw, err := mfstList.Payload()
+	if err != nil {
+		return nil, err
+	}
+	var index ocispec.Index
+	if err := json.Unmarshal(raw, &index); err != nil {
+		return nil, err
+	}
+
 	infos := make([]types.ImageManifest, 0, len(mfstList.Manifests))
-	for _, manifestDescriptor := range mfstList.Manifests {
+	for i, manifestDescriptor := range mfstList.Manifests {
 		manSvc, err := repo.Manifests(ctx)
 		if err != nil {
 			return nil, err
@@ -189,6 +217,10 @@ func pullManifestList(ctx context.Context, ref reference.Named, repo distributio
 		// Replace platform from config
 		p := manifestDescriptor.Platform
 		imageManifest.Descriptor.Platform = types.OCIPlatform(&p)
+		imageManifest.Descriptor.Annotations = manifestDescriptor.Annotations
+		if i < len(index.Manifests) && index.Manifests[i].ArtifactType != "" {
+			imageManifest.Descriptor.ArtifactType = index.Manifests[i].ArtifactType
+		}
 
 		infos = append(infos, imageManifest)
 	}
@@ -0,0 +1,217 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package registryclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli/config"
+	v2 "github.com/docker/distribution/registry/api/v2"
+	distributionclient "github.com/docker/distribution/registry/client"
+	"github.com/opencontainers/go-digest"
+)
+
+// uploadSessionDir is the directory, inside the CLIs [config.Dir], in which
+// the locations of interrupted blob uploads are stored, so that a later copy
+// of the blob resumes the upload instead of uploading the blob again.
+const uploadSessionDir = "uploads"
+
+// uploadSessionFile returns the file that stores the location of an
+// interrupted upload of the blob to the repository.
+func uploadSessionFile(repo reference.Named, dgst digest.Digest) string {
+	key := digest.FromString(repo.Name() + "@" + dgst.String())
+	return filepath.Join(config.Dir(), uploadSessionDir, key.Encoded())
+}
+
+// blobUpload is an upload session of a blob, which can be resumed after it
+// was interrupted, as described in the distribution specification:
+// https://distribution.github.io/distribution/spec/api/#resumable-upload
+type blobUpload struct {
+	client   *http.Client
+	repo     reference.Named
+	location *url.URL
+
+	// offset is the number of bytes of the blob that were received by the
+	// registry.
+	offset int64
+}
+
+// newBlobUpload resumes the interrupted upload of the blob to the repository,
+// if any, or starts a new upload.
+func newBlobUpload(ctx context.Context, transport http.RoundTripper, baseURL string, repo reference.Named, dgst digest.Digest) (*blobUpload, error) {
+	u := &blobUpload{
+		client: &http.Client{Transport: transport},
+		repo:   repo,
+	}
+	if location, err := os.ReadFile(uploadSessionFile(repo, dgst)); err == nil {
+		if err := u.resume(ctx, strings.TrimSpace(string(location))); err == nil {
+			return u, nil
+		}
+		u.forget(dgst)
+	}
+
+	ub, err := v2.NewURLBuilderFromString(baseURL, false)
+	if err != nil {
+		return nil, err
+	}
+	uploadURL, err := ub.BuildBlobUploadURL(repo)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uploadURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		return nil, distributionclient.HandleErrorResponse(resp)
+	}
+	return u, u.update(resp)
+}
+
+// resume gets the status of the upload at the location, to continue the
+// upload after the bytes that were received by the registry.
+func (u *blobUpload) resume(ctx context.Context, location string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		return distributionclient.HandleErrorResponse(resp)
+	}
+	return u.update(resp)
+}
+
+// write uploads the content of the blob from the offset of the upload. The
+// reader must be at the offset of the upload. An [interruptedError] is
+// returned if the upload can be resumed.
+func (u *blobUpload) write(ctx context.Context, r io.Reader, size int64) error {
+	if u.offset >= size {
+		return nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, u.location.String(), io.LimitReader(r, size-u.offset))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size - u.offset
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Range", strconv.FormatInt(u.offset, 10)+"-"+strconv.FormatInt(size-1, 10))
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return interruptedError{err}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		return distributionclient.HandleErrorResponse(resp)
+	}
+	return u.update(resp)
+}
+
+// interruptedError is returned when an upload was interrupted, for example
+// because the connection failed, or the copy was canceled.
+type interruptedError struct{ error }
+
+func (e interruptedError) Unwrap() error { return e.error }
+
+// commit completes the upload of the blob.
+func (u *blobUpload) commit(ctx context.Context, dgst digest.Digest) error {
+	commitURL := *u.location
+	query := commitURL.Query()
+	query.Set("digest", dgst.String())
+	commitURL.RawQuery = query.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, commitURL.String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return distributionclient.HandleErrorResponse(resp)
+	}
+	u.forget(dgst)
+	return nil
+}
+
+// cancel cancels the upload, so that it is not resumed.
+func (u *blobUpload) cancel(ctx context.Context, dgst digest.Digest) {
+	u.forget(dgst)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, u.location.String(), nil)
+	if err != nil {
+		return
+	}
+	if resp, err := u.client.Do(req); err == nil {
+		_ = resp.Body.Close()
+	}
+}
+
+// remember stores the location of the upload, so that it is resumed by a
+// later copy of the blob.
+func (u *blobUpload) remember(dgst digest.Digest) error {
+	file := uploadSessionFile(u.repo, dgst)
+	if err := os.MkdirAll(filepath.Dir(file), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(file, []byte(u.location.String()), 0o600)
+}
+
+// forget removes the stored location of the upload, if any.
+func (u *blobUpload) forget(dgst digest.Digest) {
+	_ = os.Remove(uploadSessionFile(u.repo, dgst))
+}
+
+// update updates the location and offset of the upload from the response of
+// the registry.
+func (u *blobUpload) update(resp *http.Response) error {
+	location, err := resp.Request.URL.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		return err
+	}
+	if resp.Header.Get("Location") == "" {
+		if u.location == nil {
+			return errors.New("missing upload location")
+		}
+		location = u.location
+	}
+	u.location = location
+	u.offset = 0
+	if r := resp.Header.Get("Range"); r != "" {
+		_, end, ok := strings.Cut(r, "-")
+		if !ok {
+			return fmt.Errorf("invalid upload range: %q", r)
+		}
+		n, err := strconv.ParseInt(end, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid upload range: %q", r)
+		}
+		// An empty upload has the range "0-0", as does an upload of a
+		// single byte. Treat it as empty; if a byte was received, the
+		// registry rejects the write, and the upload is started again.
+		if n > 0 {
+			u.offset = n + 1
+		}
+	}
+	return nil
+}